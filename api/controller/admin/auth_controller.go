@@ -22,7 +22,7 @@ func NewAuthController() *AuthController {
 
 // GetCaptchaImage 获取验证码
 func (c *AuthController) GetCaptchaImage(ctx *gin.Context) {
-	captchaResponse := c.authService.GetCaptchaImage()
+	captchaResponse := c.authService.GetCaptchaImage(ctx)
 	response.Success(ctx).SetData("uuid", captchaResponse.Uuid).SetData("img", captchaResponse.Img).SetData("captchaEnabled", captchaResponse.CaptchaEnabled).Json()
 }
 
@@ -37,7 +37,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err := c.authService.Register(&param); err != nil {
+	if err := c.authService.Register(ctx.Request.Context(), &param); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -60,13 +60,66 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		response.Error(ctx).SetCode(400).SetMsg(err.Error()).Json()
 		return
 	}
-	response.Success(ctx).SetData("token", token).Json()
+	c.tokenJson(ctx, token)
+}
+
+// LoginTwoFactor 两步验证登录
+func (c *AuthController) LoginTwoFactor(ctx *gin.Context) {
+	var param dto.TwoFactorLoginRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetCode(400).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.TwoFactorLoginValidator(param); err != nil {
+		response.Error(ctx).SetCode(400).SetMsg(err.Error()).Json()
+		return
+	}
+	token, err := c.authService.LoginTwoFactor(&param, ctx)
+	if err != nil {
+		response.Error(ctx).SetCode(400).SetMsg(err.Error()).Json()
+		return
+	}
+	c.tokenJson(ctx, token)
+}
+
+// SetupTwoFactor 登录时绑定两步验证，角色要求两步验证但尚未绑定时使用
+func (c *AuthController) SetupTwoFactor(ctx *gin.Context) {
+	var param dto.TwoFactorTicketRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetCode(400).SetMsg(err.Error()).Json()
+		return
+	}
+	setup, err := c.authService.SetupTwoFactor(&param, ctx)
+	if err != nil {
+		response.Error(ctx).SetCode(400).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("data", setup).Json()
+}
+
+// RefreshToken 刷新令牌，刷新令牌失效时返回401，需重新登录
+func (c *AuthController) RefreshToken(ctx *gin.Context) {
+	var param dto.RefreshTokenRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetCode(400).SetMsg(err.Error()).Json()
+		return
+	}
+	if param.RefreshToken == "" {
+		response.Error(ctx).SetCode(400).SetMsg("刷新令牌不能为空").Json()
+		return
+	}
+	token, err := c.authService.RefreshToken(&param, ctx)
+	if err != nil {
+		response.Error(ctx).SetCode(401).SetMsg(err.Error()).Json()
+		return
+	}
+	c.tokenJson(ctx, token)
 }
 
 // GetInfo 获取授权信息
 func (c *AuthController) GetInfo(ctx *gin.Context) {
 	authInfo := c.authService.GetAuthInfo(ctx)
-	response.Success(ctx).SetData("user", authInfo.User).SetData("roles", authInfo.Roles).SetData("permissions", authInfo.Permissions).Json()
+	response.Success(ctx).SetData("user", authInfo.User).SetData("roles", authInfo.Roles).SetData("permissions", authInfo.Permissions).SetData("pwdChangeMsg", authInfo.PwdChangeMsg).Json()
 }
 
 // GetRouters 获取当前用户的路由
@@ -84,3 +137,22 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 	}
 	response.Success(ctx).Json()
 }
+
+// tokenJson 输出令牌，sliding模式下仅包含token，需要两步验证时仅包含登录凭证
+func (c *AuthController) tokenJson(ctx *gin.Context, token dto.TokenResponse) {
+	if token.TwoFactorTicket != "" {
+		response.Success(ctx).SetData("twoFactor", true).SetData("ticket", token.TwoFactorTicket).SetData("setup", token.TwoFactorSetup).Json()
+		return
+	}
+	r := response.Success(ctx).SetData("token", token.Token)
+	if token.RefreshToken != "" {
+		r.SetData("refreshToken", token.RefreshToken).SetData("expiresIn", token.ExpiresIn)
+	}
+	if len(token.RecoveryCodes) > 0 {
+		r.SetData("recoveryCodes", token.RecoveryCodes)
+	}
+	if token.PwdChangeMsg != "" {
+		r.SetData("pwdChangeMsg", token.PwdChangeMsg)
+	}
+	r.Json()
+}
@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/service/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CacheController struct {
+	cacheService *admin.CacheService
+}
+
+func NewCacheController() *CacheController {
+	return &CacheController{
+		cacheService: &admin.CacheService{},
+	}
+}
+
+// Info 缓存监控信息
+func (c *CacheController) Info(ctx *gin.Context) {
+	info, err := c.cacheService.Info(ctx)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("data", info).Json()
+}
+
+// ListNames 缓存名称列表
+func (c *CacheController) ListNames(ctx *gin.Context) {
+	response.Success(ctx).SetData("data", c.cacheService.ListNames()).Json()
+}
+
+// ListKeys 缓存键名列表
+func (c *CacheController) ListKeys(ctx *gin.Context) {
+	keys, err := c.cacheService.ListKeys(ctx, ctx.Param("cacheName"))
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("data", keys).Json()
+}
+
+// GetValue 缓存内容
+func (c *CacheController) GetValue(ctx *gin.Context) {
+	value, err := c.cacheService.GetValue(ctx, ctx.Param("cacheName"), ctx.Param("cacheKey"))
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("data", value).Json()
+}
+
+// ClearCacheName 清理缓存名称下的所有键
+func (c *CacheController) ClearCacheName(ctx *gin.Context) {
+	if err := c.cacheService.ClearCacheName(ctx, ctx.Param("cacheName")); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// ClearCacheKey 清理指定键名的缓存
+func (c *CacheController) ClearCacheKey(ctx *gin.Context) {
+	if err := c.cacheService.ClearCacheKey(ctx, ctx.Param("cacheKey")); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// ClearCacheAll 清理全部缓存
+func (c *CacheController) ClearCacheAll(ctx *gin.Context) {
+	if err := c.cacheService.ClearCacheAll(ctx); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
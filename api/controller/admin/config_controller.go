@@ -32,14 +32,14 @@ func (c *ConfigController) List(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	configs, total := c.configService.List(param, true)
+	configs, total := c.configService.List(ctx.Request.Context(), param, true)
 	response.Success(ctx).SetPageData(configs, total).Json()
 }
 
 // Get 参数详情
 func (c *ConfigController) Get(ctx *gin.Context) {
 	configId, _ := strconv.Atoi(ctx.Param("configId"))
-	config := c.configService.Get(configId)
+	config := c.configService.Get(ctx.Request.Context(), configId)
 	response.Success(ctx).SetData("data", config).Json()
 }
 
@@ -55,7 +55,7 @@ func (c *ConfigController) Create(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.configService.Create(dto.SaveConfigRequest{
+	if err := c.configService.Create(ctx.Request.Context(), dto.SaveConfigRequest{
 		ConfigName:  param.ConfigName,
 		ConfigKey:   param.ConfigKey,
 		ConfigValue: param.ConfigValue,
@@ -81,7 +81,7 @@ func (c *ConfigController) Update(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.configService.Update(dto.SaveConfigRequest{
+	if err := c.configService.Update(ctx.Request.Context(), dto.SaveConfigRequest{
 		ConfigId:    param.ConfigId,
 		ConfigName:  param.ConfigName,
 		ConfigKey:   param.ConfigKey,
@@ -103,7 +103,7 @@ func (c *ConfigController) Delete(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.configService.Delete(configIds); err != nil {
+	if err = c.configService.Delete(ctx.Request.Context(), configIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -113,7 +113,7 @@ func (c *ConfigController) Delete(ctx *gin.Context) {
 // ConfigKey 根据配置key获取配置值
 func (c *ConfigController) ConfigKey(ctx *gin.Context) {
 	configKey := ctx.Param("configKey")
-	config := c.configService.GetCacheByConfigKey(configKey)
+	config := c.configService.GetCacheByConfigKey(ctx.Request.Context(), configKey)
 	response.Success(ctx).SetMsg(config.ConfigValue).Json()
 }
 
@@ -125,7 +125,7 @@ func (c *ConfigController) Export(ctx *gin.Context) {
 		return
 	}
 	list := make([]dto.ConfigExportResponse, 0)
-	configs, _ := c.configService.List(param, false)
+	configs, _ := c.configService.List(ctx.Request.Context(), param, false)
 	for _, config := range configs {
 		list = append(list, dto.ConfigExportResponse{
 			ConfigId:    config.ConfigId,
@@ -31,7 +31,7 @@ func (c *DeptController) List(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	deptList := c.deptService.List(param, user.(*dto.UserTokenResponse).UserId)
+	deptList := c.deptService.List(ctx.Request.Context(), param, user.(*dto.UserTokenResponse).UserId)
 	response.Success(ctx).SetData("data", deptList).Json()
 }
 
@@ -40,7 +40,7 @@ func (c *DeptController) ListExclude(ctx *gin.Context) {
 	deptId, _ := strconv.Atoi(ctx.Param("deptId"))
 	data := make([]dto.DeptListResponse, 0)
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	deptList := c.deptService.List(dto.DeptListRequest{}, user.(*dto.UserTokenResponse).UserId)
+	deptList := c.deptService.List(ctx.Request.Context(), dto.DeptListRequest{}, user.(*dto.UserTokenResponse).UserId)
 	for _, dept := range deptList {
 		if dept.DeptId == deptId || utils.Contains(strings.Split(dept.Ancestors, ","), strconv.Itoa(deptId)) {
 			continue
@@ -53,7 +53,7 @@ func (c *DeptController) ListExclude(ctx *gin.Context) {
 // Get 获取部门详情
 func (c *DeptController) Get(ctx *gin.Context) {
 	deptId, _ := strconv.Atoi(ctx.Param("deptId"))
-	dept := c.deptService.Get(deptId)
+	dept := c.deptService.Get(ctx.Request.Context(), deptId)
 	response.Success(ctx).SetData("data", dept).Json()
 }
 
@@ -69,7 +69,7 @@ func (c *DeptController) Create(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.deptService.Create(dto.SaveDeptRequest{
+	if err := c.deptService.Create(ctx.Request.Context(), dto.SaveDeptRequest{
 		ParentId: param.ParentId,
 		DeptName: param.DeptName,
 		OrderNum: param.OrderNum,
@@ -97,7 +97,7 @@ func (c *DeptController) Update(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.deptService.Update(dto.SaveDeptRequest{
+	if err := c.deptService.Update(ctx.Request.Context(), dto.SaveDeptRequest{
 		DeptId:   param.DeptId,
 		ParentId: param.ParentId,
 		DeptName: param.DeptName,
@@ -117,7 +117,7 @@ func (c *DeptController) Update(ctx *gin.Context) {
 // Delete 删除部门
 func (c *DeptController) Delete(ctx *gin.Context) {
 	deptId, _ := strconv.Atoi(ctx.Param("deptId"))
-	if err := c.deptService.Delete(deptId); err != nil {
+	if err := c.deptService.Delete(ctx.Request.Context(), deptId); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
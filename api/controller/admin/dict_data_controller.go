@@ -32,14 +32,14 @@ func (c *DictDataController) List(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	dictDatas, total := c.dictDataService.List(param, true)
+	dictDatas, total := c.dictDataService.List(ctx.Request.Context(), param, true)
 	response.Success(ctx).SetPageData(dictDatas, total).Json()
 }
 
 // Get 获取字典数据详情
 func (c *DictDataController) Get(ctx *gin.Context) {
 	dictCode, _ := strconv.Atoi(ctx.Param("dictCode"))
-	dictData := c.dictDataService.GetByDictCode(dictCode)
+	dictData := c.dictDataService.GetByDictCode(ctx.Request.Context(), dictCode)
 	response.Success(ctx).SetData("data", dictData).Json()
 }
 
@@ -55,7 +55,7 @@ func (c *DictDataController) Create(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.dictDataService.Create(dto.SaveDictDataRequest{
+	if err := c.dictDataService.Create(ctx.Request.Context(), dto.SaveDictDataRequest{
 		DictSort:  param.DictSort,
 		DictLabel: param.DictLabel,
 		DictValue: param.DictValue,
@@ -85,7 +85,7 @@ func (c *DictDataController) Update(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.dictDataService.Update(dto.SaveDictDataRequest{
+	if err := c.dictDataService.Update(ctx.Request.Context(), dto.SaveDictDataRequest{
 		DictCode:  param.DictCode,
 		DictSort:  param.DictSort,
 		DictLabel: param.DictLabel,
@@ -111,7 +111,7 @@ func (c *DictDataController) Delete(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.dictDataService.Delete(dictCodes); err != nil {
+	if err = c.dictDataService.Delete(ctx.Request.Context(), dictCodes); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -121,7 +121,7 @@ func (c *DictDataController) Delete(ctx *gin.Context) {
 // DictDataOptions 根据字典类型查询字典数据
 func (c *DictDataController) DictDataOptions(ctx *gin.Context) {
 	dictType := ctx.Param("dictType")
-	dictDatas := c.dictDataService.GetCacheByDictType(dictType)
+	dictDatas := c.dictDataService.GetCacheByDictType(ctx.Request.Context(), dictType)
 	for key, dictData := range dictDatas {
 		dictDatas[key].Default = dictData.IsDefault == "Y"
 	}
@@ -136,7 +136,7 @@ func (c *DictDataController) Export(ctx *gin.Context) {
 		return
 	}
 	list := make([]dto.DictDataExportResponse, 0)
-	dictDatas, _ := c.dictDataService.List(param, false)
+	dictDatas, _ := c.dictDataService.List(ctx.Request.Context(), param, false)
 	for _, dictData := range dictDatas {
 		list = append(list, dto.DictDataExportResponse{
 			DictCode:  dictData.DictCode,
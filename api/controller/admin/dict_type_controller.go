@@ -32,14 +32,14 @@ func (c *DictTypeController) List(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	dictTypes, total := c.dictTypeService.List(param, true)
+	dictTypes, total := c.dictTypeService.List(ctx.Request.Context(), param, true)
 	response.Success(ctx).SetPageData(dictTypes, total).Json()
 }
 
 // Get 字典类型详情
 func (c *DictTypeController) Get(ctx *gin.Context) {
 	dictId, _ := strconv.Atoi(ctx.Param("dictId"))
-	dictType := c.dictTypeService.Get(dictId)
+	dictType := c.dictTypeService.Get(ctx.Request.Context(), dictId)
 	response.Success(ctx).SetData("data", dictType).Json()
 }
 
@@ -55,7 +55,7 @@ func (c *DictTypeController) Create(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.dictTypeService.Create(dto.SaveDictTypeRequest{
+	if err := c.dictTypeService.Create(ctx.Request.Context(), dto.SaveDictTypeRequest{
 		DictName: param.DictName,
 		DictType: param.DictType,
 		Status:   param.Status,
@@ -80,7 +80,7 @@ func (c *DictTypeController) Update(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.dictTypeService.Update(dto.SaveDictTypeRequest{
+	if err := c.dictTypeService.Update(ctx.Request.Context(), dto.SaveDictTypeRequest{
 		DictId:   param.DictId,
 		DictName: param.DictName,
 		DictType: param.DictType,
@@ -101,7 +101,7 @@ func (c *DictTypeController) Delete(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.dictTypeService.Delete(dictIds); err != nil {
+	if err = c.dictTypeService.Delete(ctx.Request.Context(), dictIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -110,7 +110,7 @@ func (c *DictTypeController) Delete(ctx *gin.Context) {
 
 // DictOptions 获取字典选择框列表
 func (c *DictTypeController) DictOptions(ctx *gin.Context) {
-	dictTypes, _ := c.dictTypeService.List(dto.DictTypeListRequest{
+	dictTypes, _ := c.dictTypeService.List(ctx.Request.Context(), dto.DictTypeListRequest{
 		Status: "0",
 	}, false)
 	response.Success(ctx).SetData("data", dictTypes).Json()
@@ -124,7 +124,7 @@ func (c *DictTypeController) Export(ctx *gin.Context) {
 		return
 	}
 	list := make([]dto.DictTypeExportResponse, 0)
-	dictTypes, _ := c.dictTypeService.List(param, false)
+	dictTypes, _ := c.dictTypeService.List(ctx.Request.Context(), param, false)
 	for _, dictType := range dictTypes {
 		list = append(list, dto.DictTypeExportResponse{
 			DictId:   dictType.DictId,
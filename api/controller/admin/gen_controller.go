@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"encoding/json"
+	"github.com/hugo8680/goat/api/validator/admin"
+	"github.com/hugo8680/goat/common/constant/auth"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/model/dto"
+	adminService "github.com/hugo8680/goat/service/admin"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GenController struct {
+	genService *adminService.GenService
+}
+
+func NewGenController() *GenController {
+	return &GenController{
+		genService: &adminService.GenService{},
+	}
+}
+
+// List 代码生成业务表列表
+func (c *GenController) List(ctx *gin.Context) {
+	var param dto.GenTableListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	tables, total := c.genService.List(ctx.Request.Context(), param, true)
+	response.Success(ctx).SetPageData(tables, total).Json()
+}
+
+// DbList 数据库表列表
+func (c *GenController) DbList(ctx *gin.Context) {
+	var param dto.DbTableListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	tables, total := c.genService.ListDbTables(ctx.Request.Context(), param, true)
+	response.Success(ctx).SetPageData(tables, total).Json()
+}
+
+// Get 代码生成业务表详情
+func (c *GenController) Get(ctx *gin.Context) {
+	tableId, _ := strconv.Atoi(ctx.Param("tableId"))
+	table := c.genService.Get(ctx.Request.Context(), tableId)
+	response.Success(ctx).SetData("data", map[string]interface{}{
+		"info":   table,
+		"rows":   table.Columns,
+		"tables": c.genService.ListAll(ctx.Request.Context()),
+	}).Json()
+}
+
+// ColumnList 代码生成业务表字段列表
+func (c *GenController) ColumnList(ctx *gin.Context) {
+	tableId, _ := strconv.Atoi(ctx.Param("tableId"))
+	columns := c.genService.ListColumns(ctx.Request.Context(), tableId)
+	response.Success(ctx).SetPageData(columns, len(columns)).Json()
+}
+
+// Import 导入表结构
+func (c *GenController) Import(ctx *gin.Context) {
+	var param dto.ImportGenTableRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if param.Tables == "" {
+		response.Error(ctx).SetMsg("请选择要导入的表").Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	if err := c.genService.Import(ctx.Request.Context(), strings.Split(param.Tables, ","), user.(*dto.UserTokenResponse).UserName); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Update 修改代码生成业务表
+func (c *GenController) Update(ctx *gin.Context) {
+	var param dto.UpdateGenTableRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.UpdateGenTableValidator(param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	options, _ := json.Marshal(map[string]int{
+		"parentMenuId": param.ParentMenuId,
+	})
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	userName := user.(*dto.UserTokenResponse).UserName
+	columns := make([]dto.SaveGenTableColumnRequest, 0, len(param.Columns))
+	for _, column := range param.Columns {
+		columns = append(columns, dto.SaveGenTableColumnRequest{
+			ColumnId:      column.ColumnId,
+			ColumnComment: column.ColumnComment,
+			GoType:        column.GoType,
+			GoField:       column.GoField,
+			IsRequired:    column.IsRequired,
+			IsInsert:      column.IsInsert,
+			IsEdit:        column.IsEdit,
+			IsList:        column.IsList,
+			IsQuery:       column.IsQuery,
+			QueryType:     column.QueryType,
+			HtmlType:      column.HtmlType,
+			DictType:      column.DictType,
+			UpdateBy:      userName,
+		})
+	}
+	if err := c.genService.Update(ctx.Request.Context(), dto.SaveGenTableRequest{
+		TableId:        param.TableId,
+		TableComment:   param.TableComment,
+		ClassName:      param.ClassName,
+		TplCategory:    param.TplCategory,
+		PackageName:    param.PackageName,
+		ModuleName:     param.ModuleName,
+		BusinessName:   param.BusinessName,
+		FunctionName:   param.FunctionName,
+		FunctionAuthor: param.FunctionAuthor,
+		GenType:        param.GenType,
+		GenPath:        param.GenPath,
+		Options:        string(options),
+		UpdateBy:       userName,
+		Remark:         param.Remark,
+	}, columns); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Delete 删除代码生成业务表
+func (c *GenController) Delete(ctx *gin.Context) {
+	tableIds, err := utils.StringToIntSlice(ctx.Param("tableIds"), ",")
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err = c.genService.Delete(ctx.Request.Context(), tableIds); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// SyncDb 同步数据库表结构
+func (c *GenController) SyncDb(ctx *gin.Context) {
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	if err := c.genService.SyncDb(ctx.Request.Context(), ctx.Param("tableName"), user.(*dto.UserTokenResponse).UserName); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Preview 预览代码
+func (c *GenController) Preview(ctx *gin.Context) {
+	tableId, _ := strconv.Atoi(ctx.Param("tableId"))
+	files, err := c.genService.Preview(ctx.Request.Context(), tableId)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("data", files).Json()
+}
+
+// Download 下载代码
+func (c *GenController) Download(ctx *gin.Context) {
+	c.download(ctx, []string{ctx.Param("tableName")})
+}
+
+// BatchDownload 批量下载代码
+func (c *GenController) BatchDownload(ctx *gin.Context) {
+	tables := ctx.Query("tables")
+	if tables == "" {
+		response.Error(ctx).SetMsg("请选择要生成的表").Json()
+		return
+	}
+	c.download(ctx, strings.Split(tables, ","))
+}
+
+// GenCode 生成代码到自定义路径，文件已存在时需传overwrite=true确认覆盖
+func (c *GenController) GenCode(ctx *gin.Context) {
+	if err := c.genService.GenCode(ctx.Request.Context(), ctx.Param("tableName"), ctx.Query("overwrite") == "true"); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// download 以zip压缩包的形式输出代码
+func (c *GenController) download(ctx *gin.Context, tableNames []string) {
+	data, err := c.genService.Download(ctx.Request.Context(), tableNames)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	ctx.Header("Content-Disposition", "attachment; filename=\"goat.zip\"")
+	ctx.Data(http.StatusOK, "application/octet-stream; charset=UTF-8", data)
+}
@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"github.com/hugo8680/goat/api/validator/admin"
+	"github.com/hugo8680/goat/common/constant/auth"
+	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/model/dto"
+	adminService "github.com/hugo8680/goat/service/admin"
+	"strconv"
+	"time"
+
+	"gitee.com/hanshuangjianke/go-excel/excel"
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	jobService *adminService.JobService
+}
+
+func NewJobController() *JobController {
+	return &JobController{
+		jobService: &adminService.JobService{},
+	}
+}
+
+// List 定时任务列表
+func (c *JobController) List(ctx *gin.Context) {
+	var param dto.JobListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	jobs, total := c.jobService.List(ctx.Request.Context(), param, true)
+	response.Success(ctx).SetPageData(jobs, total).Json()
+}
+
+// Get 定时任务详情
+func (c *JobController) Get(ctx *gin.Context) {
+	jobId, _ := strconv.Atoi(ctx.Param("jobId"))
+	job := c.jobService.Get(ctx.Request.Context(), jobId)
+	response.Success(ctx).SetData("data", job).Json()
+}
+
+// Create 新增定时任务
+func (c *JobController) Create(ctx *gin.Context) {
+	var param dto.CreateJobRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.CreateJobValidator(param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	if err := c.jobService.Create(ctx.Request.Context(), dto.SaveJobRequest{
+		JobName:        param.JobName,
+		JobGroup:       param.JobGroup,
+		InvokeTarget:   param.InvokeTarget,
+		CronExpression: param.CronExpression,
+		MisfirePolicy:  param.MisfirePolicy,
+		Concurrent:     param.Concurrent,
+		Status:         param.Status,
+		CreateBy:       user.(*dto.UserTokenResponse).UserName,
+		Remark:         param.Remark,
+	}); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Update 更新定时任务
+func (c *JobController) Update(ctx *gin.Context) {
+	var param dto.UpdateJobRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.UpdateJobValidator(param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	if err := c.jobService.Update(ctx.Request.Context(), dto.SaveJobRequest{
+		JobId:          param.JobId,
+		JobName:        param.JobName,
+		JobGroup:       param.JobGroup,
+		InvokeTarget:   param.InvokeTarget,
+		CronExpression: param.CronExpression,
+		MisfirePolicy:  param.MisfirePolicy,
+		Concurrent:     param.Concurrent,
+		Status:         param.Status,
+		UpdateBy:       user.(*dto.UserTokenResponse).UserName,
+		Remark:         param.Remark,
+	}); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Delete 删除定时任务
+func (c *JobController) Delete(ctx *gin.Context) {
+	jobIds, err := utils.StringToIntSlice(ctx.Param("jobIds"), ",")
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err = c.jobService.Delete(ctx.Request.Context(), jobIds); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// ChangeStatus 修改定时任务状态（暂停、恢复）
+func (c *JobController) ChangeStatus(ctx *gin.Context) {
+	var param dto.UpdateJobRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.ChangeJobStatusValidator(param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	if err := c.jobService.ChangeStatus(ctx.Request.Context(), dto.SaveJobRequest{
+		JobId:    param.JobId,
+		Status:   param.Status,
+		UpdateBy: user.(*dto.UserTokenResponse).UserName,
+	}); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Run 立即执行一次定时任务
+func (c *JobController) Run(ctx *gin.Context) {
+	var param dto.UpdateJobRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := c.jobService.Run(ctx.Request.Context(), param.JobId); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Export 数据导出
+func (c *JobController) Export(ctx *gin.Context) {
+	var param dto.JobListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	list := make([]dto.JobExportResponse, 0)
+	jobs, _ := c.jobService.List(ctx.Request.Context(), param, false)
+	for _, job := range jobs {
+		list = append(list, dto.JobExportResponse{
+			JobId:          job.JobId,
+			JobName:        job.JobName,
+			JobGroup:       job.JobGroup,
+			InvokeTarget:   job.InvokeTarget,
+			CronExpression: job.CronExpression,
+			MisfirePolicy:  job.MisfirePolicy,
+			Concurrent:     job.Concurrent,
+			Status:         job.Status,
+		})
+	}
+	file, err := excel.NormalDynamicExport("Sheet1", "", "", false, false, list, nil)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	excel.DownLoadExcel("job_"+time.Now().Format(datetime.DATETIME_FORMAT2), ctx.Writer, file)
+}
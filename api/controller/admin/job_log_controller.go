@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/model/dto"
+	"github.com/hugo8680/goat/service/admin"
+	"strconv"
+	"time"
+
+	"gitee.com/hanshuangjianke/go-excel/excel"
+	"github.com/gin-gonic/gin"
+)
+
+type JobLogController struct {
+	jobLogService *admin.JobLogService
+}
+
+func NewJobLogController() *JobLogController {
+	return &JobLogController{
+		jobLogService: &admin.JobLogService{},
+	}
+}
+
+// List 调度日志列表
+func (c *JobLogController) List(ctx *gin.Context) {
+	var param dto.JobLogListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	jobLogs, total := c.jobLogService.List(ctx.Request.Context(), param, true)
+	response.Success(ctx).SetPageData(jobLogs, total).Json()
+}
+
+// Get 调度日志详情
+func (c *JobLogController) Get(ctx *gin.Context) {
+	jobLogId, _ := strconv.Atoi(ctx.Param("jobLogId"))
+	jobLog := c.jobLogService.Get(ctx.Request.Context(), jobLogId)
+	response.Success(ctx).SetData("data", jobLog).Json()
+}
+
+// Delete 删除调度日志
+func (c *JobLogController) Delete(ctx *gin.Context) {
+	jobLogIds, err := utils.StringToIntSlice(ctx.Param("jobLogIds"), ",")
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err = c.jobLogService.Delete(ctx.Request.Context(), jobLogIds); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Clean 清空调度日志
+func (c *JobLogController) Clean(ctx *gin.Context) {
+	if err := c.jobLogService.Delete(ctx.Request.Context(), nil); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Export 数据导出
+func (c *JobLogController) Export(ctx *gin.Context) {
+	var param dto.JobLogListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	list := make([]dto.JobLogExportResponse, 0)
+	jobLogs, _ := c.jobLogService.List(ctx.Request.Context(), param, false)
+	for _, jobLog := range jobLogs {
+		list = append(list, dto.JobLogExportResponse{
+			JobLogId:      jobLog.JobLogId,
+			JobName:       jobLog.JobName,
+			JobGroup:      jobLog.JobGroup,
+			InvokeTarget:  jobLog.InvokeTarget,
+			JobMessage:    jobLog.JobMessage,
+			Status:        jobLog.Status,
+			ExceptionInfo: jobLog.ExceptionInfo,
+			CreateTime:    jobLog.CreateTime.Format(datetime.DATETIME_FORMAT0),
+		})
+	}
+	file, err := excel.NormalDynamicExport("Sheet1", "", "", false, false, list, nil)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	excel.DownLoadExcel("job_log_"+time.Now().Format(datetime.DATETIME_FORMAT2), ctx.Writer, file)
+}
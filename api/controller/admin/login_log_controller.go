@@ -41,7 +41,7 @@ func (c *LoginLogController) List(ctx *gin.Context) {
 		param.OrderByColumn = "loginTime"
 	}
 	param.OrderByColumn = strings.ToLower(regexp.MustCompile("([A-Z])").ReplaceAllString(param.OrderByColumn, "_${1}"))
-	loginLogs, total := c.loginLogService.List(param, true)
+	loginLogs, total := c.loginLogService.List(ctx.Request.Context(), param, true)
 	response.Success(ctx).SetPageData(loginLogs, total).Json()
 }
 
@@ -52,7 +52,7 @@ func (c *LoginLogController) Delete(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.loginLogService.Delete(infoIds); err != nil {
+	if err = c.loginLogService.Delete(ctx.Request.Context(), infoIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -61,14 +61,14 @@ func (c *LoginLogController) Delete(ctx *gin.Context) {
 
 // Clean 清空登录日志
 func (c *LoginLogController) Clean(ctx *gin.Context) {
-	if err := c.loginLogService.Delete(nil); err != nil {
+	if err := c.loginLogService.Delete(ctx.Request.Context(), nil); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
 	response.Success(ctx).Json()
 }
 
-// Unlock 账户解锁（删除登录错误次数限制10分钟缓存）
+// Unlock 账户解锁（删除账号的登录失败记录及锁定）
 func (c *LoginLogController) Unlock(ctx *gin.Context) {
 	err := c.loginLogService.UnLock(ctx)
 	if err != nil {
@@ -78,6 +78,16 @@ func (c *LoginLogController) Unlock(ctx *gin.Context) {
 	response.Success(ctx).Json()
 }
 
+// UnlockIp IP解锁（删除ip的登录失败记录及锁定）
+func (c *LoginLogController) UnlockIp(ctx *gin.Context) {
+	err := c.loginLogService.UnLockIp(ctx)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
 // Export 数据导出
 func (c *LoginLogController) Export(ctx *gin.Context) {
 	var param dto.LoginLogListRequest
@@ -96,7 +106,7 @@ func (c *LoginLogController) Export(ctx *gin.Context) {
 	}
 	param.OrderByColumn = strings.ToLower(regexp.MustCompile("([A-Z])").ReplaceAllString(param.OrderByColumn, "_${1}"))
 	list := make([]dto.LoginLogExportResponse, 0)
-	loginLogs, _ := c.loginLogService.List(param, false)
+	loginLogs, _ := c.loginLogService.List(ctx.Request.Context(), param, false)
 	for _, loginLog := range loginLogs {
 		list = append(list, dto.LoginLogExportResponse{
 			InfoId:        loginLog.InfoId,
@@ -108,6 +118,7 @@ func (c *LoginLogController) Export(ctx *gin.Context) {
 			Os:            loginLog.Os,
 			Msg:           loginLog.Msg,
 			LoginTime:     loginLog.LoginTime.Format(datetime.DATETIME_FORMAT0),
+			RequestId:     loginLog.RequestId,
 		})
 	}
 	file, err := excel.NormalDynamicExport("Sheet1", "", "", false, false, list, nil)
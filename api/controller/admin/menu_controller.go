@@ -28,20 +28,20 @@ func (c *MenuController) List(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	menus := c.menuService.List(param)
+	menus := c.menuService.List(ctx.Request.Context(), param)
 	response.Success(ctx).SetData("data", menus).Json()
 }
 
 // Get 菜单详情
 func (c *MenuController) Get(ctx *gin.Context) {
 	menuId, _ := strconv.Atoi(ctx.Param("menuId"))
-	menu := c.menuService.Get(menuId)
+	menu := c.menuService.Get(ctx.Request.Context(), menuId)
 	response.Success(ctx).SetData("data", menu).Json()
 }
 
 // Tree 获取菜单下拉树列表
 func (c *MenuController) Tree(ctx *gin.Context) {
-	menus := c.menuService.Tree()
+	menus := c.menuService.Tree(ctx.Request.Context())
 	tree := c.menuService.RemakeTree(menus, 0)
 	response.Success(ctx).SetData("data", tree).Json()
 }
@@ -49,8 +49,8 @@ func (c *MenuController) Tree(ctx *gin.Context) {
 // RoleMenuTree 加载对应角色菜单列表树
 func (c *MenuController) RoleMenuTree(ctx *gin.Context) {
 	roleId, _ := strconv.Atoi(ctx.Param("roleId"))
-	roleHasMenuIds := c.menuService.ListIdsByRoleId(roleId)
-	menus := c.menuService.Tree()
+	roleHasMenuIds := c.menuService.ListIdsByRoleId(ctx.Request.Context(), roleId)
+	menus := c.menuService.Tree(ctx.Request.Context())
 	tree := c.menuService.RemakeTree(menus, 0)
 	response.Success(ctx).SetData("menus", tree).SetData("checkedKeys", roleHasMenuIds).Json()
 }
@@ -67,7 +67,7 @@ func (c *MenuController) Create(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.menuService.Create(dto.SaveMenuRequest{
+	if err := c.menuService.Create(ctx.Request.Context(), dto.SaveMenuRequest{
 		MenuName:  param.MenuName,
 		ParentId:  param.ParentId,
 		OrderNum:  param.OrderNum,
@@ -102,7 +102,7 @@ func (c *MenuController) Update(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.menuService.Update(dto.SaveMenuRequest{
+	if err := c.menuService.Update(ctx.Request.Context(), dto.SaveMenuRequest{
 		MenuId:    param.MenuId,
 		MenuName:  param.MenuName,
 		ParentId:  param.ParentId,
@@ -129,7 +129,7 @@ func (c *MenuController) Update(ctx *gin.Context) {
 // Delete 删除菜单
 func (c *MenuController) Delete(ctx *gin.Context) {
 	menuId, _ := strconv.Atoi(ctx.Param("menuId"))
-	if err := c.menuService.Delete(menuId); err != nil {
+	if err := c.menuService.Delete(ctx.Request.Context(), menuId); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/model/dto"
+	"github.com/hugo8680/goat/service/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OnlineController struct {
+	onlineService *admin.OnlineService
+}
+
+func NewOnlineController() *OnlineController {
+	return &OnlineController{
+		onlineService: &admin.OnlineService{},
+	}
+}
+
+// List 在线用户列表
+func (c *OnlineController) List(ctx *gin.Context) {
+	var param dto.OnlineListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	onlines, err := c.onlineService.List(ctx, param)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetPageData(onlines, len(onlines)).Json()
+}
+
+// ForceLogout 强退用户
+func (c *OnlineController) ForceLogout(ctx *gin.Context) {
+	tokenId := ctx.Param("tokenId")
+	if tokenId == "" {
+		response.Error(ctx).SetMsg("参数错误").Json()
+		return
+	}
+	if err := c.onlineService.ForceLogout(ctx, tokenId); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
@@ -42,7 +42,7 @@ func (c *OperLogController) List(ctx *gin.Context) {
 		param.OrderByColumn = "operTime"
 	}
 	param.OrderByColumn = strings.ToLower(regexp.MustCompile("([A-Z])").ReplaceAllString(param.OrderByColumn, "_${1}"))
-	operLogs, total := c.operLogService.List(param, true)
+	operLogs, total := c.operLogService.List(ctx.Request.Context(), param, true)
 	response.Success(ctx).SetPageData(operLogs, total).Json()
 }
 
@@ -53,7 +53,7 @@ func (c *OperLogController) Delete(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.operLogService.Delete(operIds); err != nil {
+	if err = c.operLogService.Delete(ctx.Request.Context(), operIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -62,7 +62,7 @@ func (c *OperLogController) Delete(ctx *gin.Context) {
 
 // Clean 清空操作日志
 func (c *OperLogController) Clean(ctx *gin.Context) {
-	if err := c.operLogService.Delete(nil); err != nil {
+	if err := c.operLogService.Delete(ctx.Request.Context(), nil); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -87,7 +87,7 @@ func (c *OperLogController) Export(ctx *gin.Context) {
 	}
 	param.OrderByColumn = strings.ToLower(regexp.MustCompile("([A-Z])").ReplaceAllString(param.OrderByColumn, "_${1}"))
 	list := make([]dto.OperLogExportResponse, 0)
-	operLogs, _ := c.operLogService.List(param, false)
+	operLogs, _ := c.operLogService.List(ctx.Request.Context(), param, false)
 	for _, operLog := range operLogs {
 		list = append(list, dto.OperLogExportResponse{
 			OperId:        operLog.OperId,
@@ -106,6 +106,7 @@ func (c *OperLogController) Export(ctx *gin.Context) {
 			ErrorMsg:      operLog.ErrorMsg,
 			OperTime:      operLog.OperTime.Format(datetime.DATETIME_FORMAT0),
 			CostTime:      strconv.Itoa(operLog.CostTime) + "毫秒",
+			RequestId:     operLog.RequestId,
 		})
 	}
 	file, err := excel.NormalDynamicExport("Sheet1", "", "", false, false, list, nil)
@@ -32,14 +32,14 @@ func (c *PostController) List(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	posts, total := c.postService.List(param, true)
+	posts, total := c.postService.List(ctx.Request.Context(), param, true)
 	response.Success(ctx).SetPageData(posts, total).Json()
 }
 
 // Get 岗位详情
 func (c *PostController) Get(ctx *gin.Context) {
 	postId, _ := strconv.Atoi(ctx.Param("postId"))
-	post := c.postService.Get(postId)
+	post := c.postService.Get(ctx.Request.Context(), postId)
 	response.Success(ctx).SetData("data", post).Json()
 }
 
@@ -55,7 +55,7 @@ func (c *PostController) Create(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.postService.Create(dto.SavePostRequest{
+	if err := c.postService.Create(ctx.Request.Context(), dto.SavePostRequest{
 		PostCode: param.PostCode,
 		PostName: param.PostName,
 		PostSort: param.PostSort,
@@ -81,7 +81,7 @@ func (c *PostController) Update(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.postService.Update(dto.SavePostRequest{
+	if err := c.postService.Update(ctx.Request.Context(), dto.SavePostRequest{
 		PostId:   param.PostId,
 		PostCode: param.PostCode,
 		PostName: param.PostName,
@@ -103,7 +103,7 @@ func (c *PostController) Delete(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.postService.Delete(postIds); err != nil {
+	if err = c.postService.Delete(ctx.Request.Context(), postIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error())
 		return
 	}
@@ -118,7 +118,7 @@ func (c *PostController) Export(ctx *gin.Context) {
 		return
 	}
 	list := make([]dto.PostExportResponse, 0)
-	posts, _ := c.postService.List(param, false)
+	posts, _ := c.postService.List(ctx.Request.Context(), param, false)
 	for _, post := range posts {
 		list = append(list, dto.PostExportResponse{
 			PostId:   post.PostId,
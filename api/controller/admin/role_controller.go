@@ -36,14 +36,14 @@ func (c *RoleController) List(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	roles, total := c.roleService.List(param, true)
+	roles, total := c.roleService.List(ctx.Request.Context(), param, true)
 	response.Success(ctx).SetPageData(roles, total).Json()
 }
 
 // Get 角色详情
 func (c *RoleController) Get(ctx *gin.Context) {
 	roleId, _ := strconv.Atoi(ctx.Param("roleId"))
-	role := c.roleService.Get(roleId)
+	role := c.roleService.Get(ctx.Request.Context(), roleId)
 	response.Success(ctx).SetData("data", role).Json()
 }
 
@@ -66,7 +66,7 @@ func (c *RoleController) Create(ctx *gin.Context) {
 	if param.DeptCheckStrictly {
 		deptCheckStrictly = 1
 	}
-	if err := c.roleService.Create(dto.SaveRoleRequest{
+	if err := c.roleService.Create(ctx.Request.Context(), dto.SaveRoleRequest{
 		RoleName:          param.RoleName,
 		RoleKey:           param.RoleKey,
 		RoleSort:          param.RoleSort,
@@ -101,7 +101,7 @@ func (c *RoleController) Update(ctx *gin.Context) {
 		deptCheckStrictly = 1
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.roleService.Update(dto.SaveRoleRequest{
+	if err := c.roleService.Update(ctx.Request.Context(), dto.SaveRoleRequest{
 		RoleId:            param.RoleId,
 		RoleName:          param.RoleName,
 		RoleKey:           param.RoleKey,
@@ -126,14 +126,14 @@ func (c *RoleController) Delete(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	roles := c.roleService.ListByUserId(user.(*dto.UserTokenResponse).UserId)
+	roles := c.roleService.ListByUserId(ctx.Request.Context(), user.(*dto.UserTokenResponse).UserId)
 	for _, role := range roles {
 		if err = admin.RemoveRoleValidator(roleIds, role.RoleId, role.RoleName); err != nil {
 			response.Error(ctx).SetMsg(err.Error()).Json()
 			return
 		}
 	}
-	if err = c.roleService.Delete(roleIds); err != nil {
+	if err = c.roleService.Delete(ctx.Request.Context(), roleIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -152,7 +152,7 @@ func (c *RoleController) ChangeStatus(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.roleService.Update(dto.SaveRoleRequest{
+	if err := c.roleService.Update(ctx.Request.Context(), dto.SaveRoleRequest{
 		RoleId:   param.RoleId,
 		Status:   param.Status,
 		UpdateBy: user.(*dto.UserTokenResponse).UserName,
@@ -166,8 +166,8 @@ func (c *RoleController) ChangeStatus(ctx *gin.Context) {
 // DeptTree 部门树
 func (c *RoleController) DeptTree(ctx *gin.Context) {
 	roleId, _ := strconv.Atoi(ctx.Param("roleId"))
-	roleHasDeptIds := c.deptService.ListIdsByRoleId(roleId)
-	depts := c.deptService.Tree()
+	roleHasDeptIds := c.deptService.ListIdsByRoleId(ctx.Request.Context(), roleId)
+	depts := c.deptService.Tree(ctx.Request.Context())
 	tree := c.deptService.RemakeTree(depts, 0)
 	response.Success(ctx).SetData("depts", tree).SetData("checkedKeys", roleHasDeptIds).Json()
 }
@@ -184,7 +184,7 @@ func (c *RoleController) AssignDataScope(ctx *gin.Context) {
 		deptCheckStrictly = 1
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.roleService.Update(dto.SaveRoleRequest{
+	if err := c.roleService.Update(ctx.Request.Context(), dto.SaveRoleRequest{
 		RoleId:            param.RoleId,
 		DataScope:         param.DataScope,
 		DeptCheckStrictly: &deptCheckStrictly,
@@ -204,7 +204,7 @@ func (c *RoleController) RoleUsersAllocated(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	users, total := c.userService.ListByRoleId(param, user.(*dto.UserTokenResponse).UserId, true)
+	users, total := c.userService.ListByRoleId(ctx.Request.Context(), param, user.(*dto.UserTokenResponse).UserId, true)
 	response.Success(ctx).SetPageData(users, total).Json()
 }
 
@@ -216,7 +216,7 @@ func (c *RoleController) RoleUsersUnAllocated(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	users, total := c.userService.ListByRoleId(param, user.(*dto.UserTokenResponse).UserId, false)
+	users, total := c.userService.ListByRoleId(ctx.Request.Context(), param, user.(*dto.UserTokenResponse).UserId, false)
 	response.Success(ctx).SetPageData(users, total).Json()
 }
 
@@ -232,7 +232,7 @@ func (c *RoleController) AuthUsers(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.roleService.AuthUsers(param.RoleId, userIds); err != nil {
+	if err = c.roleService.AuthUsers(ctx.Request.Context(), param.RoleId, userIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -246,7 +246,7 @@ func (c *RoleController) UnAuthUser(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err := c.roleService.UnAuthUsers(param.RoleId, []int{param.UserId}); err != nil {
+	if err := c.roleService.UnAuthUsers(ctx.Request.Context(), param.RoleId, []int{param.UserId}); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -265,7 +265,7 @@ func (c *RoleController) UnAuthUsers(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err := c.roleService.UnAuthUsers(param.RoleId, userIds); err != nil {
+	if err := c.roleService.UnAuthUsers(ctx.Request.Context(), param.RoleId, userIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -280,7 +280,7 @@ func (c *RoleController) Export(ctx *gin.Context) {
 		return
 	}
 	list := make([]dto.RoleExportResponse, 0)
-	roles, _ := c.roleService.List(param, false)
+	roles, _ := c.roleService.List(ctx.Request.Context(), param, false)
 	for _, role := range roles {
 		list = append(list, dto.RoleExportResponse{
 			RoleId:    role.RoleId,
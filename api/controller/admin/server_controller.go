@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/service/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServerController struct {
+	serverService *admin.ServerService
+}
+
+func NewServerController() *ServerController {
+	return &ServerController{
+		serverService: &admin.ServerService{},
+	}
+}
+
+// Get 服务监控信息
+func (c *ServerController) Get(ctx *gin.Context) {
+	response.Success(ctx).SetData("data", c.serverService.Get(ctx)).Json()
+}
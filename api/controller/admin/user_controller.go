@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"github.com/hugo8680/goat/api/validator/admin"
 	"github.com/hugo8680/goat/common/constant/auth"
 	"github.com/hugo8680/goat/common/password"
@@ -23,29 +24,33 @@ import (
 )
 
 type UserController struct {
-	userService   *adminService.UserService
-	deptService   *adminService.DeptService
-	roleService   *adminService.RoleService
-	postService   *adminService.PostService
-	configService *adminService.ConfigService
-	setting       *config.Setting
+	userService      *adminService.UserService
+	deptService      *adminService.DeptService
+	roleService      *adminService.RoleService
+	postService      *adminService.PostService
+	configService    *adminService.ConfigService
+	securityService  *adminService.SecurityService
+	twoFactorService *adminService.TwoFactorService
+	policyService    *adminService.PasswordPolicyService
 }
 
 func NewUserController() *UserController {
 	return &UserController{
-		userService:   &adminService.UserService{},
-		deptService:   &adminService.DeptService{},
-		roleService:   &adminService.RoleService{},
-		postService:   &adminService.PostService{},
-		configService: &adminService.ConfigService{},
-		setting:       config.GetSetting(),
+		userService:      &adminService.UserService{},
+		deptService:      &adminService.DeptService{},
+		roleService:      &adminService.RoleService{},
+		postService:      &adminService.PostService{},
+		configService:    &adminService.ConfigService{},
+		securityService:  &adminService.SecurityService{},
+		twoFactorService: &adminService.TwoFactorService{},
+		policyService:    &adminService.PasswordPolicyService{},
 	}
 }
 
 // DeptTree 获取部门树
 func (c *UserController) DeptTree(ctx *gin.Context) {
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	deptList := c.deptService.TreeByUserId(user.(*dto.UserTokenResponse).UserId)
+	deptList := c.deptService.TreeByUserId(ctx.Request.Context(), user.(*dto.UserTokenResponse).UserId)
 	tree := c.userService.RemakeTreeByUserId(deptList, 0)
 	response.Success(ctx).SetData("data", tree).Json()
 }
@@ -58,7 +63,7 @@ func (c *UserController) List(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	users, total := c.userService.List(param, user.(*dto.UserTokenResponse).UserId, true)
+	users, total := c.userService.List(ctx.Request.Context(), param, user.(*dto.UserTokenResponse).UserId, true)
 	for key, user := range users {
 		users[key].Dept.DeptName = user.DeptName
 		users[key].Dept.Leader = user.Leader
@@ -71,10 +76,10 @@ func (c *UserController) Get(ctx *gin.Context) {
 	userId, _ := strconv.Atoi(ctx.Param("userId"))
 	r := response.Success(ctx)
 	if userId > 0 {
-		user := c.userService.Get(userId)
-		user.Admin = user.UserId == 1
-		dept := c.deptService.Get(user.DeptId)
-		roles := c.roleService.ListByUserId(user.UserId)
+		user := c.userService.Get(ctx.Request.Context(), userId)
+		user.Admin = c.userService.IsSuperAdmin(ctx.Request.Context(), user.UserId)
+		dept := c.deptService.Get(ctx.Request.Context(), user.DeptId)
+		roles := c.roleService.ListByUserId(ctx.Request.Context(), user.UserId)
 		r.SetData("data", dto.AuthUserInfoResponse{
 			UserDetailResponse: user,
 			Dept:               dept,
@@ -85,17 +90,17 @@ func (c *UserController) Get(ctx *gin.Context) {
 			roleIds = append(roleIds, role.RoleId)
 		}
 		r.SetData("roleIds", roleIds)
-		postIds := c.postService.ListIdsByUserId(user.UserId)
+		postIds := c.postService.ListIdsByUserId(ctx.Request.Context(), user.UserId)
 		r.SetData("postIds", postIds)
 	}
-	roles, _ := c.roleService.List(dto.RoleListRequest{}, false)
-	if userId != 1 {
+	roles, _ := c.roleService.List(ctx.Request.Context(), dto.RoleListRequest{}, false)
+	if !c.userService.IsSuperAdmin(ctx.Request.Context(), userId) {
 		roles = utils.Filter(roles, func(role dto.RoleListResponse) bool {
-			return role.RoleId != 1
+			return !c.securityService.IsSuperAdminRole(role.RoleKey)
 		})
 	}
 	r.SetData("roles", roles)
-	posts, _ := c.postService.List(dto.PostListRequest{}, false)
+	posts, _ := c.postService.List(ctx.Request.Context(), dto.PostListRequest{}, false)
 	r.SetData("posts", posts)
 	r.Json()
 }
@@ -111,18 +116,23 @@ func (c *UserController) Create(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
+	if err := c.policyService.Check(ctx.Request.Context(), 0, param.UserName, param.Password); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.userService.Create(dto.SaveUserRequest{
-		DeptId:      param.DeptId,
-		UserName:    param.UserName,
-		NickName:    param.NickName,
-		Email:       param.Email,
-		PhoneNumber: param.PhoneNumber,
-		Sex:         param.Sex,
-		Password:    password.Generate(param.Password),
-		Status:      param.Status,
-		Remark:      param.Remark,
-		CreateBy:    user.(*dto.UserTokenResponse).UserName,
+	if err := c.userService.Create(ctx.Request.Context(), dto.SaveUserRequest{
+		DeptId:        param.DeptId,
+		UserName:      param.UserName,
+		NickName:      param.NickName,
+		Email:         param.Email,
+		PhoneNumber:   param.PhoneNumber,
+		Sex:           param.Sex,
+		Password:      password.Generate(param.Password),
+		Status:        param.Status,
+		PwdMustChange: c.initPwdMustChange(ctx.Request.Context()),
+		Remark:        param.Remark,
+		CreateBy:      user.(*dto.UserTokenResponse).UserName,
 	}, param.RoleIds, param.PostIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
@@ -142,7 +152,7 @@ func (c *UserController) Update(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.userService.Update(dto.SaveUserRequest{
+	if err := c.userService.Update(ctx.Request.Context(), dto.SaveUserRequest{
 		UserId:      param.UserId,
 		DeptId:      param.DeptId,
 		NickName:    param.NickName,
@@ -171,7 +181,7 @@ func (c *UserController) Delete(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err = c.userService.Delete(userIds); err != nil {
+	if err = c.userService.Delete(ctx.Request.Context(), userIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -190,7 +200,7 @@ func (c *UserController) ChangeStatus(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.userService.Update(dto.SaveUserRequest{
+	if err := c.userService.Update(ctx.Request.Context(), dto.SaveUserRequest{
 		UserId:   param.UserId,
 		Status:   param.Status,
 		UpdateBy: user.(*dto.UserTokenResponse).UserName,
@@ -212,12 +222,31 @@ func (c *UserController) ResetPassword(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
+	if err := c.policyService.Check(ctx.Request.Context(), param.UserId, c.userService.Get(ctx.Request.Context(), param.UserId).UserName, param.Password); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.userService.Update(dto.SaveUserRequest{
-		UserId:   param.UserId,
-		Password: password.Generate(param.Password),
-		UpdateBy: user.(*dto.UserTokenResponse).UserName,
-	}, nil, nil); err != nil {
+	if err := c.userService.ResetPassword(ctx.Request.Context(), param.UserId, password.Generate(param.Password), user.(*dto.UserTokenResponse).UserName); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// ResetTwoFactor 重置用户两步验证，用户需重新绑定
+func (c *UserController) ResetTwoFactor(ctx *gin.Context) {
+	var param dto.ResetTwoFactorRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.ResetTwoFactorValidator(param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	if err := c.twoFactorService.Reset(ctx.Request.Context(), param.UserId, user.(*dto.UserTokenResponse).UserName); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -230,10 +259,10 @@ func (c *UserController) ListRoleByUserId(ctx *gin.Context) {
 	r := response.Success(ctx)
 	var userHasRoleIds []int
 	if userId > 0 {
-		user := c.userService.Get(userId)
-		user.Admin = user.UserId == 1
-		dept := c.deptService.Get(user.DeptId)
-		roles := c.roleService.ListByUserId(user.UserId)
+		user := c.userService.Get(ctx.Request.Context(), userId)
+		user.Admin = c.userService.IsSuperAdmin(ctx.Request.Context(), user.UserId)
+		dept := c.deptService.Get(ctx.Request.Context(), user.DeptId)
+		roles := c.roleService.ListByUserId(ctx.Request.Context(), user.UserId)
 		for _, role := range roles {
 			userHasRoleIds = append(userHasRoleIds, role.RoleId)
 		}
@@ -243,10 +272,10 @@ func (c *UserController) ListRoleByUserId(ctx *gin.Context) {
 			Roles:              roles,
 		})
 	}
-	roles, _ := c.roleService.List(dto.RoleListRequest{}, false)
-	if userId != 1 {
+	roles, _ := c.roleService.List(ctx.Request.Context(), dto.RoleListRequest{}, false)
+	if !c.userService.IsSuperAdmin(ctx.Request.Context(), userId) {
 		roles = utils.Filter(roles, func(role dto.RoleListResponse) bool {
-			return role.RoleId != 1
+			return !c.securityService.IsSuperAdminRole(role.RoleKey)
 		})
 		// 设置角色选中标识，如果角色在用户所拥有的角色列表中设置标识为true
 		for key, role := range roles {
@@ -271,7 +300,7 @@ func (c *UserController) AuthRoles(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	if err := c.userService.AuthRoles(param.UserId, roleIds); err != nil {
+	if err := c.userService.AuthRoles(ctx.Request.Context(), param.UserId, roleIds); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
@@ -305,7 +334,7 @@ func (c *UserController) Import(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	fileName := c.setting.System.UploadPath + file.Filename
+	fileName := config.GetSetting().System.UploadPath + file.Filename
 	// 临时保存文件
 	err = ctx.SaveUploadedFile(file, fileName)
 	if err != nil {
@@ -337,8 +366,9 @@ func (c *UserController) Import(ctx *gin.Context) {
 	var failMsg []string
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
 	authUserName := user.(*dto.UserTokenResponse).UserName
+	initPassword := c.configService.GetCacheByConfigKey(ctx.Request.Context(), "sys.user.initPassword").ConfigValue
 	for _, item := range list {
-		user := c.userService.GetByUserName(item.UserName)
+		user := c.userService.GetByUserName(ctx.Request.Context(), item.UserName)
 		// 插入新用户
 		if user.UserId <= 0 {
 			if err = admin.ImportUserValidator(dto.CreateUserRequest{
@@ -354,16 +384,22 @@ func (c *UserController) Import(ctx *gin.Context) {
 				failMsg = append(failMsg, strconv.Itoa(failNum)+"、账号 "+item.UserName+" 新增失败："+err.Error())
 				continue
 			}
-			if err = c.userService.Create(dto.SaveUserRequest{
-				DeptId:      item.DeptId,
-				UserName:    item.UserName,
-				NickName:    item.NickName,
-				Email:       item.Email,
-				PhoneNumber: item.PhoneNumber,
-				Sex:         item.Sex,
-				Password:    password.Generate(c.configService.GetCacheByConfigKey("sys.user.initPassword").ConfigValue),
-				Status:      item.Status,
-				CreateBy:    authUserName,
+			if err = c.policyService.Check(ctx.Request.Context(), 0, item.UserName, initPassword); err != nil {
+				failNum = failNum + 1
+				failMsg = append(failMsg, strconv.Itoa(failNum)+"、账号 "+item.UserName+" 新增失败：初始密码不符合密码策略，"+err.Error())
+				continue
+			}
+			if err = c.userService.Create(ctx.Request.Context(), dto.SaveUserRequest{
+				DeptId:        item.DeptId,
+				UserName:      item.UserName,
+				NickName:      item.NickName,
+				Email:         item.Email,
+				PhoneNumber:   item.PhoneNumber,
+				Sex:           item.Sex,
+				Password:      password.Generate(initPassword),
+				Status:        item.Status,
+				PwdMustChange: c.initPwdMustChange(ctx.Request.Context()),
+				CreateBy:      authUserName,
 			}, nil, nil); err != nil {
 				failNum = failNum + 1
 				failMsg = append(failMsg, strconv.Itoa(failNum)+"、账号 "+item.UserName+" 新增失败："+err.Error())
@@ -386,7 +422,7 @@ func (c *UserController) Import(ctx *gin.Context) {
 				continue
 			}
 			// 更新已经存在的用户
-			if err = c.userService.Update(dto.SaveUserRequest{
+			if err = c.userService.Update(ctx.Request.Context(), dto.SaveUserRequest{
 				UserId:      user.UserId,
 				DeptId:      item.DeptId,
 				NickName:    item.NickName,
@@ -424,7 +460,7 @@ func (c *UserController) Export(ctx *gin.Context) {
 	}
 	list := make([]dto.UserExportResponse, 0)
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	users, _ := c.userService.List(param, user.(*dto.UserTokenResponse).UserId, false)
+	users, _ := c.userService.List(ctx.Request.Context(), param, user.(*dto.UserTokenResponse).UserId, false)
 	for _, user := range users {
 		loginDate := user.LoginDate.Format("2006-01-02 15:04:05")
 		if user.LoginDate.IsZero() {
@@ -455,19 +491,19 @@ func (c *UserController) Export(ctx *gin.Context) {
 // GetProfile 个人信息
 func (c *UserController) GetProfile(ctx *gin.Context) {
 	curUser, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	user := c.userService.Get(curUser.(*dto.UserTokenResponse).UserId)
-	user.Admin = user.UserId == 1
-	dept := c.deptService.Get(user.DeptId)
-	roles := c.roleService.ListByUserId(user.UserId)
+	user := c.userService.Get(ctx.Request.Context(), curUser.(*dto.UserTokenResponse).UserId)
+	user.Admin = c.userService.IsSuperAdmin(ctx.Request.Context(), user.UserId)
+	dept := c.deptService.Get(ctx.Request.Context(), user.DeptId)
+	roles := c.roleService.ListByUserId(ctx.Request.Context(), user.UserId)
 	data := dto.AuthUserInfoResponse{
 		UserDetailResponse: user,
 		Dept:               dept,
 		Roles:              roles,
 	}
 	// 获取角色组
-	roleGroup := c.roleService.ListNameByUserId(user.UserId)
+	roleGroup := c.roleService.ListNameByUserId(ctx.Request.Context(), user.UserId)
 	// 获取岗位组
-	postGroup := c.postService.ListNamesByUserId(user.UserId)
+	postGroup := c.postService.ListNamesByUserId(ctx.Request.Context(), user.UserId)
 	response.Success(ctx).SetData("data", data).SetData("roleGroup", strings.Join(roleGroup, ",")).SetData("postGroup", strings.Join(postGroup, ",")).Json()
 }
 
@@ -483,7 +519,7 @@ func (c *UserController) UpdateProfile(ctx *gin.Context) {
 		return
 	}
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err := c.userService.Update(dto.SaveUserRequest{
+	if err := c.userService.Update(ctx.Request.Context(), dto.SaveUserRequest{
 		UserId:      user.(*dto.UserTokenResponse).UserId,
 		NickName:    param.NickName,
 		Email:       param.Email,
@@ -508,18 +544,31 @@ func (c *UserController) UpdatePassword(ctx *gin.Context) {
 		return
 	}
 	curUser, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	user := c.userService.Get(curUser.(*dto.UserTokenResponse).UserId)
+	user := c.userService.Get(ctx.Request.Context(), curUser.(*dto.UserTokenResponse).UserId)
 	if !password.Verify(user.Password, param.OldPassword) {
 		response.Error(ctx).SetMsg("旧密码输入错误").Json()
 		return
 	}
-	if err := c.userService.Update(dto.SaveUserRequest{
-		UserId:   user.UserId,
-		Password: password.Generate(param.NewPassword),
+	if err := c.policyService.Check(ctx.Request.Context(), user.UserId, user.UserName, param.NewPassword); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := c.userService.Update(ctx.Request.Context(), dto.SaveUserRequest{
+		UserId:        user.UserId,
+		Password:      password.Generate(param.NewPassword),
+		PwdMustChange: "0",
 	}, nil, nil); err != nil {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
+	// 当前会话解除修改密码限制
+	if authUser := curUser.(*dto.UserTokenResponse); authUser.PwdChange {
+		authUser.PwdChange = false
+		if err := adminService.NewTokenService().Save(ctx.Request.Context(), authUser); err != nil {
+			response.Error(ctx).SetMsg(err.Error()).Json()
+			return
+		}
+	}
 	response.Success(ctx).Json()
 }
 
@@ -556,9 +605,10 @@ func (c *UserController) UpdateAvatar(ctx *gin.Context) {
 		response.Error(ctx).SetMsg(err.Error()).Json()
 		return
 	}
-	imgUrl := "/" + fileResult.UrlPath + fileResult.FileName
+	// 访问地址由存储驱动生成，本地存储为域名加上传路径，对象存储为存储桶或自定义域名地址
+	imgUrl := fileResult.Url
 	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
-	if err = c.userService.Update(dto.SaveUserRequest{
+	if err = c.userService.Update(ctx.Request.Context(), dto.SaveUserRequest{
 		UserId: user.(*dto.UserTokenResponse).UserId,
 		Avatar: imgUrl,
 	}, nil, nil); err != nil {
@@ -567,3 +617,87 @@ func (c *UserController) UpdateAvatar(ctx *gin.Context) {
 	}
 	response.Success(ctx).SetData("imgUrl", imgUrl).Json()
 }
+
+// GetTwoFactor 获取个人两步验证状态
+func (c *UserController) GetTwoFactor(ctx *gin.Context) {
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	response.Success(ctx).SetData("data", c.twoFactorService.Status(ctx.Request.Context(), user.(*dto.UserTokenResponse).UserId)).Json()
+}
+
+// SetupTwoFactor 获取两步验证绑定信息
+func (c *UserController) SetupTwoFactor(ctx *gin.Context) {
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	setup, err := c.twoFactorService.Setup(ctx.Request.Context(), user.(*dto.UserTokenResponse).UserId)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("data", setup).Json()
+}
+
+// EnableTwoFactor 校验验证码后启用两步验证，返回恢复码
+func (c *UserController) EnableTwoFactor(ctx *gin.Context) {
+	var param dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.TwoFactorCodeValidator(param, false); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	recoveryCodes, err := c.twoFactorService.Enable(ctx.Request.Context(), user.(*dto.UserTokenResponse).UserId, param.Code)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("recoveryCodes", recoveryCodes).Json()
+}
+
+// DisableTwoFactor 校验验证码或恢复码后停用两步验证
+func (c *UserController) DisableTwoFactor(ctx *gin.Context) {
+	var param dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.TwoFactorCodeValidator(param, true); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	if err := c.twoFactorService.Disable(ctx.Request.Context(), user.(*dto.UserTokenResponse).UserId, param.Code, param.RecoveryCode); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码
+func (c *UserController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var param dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.TwoFactorCodeValidator(param, false); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+	recoveryCodes, err := c.twoFactorService.RegenerateRecoveryCodes(ctx.Request.Context(), user.(*dto.UserTokenResponse).UserId, param.Code)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).SetData("recoveryCodes", recoveryCodes).Json()
+}
+
+// initPwdMustChange 管理员设置的初始密码是否须在登录后修改
+func (c *UserController) initPwdMustChange(ctx context.Context) string {
+	if c.policyService.MustChangeInitial(ctx) {
+		return "1"
+	}
+	return "0"
+}
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"errors"
+	"github.com/hugo8680/goat/common/constant/auth"
 	"github.com/hugo8680/goat/model/dto"
 )
 
@@ -19,9 +20,6 @@ func RegisterValidator(param dto.RegisterRequest) error {
 	if len(param.Username) < 2 || len(param.Username) > 20 {
 		return errors.New("用户名长度必须在2-20之间")
 	}
-	if len(param.Password) < 5 || len(param.Password) > 20 {
-		return errors.New("密码长度必须在5-20之间")
-	}
 	return nil
 }
 
@@ -33,5 +31,19 @@ func LoginValidator(param dto.LoginRequest) error {
 	if param.Password == "" {
 		return errors.New("密码不能为空")
 	}
+	if param.ClientType != "" && param.ClientType != auth.CLIENT_TYPE_WEB && param.ClientType != auth.CLIENT_TYPE_MOBILE {
+		return errors.New("客户端类型错误")
+	}
+	return nil
+}
+
+// TwoFactorLoginValidator 两步验证登录验证
+func TwoFactorLoginValidator(param dto.TwoFactorLoginRequest) error {
+	if param.Ticket == "" {
+		return errors.New("登录凭证不能为空")
+	}
+	if param.Code == "" && param.RecoveryCode == "" {
+		return errors.New("验证码不能为空")
+	}
 	return nil
 }
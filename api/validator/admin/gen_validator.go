@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"errors"
+	"github.com/hugo8680/goat/common/generator"
+	"github.com/hugo8680/goat/model/dto"
+)
+
+// UpdateGenTableValidator 修改代码生成业务表验证
+func UpdateGenTableValidator(param dto.UpdateGenTableRequest) error {
+	if param.TableId <= 0 {
+		return errors.New("参数错误")
+	}
+	if param.TableComment == "" {
+		return errors.New("请输入表描述")
+	}
+	if param.ClassName == "" {
+		return errors.New("请输入实体类名称")
+	}
+	// 生成信息直接插入到Go代码及sql中，限制格式避免注入代码或生成无法编译的文件
+	if !generator.ValidExported(param.ClassName) {
+		return errors.New("实体类名称须以大写字母开头，且只能包含字母、数字和下划线")
+	}
+	if param.PackageName != "" && !generator.ValidPackage(param.PackageName) {
+		return errors.New("生成包名须以小写字母开头，且只能包含小写字母、数字和下划线")
+	}
+	if param.ModuleName == "" {
+		return errors.New("请输入生成模块名")
+	}
+	if !generator.ValidPackage(param.ModuleName) {
+		return errors.New("生成模块名须以小写字母开头，且只能包含小写字母、数字和下划线")
+	}
+	if param.BusinessName == "" {
+		return errors.New("请输入生成业务名")
+	}
+	// 表名及业务名用于生成文件名，限制字符避免写入到其他目录
+	if !generator.ValidName(param.BusinessName) {
+		return errors.New("生成业务名只能包含字母、数字和下划线，且以字母开头")
+	}
+	if param.TableName != "" && !generator.ValidName(param.TableName) {
+		return errors.New("表名称只能包含字母、数字和下划线，且以字母开头")
+	}
+	if param.FunctionName == "" {
+		return errors.New("请输入生成功能名")
+	}
+	if !generator.ValidText(param.FunctionName) {
+		return errors.New("生成功能名不能包含引号、反斜杠及换行")
+	}
+	if param.FunctionAuthor == "" {
+		return errors.New("请输入作者")
+	}
+	if param.GenType == "1" && param.GenPath == "" {
+		return errors.New("请输入生成路径")
+	}
+	if !generator.ValidPath(param.GenPath) {
+		return errors.New("生成路径须为项目根目录下的相对路径")
+	}
+	for _, column := range param.Columns {
+		if column.GoType == "" || column.GoField == "" {
+			return errors.New("请完善字段信息")
+		}
+		if !generator.ValidExported(column.GoField) {
+			return errors.New("Go属性" + column.GoField + "须以大写字母开头，且只能包含字母、数字和下划线")
+		}
+		if !generator.ValidGoType(column.GoType) {
+			return errors.New("Go类型" + column.GoType + "不支持")
+		}
+		if !generator.ValidText(column.ColumnComment) {
+			return errors.New("字段描述不能包含引号、反斜杠及换行")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"errors"
+	"github.com/hugo8680/goat/common/scheduler"
+	"github.com/hugo8680/goat/model/dto"
+)
+
+// CreateJobValidator 添加定时任务验证
+func CreateJobValidator(param dto.CreateJobRequest) error {
+	if param.JobName == "" {
+		return errors.New("请输入任务名称")
+	}
+	if param.InvokeTarget == "" {
+		return errors.New("请输入调用目标字符串")
+	}
+	if err := scheduler.ValidInvokeTarget(param.InvokeTarget); err != nil {
+		return errors.New("新增任务" + param.JobName + "失败，" + err.Error())
+	}
+	if param.CronExpression == "" {
+		return errors.New("请输入cron执行表达式")
+	}
+	if err := scheduler.ValidCronExpression(param.CronExpression); err != nil {
+		return errors.New("新增任务" + param.JobName + "失败，Cron表达式不正确")
+	}
+	return nil
+}
+
+// UpdateJobValidator 更新定时任务验证
+func UpdateJobValidator(param dto.UpdateJobRequest) error {
+	if param.JobId <= 0 {
+		return errors.New("参数错误")
+	}
+	if param.JobName == "" {
+		return errors.New("请输入任务名称")
+	}
+	if param.InvokeTarget == "" {
+		return errors.New("请输入调用目标字符串")
+	}
+	if err := scheduler.ValidInvokeTarget(param.InvokeTarget); err != nil {
+		return errors.New("修改任务" + param.JobName + "失败，" + err.Error())
+	}
+	if param.CronExpression == "" {
+		return errors.New("请输入cron执行表达式")
+	}
+	if err := scheduler.ValidCronExpression(param.CronExpression); err != nil {
+		return errors.New("修改任务" + param.JobName + "失败，Cron表达式不正确")
+	}
+	return nil
+}
+
+// ChangeJobStatusValidator 修改定时任务状态验证
+func ChangeJobStatusValidator(param dto.UpdateJobRequest) error {
+	if param.JobId <= 0 {
+		return errors.New("参数错误")
+	}
+	if param.Status == "" {
+		return errors.New("请选择任务状态")
+	}
+	return nil
+}
@@ -33,9 +33,6 @@ func UpdateRoleValidator(param dto.UpdateRoleRequest) error {
 
 // RemoveRoleValidator 删除角色验证
 func RemoveRoleValidator(roleIds []int, roleId int, roleName string) error {
-	if utils.Contains(roleIds, 1) {
-		return errors.New("超级管理员无法删除")
-	}
 	if utils.Contains(roleIds, roleId) {
 		return errors.New(roleName + "角色无法删除")
 	}
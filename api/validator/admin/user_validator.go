@@ -71,9 +71,6 @@ func UpdateUserValidator(param dto.UpdateUserRequest) error {
 
 // RemoveUserValidator 删除用户验证
 func RemoveUserValidator(userIds []int, authUserId int) error {
-	if utils.Contains(userIds, 1) {
-		return errors.New("超级管理员无法删除")
-	}
 	if utils.Contains(userIds, authUserId) {
 		return errors.New("当前用户无法删除")
 	}
@@ -118,3 +115,19 @@ func ImportUserValidator(param dto.CreateUserRequest) error {
 	}
 	return nil
 }
+
+// TwoFactorCodeValidator 两步验证码验证，allowRecoveryCode为false时必须使用验证码
+func TwoFactorCodeValidator(param dto.TwoFactorCodeRequest, allowRecoveryCode bool) error {
+	if param.Code == "" && (!allowRecoveryCode || param.RecoveryCode == "") {
+		return errors.New("验证码不能为空")
+	}
+	return nil
+}
+
+// ResetTwoFactorValidator 重置用户两步验证验证
+func ResetTwoFactorValidator(param dto.ResetTwoFactorRequest) error {
+	if param.UserId <= 0 {
+		return errors.New("请选择用户")
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/framework/metrics"
+	"sync"
+	"time"
+)
+
+// FlushFunc 批量写入函数
+type FlushFunc[T any] func(items []T) error
+
+// Options 写入配置
+type Options struct {
+	Size           int           // 队列容量
+	BatchSize      int           // 单批写入条数
+	FlushInterval  time.Duration // 未满一批时的写入间隔
+	Workers        int           // 写入协程数
+	EnqueueTimeout time.Duration // 队列满时的最长等待时间，超时后丢弃
+}
+
+// Writer 异步批量写入器
+//
+// 数据先写入有界队列，由后台协程按批次写入，队列满时等待EnqueueTimeout后丢弃
+type Writer[T any] struct {
+	name    string
+	flush   FlushFunc[T]
+	options Options
+	queue   chan T
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// New 初始化写入器，name用于日志及指标区分
+func New[T any](name string, flush FlushFunc[T]) *Writer[T] {
+	return &Writer[T]{
+		name:  name,
+		flush: flush,
+	}
+}
+
+// Start 启动写入协程
+func (w *Writer[T]) Start(options Options) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started {
+		return fmt.Errorf("写入器%s已启动", w.name)
+	}
+	w.options = withDefaults(options)
+	w.queue = make(chan T, w.options.Size)
+	queue := w.queue
+	if err := metrics.RegisterQueue(w.name, func() float64 {
+		return float64(len(queue))
+	}); err != nil {
+		return err
+	}
+	for i := 0; i < w.options.Workers; i++ {
+		w.wg.Add(1)
+		go w.work()
+	}
+	w.started = true
+	return nil
+}
+
+// Push 写入队列
+//
+// 未启动时同步写入，已关闭或等待超时时丢弃，返回是否写入成功
+func (w *Writer[T]) Push(item T) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.started {
+		w.write([]T{item})
+		return true
+	}
+	if w.closed {
+		w.drop("写入器已关闭")
+		return false
+	}
+	select {
+	case w.queue <- item:
+		return true
+	default:
+	}
+	metrics.ObserveQueueOverflow(w.name)
+	if w.options.EnqueueTimeout <= 0 {
+		w.drop("队列已满")
+		return false
+	}
+	timer := time.NewTimer(w.options.EnqueueTimeout)
+	defer timer.Stop()
+	select {
+	case w.queue <- item:
+		return true
+	case <-timer.C:
+		w.drop("队列已满")
+		return false
+	}
+}
+
+// Close 停止接收数据，等待队列中的数据写入完成或ctx取消
+func (w *Writer[T]) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.started || w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("等待" + w.name + "写入超时，剩余" + fmt.Sprint(len(w.queue)) + "条未写入")
+	}
+}
+
+// work 从队列读取数据，满一批或到达写入间隔时写入，队列关闭后写入剩余数据
+func (w *Writer[T]) work() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+	items := make([]T, 0, w.options.BatchSize)
+	for {
+		select {
+		case item, ok := <-w.queue:
+			if !ok {
+				w.write(items)
+				return
+			}
+			if items = append(items, item); len(items) >= w.options.BatchSize {
+				w.write(items)
+				items = items[:0]
+			}
+		case <-ticker.C:
+			w.write(items)
+			items = items[:0]
+		}
+	}
+}
+
+// write 调用写入函数，捕获写入过程中的panic
+func (w *Writer[T]) write(items []T) {
+	if len(items) == 0 {
+		return
+	}
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		return w.flush(items)
+	}()
+	metrics.ObserveQueueWritten(w.name, len(items), err == nil)
+	if err != nil {
+		logger.Module(w.name).Error("batch write", "count", len(items), "error", err)
+	}
+}
+
+func (w *Writer[T]) drop(reason string) {
+	metrics.ObserveQueueDropped(w.name)
+	logger.Module(w.name).Debug("drop item", "reason", reason)
+}
+
+// withDefaults 未配置的项使用默认值，EnqueueTimeout为0时不等待直接丢弃
+func withDefaults(options Options) Options {
+	if options.Size <= 0 {
+		options.Size = 10000
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+	if options.Workers <= 0 {
+		options.Workers = 2
+	}
+	return options
+}
@@ -2,4 +2,27 @@ package auth
 
 const (
 	CONTEXT_USER_KEY = "ctx_user"
+	// 登录用户名，登录请求体中不含用户名时（如两步验证）由处理函数设置，供登录日志使用
+	CONTEXT_LOGIN_USER_NAME_KEY = "ctx_login_user_name"
+	// 密码校验通过但需两步验证，登录尚未完成，登录日志在两步验证后记录
+	CONTEXT_LOGIN_PENDING_KEY = "ctx_login_pending"
+)
+
+// 令牌模式
+const (
+	TOKEN_MODE_SLIDING = "sliding" // 单一令牌，临期自动续期
+	TOKEN_MODE_REFRESH = "refresh" // 短期访问令牌加刷新令牌
+)
+
+// 会话策略
+const (
+	SESSION_POLICY_MULTIPLE = "multiple" // 不限制会话数
+	SESSION_POLICY_LIMIT    = "limit"    // 限制会话数，超出时踢出最早登录的会话
+	SESSION_POLICY_SINGLE   = "single"   // 仅允许一个会话，新登录踢出之前的会话
+)
+
+// 客户端类型
+const (
+	CLIENT_TYPE_WEB    = "web"
+	CLIENT_TYPE_MOBILE = "mobile"
 )
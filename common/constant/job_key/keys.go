@@ -0,0 +1,31 @@
+package job_key
+
+// 任务状态（正常）
+const JOB_STATUS_NORMAL = "0"
+
+// 任务状态（暂停）
+const JOB_STATUS_PAUSE = "1"
+
+// 计划执行错误策略（默认，同放弃执行）
+const MISFIRE_DEFAULT = "0"
+
+// 计划执行错误策略（立即执行，补偿所有错过的执行）
+const MISFIRE_IGNORE_MISFIRES = "1"
+
+// 计划执行错误策略（执行一次，错过多次也只补偿一次）
+const MISFIRE_FIRE_AND_PROCEED = "2"
+
+// 计划执行错误策略（放弃执行）
+const MISFIRE_DO_NOTHING = "3"
+
+// 是否并发执行（允许）
+const CONCURRENT_ALLOW = "0"
+
+// 是否并发执行（禁止）
+const CONCURRENT_FORBID = "1"
+
+// 执行状态（成功）
+const JOB_LOG_STATUS_SUCCESS = "0"
+
+// 执行状态（失败）
+const JOB_LOG_STATUS_FAIL = "1"
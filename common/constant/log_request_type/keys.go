@@ -1,6 +1,7 @@
 package log_request_type
 
 const (
+	REQUEST_BUSINESS_TYPE_OTHER  = 0 // 其它
 	REQUEST_BUSINESS_TYPE_INSERT = 1 // 新增
 	REQUEST_BUSINESS_TYPE_UPDATE = 2 // 修改
 	REQUEST_BUSINESS_TYPE_DELETE = 3 // 删除
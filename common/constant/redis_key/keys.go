@@ -1,16 +1,38 @@
 package redis_key
 
-import (
-	"github.com/hugo8680/goat/framework/config"
-)
-
-var prefix = config.GetSetting().System.Name
-
 var (
-	CaptchaCodeKey        = prefix + "captcha:code:"          // 验证码
-	LoginPasswordErrorKey = prefix + ":login:password:error:" // 登录账户密码错误次数
-	UserTokenKey          = prefix + ":user:token:"           // 登录用户
-	RepeatSubmitKey       = prefix + ":repeat:submit:"        // 防重提交
-	SysConfigKey          = prefix + ":system:config"         // 配置表数据
-	SysDictKey            = prefix + ":system:dict:data"      // 字典表数据
+	CaptchaCodeKey      string // 验证码
+	LoginFailKey        string // 登录失败记录
+	LoginLockKey        string // 登录锁定
+	LoginBackoffKey     string // 登录锁定次数，用于计算锁定时间
+	UserTokenKey        string // 登录用户
+	UserRefreshTokenKey string // 刷新令牌
+	UserSessionKey      string // 用户会话索引
+	TwoFactorTicketKey  string // 两步验证登录凭证
+	TwoFactorSetupKey   string // 两步验证待绑定密钥
+	TwoFactorUsedKey    string // 两步验证已使用的时间步
+	UserPermissionKey   string // 用户权限及角色
+	RepeatSubmitKey     string // 防重提交
+	RateLimitKey        string // 接口限流
+	SysConfigKey        string // 配置表数据
+	SysDictKey          string // 字典表数据
 )
+
+// Init 使用系统名称作为前缀初始化缓存键名，需在加载配置后调用
+func Init(prefix string) {
+	CaptchaCodeKey = prefix + "captcha:code:"
+	LoginFailKey = prefix + ":login:fail:"
+	LoginLockKey = prefix + ":login:lock:"
+	LoginBackoffKey = prefix + ":login:backoff:"
+	UserTokenKey = prefix + ":user:token:"
+	UserRefreshTokenKey = prefix + ":user:refresh:"
+	UserSessionKey = prefix + ":user:session:"
+	TwoFactorTicketKey = prefix + ":login:2fa:ticket:"
+	TwoFactorSetupKey = prefix + ":user:2fa:setup:"
+	TwoFactorUsedKey = prefix + ":user:2fa:used:"
+	UserPermissionKey = prefix + ":user:permission:"
+	RepeatSubmitKey = prefix + ":repeat:submit:"
+	RateLimitKey = prefix + ":rate:limit:"
+	SysConfigKey = prefix + ":system:config"
+	SysDictKey = prefix + ":system:dict:data"
+}
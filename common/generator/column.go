@@ -0,0 +1,197 @@
+package generator
+
+import (
+	"strings"
+)
+
+// 基础字段，不参与新增、编辑、列表和查询
+var baseColumns = []string{"create_by", "create_time", "update_by", "update_time", "delete_time"}
+
+// NewColumn 根据数据库字段信息初始化生成配置
+func NewColumn(columnName, columnComment, columnType string, isPk, isIncrement, isRequired bool) Column {
+	column := Column{
+		ColumnName:    columnName,
+		ColumnComment: columnComment,
+		ColumnType:    columnType,
+		GoType:        GoType(columnType),
+		GoField:       ToCamel(columnName),
+		IsPk:          isPk,
+		IsIncrement:   isIncrement,
+		IsRequired:    isRequired && !isPk,
+		QueryType:     "EQ",
+		HtmlType:      "input",
+	}
+
+	dataType := DataType(columnType)
+	switch {
+	case dataType == "text" || dataType == "tinytext" || dataType == "mediumtext" || dataType == "longtext":
+		column.HtmlType = "textarea"
+	case strings.HasPrefix(column.GoType, "datetime."):
+		column.HtmlType = "datetime"
+	}
+
+	if column.IsBase() || isPk {
+		return column
+	}
+	column.IsInsert = true
+	column.IsEdit = true
+	column.IsList = dataType != "text" && dataType != "mediumtext" && dataType != "longtext"
+	column.IsQuery = column.IsList && columnName != "remark"
+
+	lowerName := strings.ToLower(columnName)
+	switch {
+	case strings.HasSuffix(lowerName, "name"):
+		column.QueryType = "LIKE"
+	case strings.HasSuffix(lowerName, "status"), strings.HasSuffix(lowerName, "type"), strings.HasSuffix(lowerName, "sex"):
+		column.HtmlType = "select"
+	case strings.HasSuffix(lowerName, "image"):
+		column.HtmlType = "imageUpload"
+	case strings.HasSuffix(lowerName, "file"):
+		column.HtmlType = "fileUpload"
+	case strings.HasSuffix(lowerName, "content"):
+		column.HtmlType = "editor"
+	}
+	if column.HtmlType == "datetime" {
+		column.QueryType = "BETWEEN"
+	}
+	return column
+}
+
+// DataType 获取字段的数据类型，如varchar(64)返回varchar
+func DataType(columnType string) string {
+	columnType = strings.ToLower(columnType)
+	if index := strings.IndexAny(columnType, "( "); index > 0 {
+		return columnType[:index]
+	}
+	return columnType
+}
+
+// GoType 数据库字段类型转换为Go类型
+func GoType(columnType string) string {
+	switch DataType(columnType) {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		return "int"
+	case "float", "double", "decimal":
+		return "float64"
+	case "datetime", "timestamp":
+		return "datetime.Datetime"
+	case "date":
+		return "datetime.Date"
+	default:
+		return "string"
+	}
+}
+
+// IsBase 是否为基础字段
+func (c Column) IsBase() bool {
+	for _, name := range baseColumns {
+		if c.ColumnName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// JsonField json字段名，如postName
+func (c Column) JsonField() string {
+	return ToLowerCamel(c.ColumnName)
+}
+
+// IsString 是否为字符串类型
+func (c Column) IsString() bool {
+	return c.GoType == "string"
+}
+
+// IsDatetime 是否为日期时间类型
+func (c Column) IsDatetime() bool {
+	return strings.HasPrefix(c.GoType, "datetime.")
+}
+
+// ModelType 模型中的字段类型
+func (c Column) ModelType() string {
+	if c.ColumnName == "delete_time" {
+		return "gorm.DeletedAt"
+	}
+	return c.GoType
+}
+
+// ModelTag 模型中的gorm标签
+func (c Column) ModelTag() string {
+	switch {
+	case c.IsPk && c.IsIncrement:
+		return "`gorm:\"primaryKey;autoIncrement\"`"
+	case c.IsPk:
+		return "`gorm:\"primaryKey\"`"
+	case c.ColumnName == "create_time":
+		return "`gorm:\"autoCreateTime\"`"
+	case c.ColumnName == "update_time":
+		return "`gorm:\"autoUpdateTime\"`"
+	}
+	return ""
+}
+
+// QueryGoType 查询参数类型，日期时间类型使用字符串接收
+func (c Column) QueryGoType() string {
+	if c.IsDatetime() {
+		return "string"
+	}
+	return c.GoType
+}
+
+// ExportValue 导出时的字段取值表达式，日期时间类型格式化为字符串
+func (c Column) ExportValue(name string) string {
+	switch c.GoType {
+	case "datetime.Datetime":
+		return name + "." + c.GoField + ".Format(datetime.DATETIME_FORMAT0)"
+	case "datetime.Date":
+		return name + "." + c.GoField + ".Format(datetime.DATE_FORMAT0)"
+	}
+	return name + "." + c.GoField
+}
+
+// NotEmpty 查询参数的非空判断表达式
+func (c Column) NotEmpty(field string) string {
+	if c.QueryGoType() == "string" {
+		return field + " != \"\""
+	}
+	return field + " != 0"
+}
+
+// ToCamel 下划线转大驼峰，如sys_post转为SysPost
+func ToCamel(name string) string {
+	var builder strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return builder.String()
+}
+
+// ToLowerCamel 下划线转小驼峰，如post_name转为postName
+func ToLowerCamel(name string) string {
+	camel := ToCamel(name)
+	if camel == "" {
+		return camel
+	}
+	return strings.ToLower(camel[:1]) + camel[1:]
+}
+
+// ClassName 表名转换为业务实体名称，autoRemovePre为true时去除表前缀
+func ClassName(tableName string, autoRemovePre bool, tablePrefix string) string {
+	if autoRemovePre && tablePrefix != "" {
+		for _, prefix := range strings.Split(tablePrefix, ",") {
+			if prefix = strings.TrimSpace(prefix); prefix != "" && strings.HasPrefix(tableName, prefix) {
+				tableName = strings.TrimPrefix(tableName, prefix)
+				break
+			}
+		}
+	}
+	return ToCamel(tableName)
+}
+
+// BusinessName 表名转换为业务名，取最后一个下划线后的内容
+func BusinessName(tableName string) string {
+	return tableName[strings.LastIndex(tableName, "_")+1:]
+}
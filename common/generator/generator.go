@@ -0,0 +1,255 @@
+package generator
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"errors"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates/*.tpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("gen").Funcs(template.FuncMap{
+	"hasDatetime": hasDatetime,
+}).ParseFS(templateFS, "templates/*.tpl"))
+
+// Table 代码生成模板数据
+type Table struct {
+	TableName      string   // 表名称，如sys_post
+	TableComment   string   // 表描述
+	ClassName      string   // 业务实体名称，如Post
+	ModuleName     string   // 模块名，如system
+	BusinessName   string   // 业务名，如post
+	FunctionName   string   // 功能名，如岗位
+	FunctionAuthor string   // 作者
+	ParentMenuId   int      // 上级菜单id
+	Columns        []Column // 表字段
+}
+
+// Column 代码生成字段数据
+type Column struct {
+	ColumnName    string // 字段名，如post_name
+	ColumnComment string // 字段描述
+	ColumnType    string // 字段类型，如varchar(64)
+	GoType        string // Go类型
+	GoField       string // Go字段名，如PostName
+	IsPk          bool   // 是否主键
+	IsIncrement   bool   // 是否自增
+	IsRequired    bool   // 是否必填
+	IsInsert      bool   // 是否为新增字段
+	IsEdit        bool   // 是否为编辑字段
+	IsList        bool   // 是否为列表字段
+	IsQuery       bool   // 是否为查询字段
+	QueryType     string // 查询方式：EQ、NE、GT、GTE、LT、LTE、LIKE、BETWEEN
+	HtmlType      string // 显示类型：input、textarea、select、radio、checkbox、datetime、imageUpload、fileUpload、editor
+	DictType      string // 字典类型
+}
+
+// Render 渲染代码，返回以项目根目录为基准的相对路径和文件内容
+func Render(table *Table) (map[string]string, error) {
+	// 生成信息直接插入到Go代码及sql中，预览、下载及生成代码前统一校验
+	if err := table.validate(); err != nil {
+		return nil, err
+	}
+	files := map[string]string{
+		"model/" + table.TableName + ".go":                              "model.go.tpl",
+		"model/dto/" + table.BusinessName + "_request.go":               "request.go.tpl",
+		"model/dto/" + table.BusinessName + "_response.go":              "response.go.tpl",
+		"api/validator/admin/" + table.BusinessName + "_validator.go":   "validator.go.tpl",
+		"service/admin/" + table.BusinessName + "_service.go":           "service.go.tpl",
+		"api/controller/admin/" + table.BusinessName + "_controller.go": "controller.go.tpl",
+		"route/" + table.BusinessName + ".go":                           "route.go.tpl",
+		"docs/sql/" + table.BusinessName + "_menu.sql":                  "menu.sql.tpl",
+	}
+	result := make(map[string]string, len(files))
+	for fileName, tplName := range files {
+		var buffer bytes.Buffer
+		if err := templates.ExecuteTemplate(&buffer, tplName, table); err != nil {
+			return nil, err
+		}
+		content := buffer.Bytes()
+		// 格式化失败时保留原始内容，方便在预览中排查模板问题
+		if strings.HasSuffix(fileName, ".go") {
+			if formatted, err := format.Source(content); err == nil {
+				content = formatted
+			}
+		}
+		result[fileName] = string(content)
+	}
+	return result, nil
+}
+
+// Zip 将渲染结果打包为zip
+func Zip(files map[string]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		w, err := writer.Create(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = w.Write([]byte(files[fileName])); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+var (
+	// namePattern 表名、字段名及业务名只能包含字母、数字和下划线
+	namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	// exportedPattern 实体名称及字段名须为导出的Go标识符
+	exportedPattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+	// packagePattern 模块名及包名须为小写标识符
+	packagePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// goTypes 字段可选的Go类型，与GoType的转换结果一致
+var goTypes = []string{"int", "float64", "string", "datetime.Datetime", "datetime.Date"}
+
+// ValidName 表名、字段名及业务名用于生成文件名及sql，校验是否只包含字母、数字和下划线
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// ValidExported 校验实体名称及字段名是否为导出的Go标识符
+func ValidExported(name string) bool {
+	return exportedPattern.MatchString(name)
+}
+
+// ValidPackage 校验模块名及包名是否为小写标识符
+func ValidPackage(name string) bool {
+	return packagePattern.MatchString(name) && token.IsIdentifier(name)
+}
+
+// ValidGoType 校验字段的Go类型是否为可选类型
+func ValidGoType(goType string) bool {
+	for _, item := range goTypes {
+		if goType == item {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidText 功能名及字段描述插入到注释、字符串及sql中，不能包含引号、反斜杠及换行等控制字符
+func ValidText(text string) bool {
+	return !strings.ContainsAny(text, "'\"`\\") && strings.IndexFunc(text, unicode.IsControl) < 0
+}
+
+// validate 校验生成信息，避免生成无法编译或被注入代码的文件
+func (t *Table) validate() error {
+	if !ValidName(t.TableName) || !ValidName(t.BusinessName) {
+		return errors.New("表名称及生成业务名只能包含字母、数字和下划线，且以字母开头")
+	}
+	if !ValidExported(t.ClassName) {
+		return errors.New("实体类名称须以大写字母开头，且只能包含字母、数字和下划线")
+	}
+	if !ValidPackage(t.ModuleName) {
+		return errors.New("生成模块名须以小写字母开头，且只能包含小写字母、数字和下划线")
+	}
+	if !ValidText(t.FunctionName) {
+		return errors.New("生成功能名不能包含引号、反斜杠及换行")
+	}
+	for _, column := range t.Columns {
+		if !ValidName(column.ColumnName) {
+			return errors.New("字段" + column.ColumnName + "名称只能包含字母、数字和下划线，且以字母开头")
+		}
+		if !ValidExported(column.GoField) {
+			return errors.New("字段" + column.ColumnName + "的Go属性须以大写字母开头，且只能包含字母、数字和下划线")
+		}
+		if !ValidGoType(column.GoType) {
+			return errors.New("字段" + column.ColumnName + "的Go类型只能为" + strings.Join(goTypes, "、"))
+		}
+		if !ValidText(column.ColumnComment) {
+			return errors.New("字段" + column.ColumnName + "的描述不能包含引号、反斜杠及换行")
+		}
+	}
+	return nil
+}
+
+// ValidPath 校验生成路径，为空或/表示项目根目录，否则须为不含..的相对路径
+func ValidPath(genPath string) bool {
+	if genPath == "" || genPath == "/" {
+		return true
+	}
+	if filepath.IsAbs(genPath) || filepath.VolumeName(genPath) != "" || strings.ContainsAny(genPath, ":") || strings.HasPrefix(genPath, "/") || strings.HasPrefix(genPath, `\`) {
+		return false
+	}
+	for _, part := range strings.FieldsFunc(genPath, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteFiles 将渲染结果写入到项目根目录下的生成路径，目标路径不在项目根目录下时拒绝写入
+//
+// overwrite为false时，目标文件已存在则拒绝写入并返回已存在的文件
+func WriteFiles(root, genPath string, files map[string]string, overwrite bool) error {
+	if !ValidPath(genPath) {
+		return errors.New("生成路径须为项目根目录下的相对路径")
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	target := root
+	if genPath != "/" {
+		target = filepath.Join(root, filepath.FromSlash(genPath))
+	}
+	paths := make(map[string]string, len(files))
+	exists := make([]string, 0)
+	for fileName, content := range files {
+		path := filepath.Join(target, filepath.FromSlash(fileName))
+		if !within(root, path) {
+			return errors.New("生成文件" + fileName + "不在项目根目录下")
+		}
+		if _, err := os.Stat(path); err == nil {
+			rel, _ := filepath.Rel(root, path)
+			exists = append(exists, filepath.ToSlash(rel))
+		}
+		paths[path] = content
+	}
+	if len(exists) > 0 && !overwrite {
+		sort.Strings(exists)
+		return errors.New("以下文件已存在：" + strings.Join(exists, "、") + "，确认覆盖后重新生成")
+	}
+	for path, content := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// within 判断path是否在root目录下
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
@@ -0,0 +1,102 @@
+package generator
+
+// ModelName 模型名称，如SysPost
+func (t *Table) ModelName() string {
+	return ToCamel(t.TableName)
+}
+
+// VarName 变量名称，如post
+func (t *Table) VarName() string {
+	return ToLowerCamel(t.ClassName)
+}
+
+// PermPrefix 权限标识前缀，如system:post
+func (t *Table) PermPrefix() string {
+	return t.ModuleName + ":" + t.BusinessName
+}
+
+// PkColumn 主键字段，未设置主键时取第一个字段
+func (t *Table) PkColumn() Column {
+	for _, column := range t.Columns {
+		if column.IsPk {
+			return column
+		}
+	}
+	if len(t.Columns) > 0 {
+		return t.Columns[0]
+	}
+	return Column{}
+}
+
+// HasColumn 是否存在字段
+func (t *Table) HasColumn(columnName string) bool {
+	for _, column := range t.Columns {
+		if column.ColumnName == columnName {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveColumns 保存请求字段
+func (t *Table) SaveColumns() []Column {
+	return t.filter(func(column Column) bool {
+		return column.IsPk || column.IsInsert || column.IsEdit || column.ColumnName == "create_by" || column.ColumnName == "update_by"
+	})
+}
+
+// InsertColumns 新增字段
+func (t *Table) InsertColumns() []Column {
+	return t.filter(func(column Column) bool {
+		return column.IsInsert && !column.IsPk
+	})
+}
+
+// EditColumns 编辑字段
+func (t *Table) EditColumns() []Column {
+	return t.filter(func(column Column) bool {
+		return column.IsEdit && !column.IsPk
+	})
+}
+
+// DetailColumns 详情字段
+func (t *Table) DetailColumns() []Column {
+	return t.filter(func(column Column) bool {
+		return column.IsPk || column.IsInsert || column.IsEdit
+	})
+}
+
+// ListColumns 列表字段
+func (t *Table) ListColumns() []Column {
+	return t.filter(func(column Column) bool {
+		return column.IsPk || column.IsList
+	})
+}
+
+// QueryColumns 查询字段
+func (t *Table) QueryColumns() []Column {
+	return t.filter(func(column Column) bool {
+		return column.IsQuery
+	})
+}
+
+// filter 筛选字段，delete_time由gorm维护，不参与保存、列表及查询
+func (t *Table) filter(fn func(column Column) bool) []Column {
+	columns := make([]Column, 0)
+	for _, column := range t.Columns {
+		if column.ColumnName != "delete_time" && fn(column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// hasDatetime 字段中是否包含日期时间类型，delete_time在模型中为gorm.DeletedAt，不计入
+func hasDatetime(columns []Column) bool {
+	for _, column := range columns {
+		if column.IsDatetime() && column.ColumnName != "delete_time" {
+			return true
+		}
+	}
+	return false
+}
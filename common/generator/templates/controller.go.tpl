@@ -0,0 +1,158 @@
+{{- $pk := .PkColumn -}}
+{{- $table := . -}}
+package admin
+
+import (
+	"github.com/hugo8680/goat/api/validator/admin"
+{{- if or (.HasColumn "create_by") (.HasColumn "update_by")}}
+	"github.com/hugo8680/goat/common/constant/auth"
+{{- end}}
+	"github.com/hugo8680/goat/common/serializer/datetime"
+{{- if not $pk.IsString}}
+	"github.com/hugo8680/goat/common/utils"
+{{- end}}
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/model/dto"
+	adminService "github.com/hugo8680/goat/service/admin"
+{{- if $pk.IsString}}
+	"strings"
+{{- else}}
+	"strconv"
+{{- end}}
+	"time"
+
+	"gitee.com/hanshuangjianke/go-excel/excel"
+	"github.com/gin-gonic/gin"
+)
+
+type {{.ClassName}}Controller struct {
+	{{.VarName}}Service *adminService.{{.ClassName}}Service
+}
+
+func New{{.ClassName}}Controller() *{{.ClassName}}Controller {
+	return &{{.ClassName}}Controller{
+		{{.VarName}}Service: &adminService.{{.ClassName}}Service{},
+	}
+}
+
+// List {{.FunctionName}}列表
+func (c *{{.ClassName}}Controller) List(ctx *gin.Context) {
+	var param dto.{{.ClassName}}ListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	{{.VarName}}s, total := c.{{.VarName}}Service.List(ctx.Request.Context(), param, true)
+	response.Success(ctx).SetPageData({{.VarName}}s, total).Json()
+}
+
+// Get {{.FunctionName}}详情
+func (c *{{.ClassName}}Controller) Get(ctx *gin.Context) {
+{{- if $pk.IsString}}
+	{{$pk.JsonField}} := ctx.Param("{{$pk.JsonField}}")
+{{- else}}
+	{{$pk.JsonField}}, _ := strconv.Atoi(ctx.Param("{{$pk.JsonField}}"))
+{{- end}}
+	{{.VarName}} := c.{{.VarName}}Service.Get(ctx.Request.Context(), {{$pk.JsonField}})
+	response.Success(ctx).SetData("data", {{.VarName}}).Json()
+}
+
+// Create 新增{{.FunctionName}}
+func (c *{{.ClassName}}Controller) Create(ctx *gin.Context) {
+	var param dto.Create{{.ClassName}}Request
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.Create{{.ClassName}}Validator(param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+{{- if .HasColumn "create_by"}}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+{{- end}}
+	if err := c.{{.VarName}}Service.Create(ctx.Request.Context(), dto.Save{{.ClassName}}Request{
+{{- range .InsertColumns}}
+		{{.GoField}}: param.{{.GoField}},
+{{- end}}
+{{- if .HasColumn "create_by"}}
+		CreateBy: user.(*dto.UserTokenResponse).UserName,
+{{- end}}
+	}); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Update 更新{{.FunctionName}}
+func (c *{{.ClassName}}Controller) Update(ctx *gin.Context) {
+	var param dto.Update{{.ClassName}}Request
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err := admin.Update{{.ClassName}}Validator(param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+{{- if .HasColumn "update_by"}}
+	user, _ := ctx.Get(auth.CONTEXT_USER_KEY)
+{{- end}}
+	if err := c.{{.VarName}}Service.Update(ctx.Request.Context(), dto.Save{{.ClassName}}Request{
+		{{$pk.GoField}}: param.{{$pk.GoField}},
+{{- range .EditColumns}}
+		{{.GoField}}: param.{{.GoField}},
+{{- end}}
+{{- if .HasColumn "update_by"}}
+		UpdateBy: user.(*dto.UserTokenResponse).UserName,
+{{- end}}
+	}); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Delete 删除{{.FunctionName}}
+func (c *{{.ClassName}}Controller) Delete(ctx *gin.Context) {
+{{- if $pk.IsString}}
+	{{$pk.JsonField}}s := strings.Split(ctx.Param("{{$pk.JsonField}}s"), ",")
+	if err := c.{{.VarName}}Service.Delete(ctx.Request.Context(), {{$pk.JsonField}}s); err != nil {
+{{- else}}
+	{{$pk.JsonField}}s, err := utils.StringToIntSlice(ctx.Param("{{$pk.JsonField}}s"), ",")
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	if err = c.{{.VarName}}Service.Delete(ctx.Request.Context(), {{$pk.JsonField}}s); err != nil {
+{{- end}}
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	response.Success(ctx).Json()
+}
+
+// Export 数据导出
+func (c *{{.ClassName}}Controller) Export(ctx *gin.Context) {
+	var param dto.{{.ClassName}}ListRequest
+	if err := ctx.ShouldBind(&param); err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	list := make([]dto.{{.ClassName}}ExportResponse, 0)
+	{{.VarName}}s, _ := c.{{.VarName}}Service.List(ctx.Request.Context(), param, false)
+	for _, {{.VarName}} := range {{.VarName}}s {
+		list = append(list, dto.{{.ClassName}}ExportResponse{
+{{- range .ListColumns}}
+			{{.GoField}}: {{.ExportValue $table.VarName}},
+{{- end}}
+		})
+	}
+	file, err := excel.NormalDynamicExport("Sheet1", "", "", false, false, list, nil)
+	if err != nil {
+		response.Error(ctx).SetMsg(err.Error()).Json()
+		return
+	}
+	excel.DownLoadExcel("{{.BusinessName}}_"+time.Now().Format(datetime.DATETIME_FORMAT2), ctx.Writer, file)
+}
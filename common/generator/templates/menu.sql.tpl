@@ -0,0 +1,12 @@
+-- {{.FunctionName}}菜单
+INSERT INTO `sys_menu` (`menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `remark`) VALUES ('{{.FunctionName}}', {{.ParentMenuId}}, 1, '{{.BusinessName}}', '{{.ModuleName}}/{{.BusinessName}}/index', '', '', 1, 0, 'C', '0', '{{.PermPrefix}}:list', '#', '0', 'admin', NOW(), '{{.FunctionName}}菜单');
+
+-- 按钮父菜单id
+SELECT @parentId := LAST_INSERT_ID();
+
+-- {{.FunctionName}}按钮
+INSERT INTO `sys_menu` (`menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `remark`) VALUES ('{{.FunctionName}}查询', @parentId, 1, '', '', '', '', 1, 0, 'F', '0', '{{.PermPrefix}}:query', '#', '0', 'admin', NOW(), '');
+INSERT INTO `sys_menu` (`menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `remark`) VALUES ('{{.FunctionName}}新增', @parentId, 2, '', '', '', '', 1, 0, 'F', '0', '{{.PermPrefix}}:add', '#', '0', 'admin', NOW(), '');
+INSERT INTO `sys_menu` (`menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `remark`) VALUES ('{{.FunctionName}}修改', @parentId, 3, '', '', '', '', 1, 0, 'F', '0', '{{.PermPrefix}}:edit', '#', '0', 'admin', NOW(), '');
+INSERT INTO `sys_menu` (`menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `remark`) VALUES ('{{.FunctionName}}删除', @parentId, 4, '', '', '', '', 1, 0, 'F', '0', '{{.PermPrefix}}:remove', '#', '0', 'admin', NOW(), '');
+INSERT INTO `sys_menu` (`menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `remark`) VALUES ('{{.FunctionName}}导出', @parentId, 5, '', '', '', '', 1, 0, 'F', '0', '{{.PermPrefix}}:export', '#', '0', 'admin', NOW(), '');
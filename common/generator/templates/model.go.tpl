@@ -0,0 +1,22 @@
+package model
+{{if or (hasDatetime .Columns) (.HasColumn "delete_time")}}
+import (
+{{- if hasDatetime .Columns}}
+	"github.com/hugo8680/goat/common/serializer/datetime"
+{{- end}}
+{{- if .HasColumn "delete_time"}}
+
+	"gorm.io/gorm"
+{{- end}}
+)
+{{end}}
+// {{.ModelName}} {{.FunctionName}}
+type {{.ModelName}} struct {
+{{- range .Columns}}
+	{{.GoField}} {{.ModelType}} {{.ModelTag}}
+{{- end}}
+}
+
+func ({{.ModelName}}) TableName() string {
+	return "{{.TableName}}"
+}
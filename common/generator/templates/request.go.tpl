@@ -0,0 +1,42 @@
+package dto
+{{if hasDatetime .SaveColumns}}
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+{{end}}
+// Save{{.ClassName}}Request 保存{{.FunctionName}}
+type Save{{.ClassName}}Request struct {
+{{- range .SaveColumns}}
+	{{.GoField}} {{.GoType}} `json:"{{.JsonField}}"`
+{{- end}}
+}
+
+// {{.ClassName}}ListRequest {{.FunctionName}}列表
+type {{.ClassName}}ListRequest struct {
+	PageRequest
+{{- range .QueryColumns}}
+{{- if eq .QueryType "BETWEEN"}}
+	Begin{{.GoField}} string `query:"params[begin{{.GoField}}]" form:"params[begin{{.GoField}}]"`
+	End{{.GoField}} string `query:"params[end{{.GoField}}]" form:"params[end{{.GoField}}]"`
+{{- else}}
+	{{.GoField}} {{.QueryGoType}} `query:"{{.JsonField}}" form:"{{.JsonField}}"`
+{{- end}}
+{{- end}}
+}
+
+// Create{{.ClassName}}Request 新增{{.FunctionName}}
+type Create{{.ClassName}}Request struct {
+{{- range .InsertColumns}}
+	{{.GoField}} {{.GoType}} `json:"{{.JsonField}}"`
+{{- end}}
+}
+
+// Update{{.ClassName}}Request 更新{{.FunctionName}}
+type Update{{.ClassName}}Request struct {
+{{- with .PkColumn}}
+	{{.GoField}} {{.GoType}} `json:"{{.JsonField}}"`
+{{- end}}
+{{- range .EditColumns}}
+	{{.GoField}} {{.GoType}} `json:"{{.JsonField}}"`
+{{- end}}
+}
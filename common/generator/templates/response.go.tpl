@@ -0,0 +1,26 @@
+package dto
+{{if or (hasDatetime .ListColumns) (hasDatetime .DetailColumns)}}
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+{{end}}
+// {{.ClassName}}ListResponse {{.FunctionName}}列表
+type {{.ClassName}}ListResponse struct {
+{{- range .ListColumns}}
+	{{.GoField}} {{.GoType}} `json:"{{.JsonField}}"`
+{{- end}}
+}
+
+// {{.ClassName}}DetailResponse {{.FunctionName}}详情
+type {{.ClassName}}DetailResponse struct {
+{{- range .DetailColumns}}
+	{{.GoField}} {{.GoType}} `json:"{{.JsonField}}"`
+{{- end}}
+}
+
+// {{.ClassName}}ExportResponse {{.FunctionName}}导出
+type {{.ClassName}}ExportResponse struct {
+{{- range .ListColumns}}
+	{{.GoField}} {{.QueryGoType}} `excel:"name:{{if .ColumnComment}}{{.ColumnComment}}{{else}}{{.ColumnName}}{{end}};"`
+{{- end}}
+}
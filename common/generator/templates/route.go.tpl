@@ -0,0 +1,67 @@
+{{- $pk := .PkColumn -}}
+package route
+
+import (
+	"github.com/hugo8680/goat/api/controller/admin"
+	"github.com/hugo8680/goat/common/constant/log_request_type"
+	"github.com/hugo8680/goat/framework"
+	"github.com/hugo8680/goat/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// {{.ClassName}}Routes {{.FunctionName}}路由
+//
+// 路由组未设置访问方式，组内路由需要登录并按Permission鉴权
+//
+// 将路由组按照可变参数的形式传递给app.Run即可，如：app.Run(route.DefaultRoutes(), route.{{.ClassName}}Routes())
+func {{.ClassName}}Routes() []framework.RouteGroup {
+	return []framework.RouteGroup{
+		{
+			Name:         "{{.FunctionName}}",
+			RelativePath: "/",
+			Routes: []framework.Route{
+				{
+					Method:       "GET",
+					RelativePath: "/{{.ModuleName}}/{{.BusinessName}}/list",
+					Permission:   "{{.PermPrefix}}:list",
+					Function:     admin.New{{.ClassName}}Controller().List,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/{{.ModuleName}}/{{.BusinessName}}/:{{$pk.JsonField}}",
+					Permission:   "{{.PermPrefix}}:query",
+					Function:     admin.New{{.ClassName}}Controller().Get,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/{{.ModuleName}}/{{.BusinessName}}",
+					Permission:   "{{.PermPrefix}}:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增{{.FunctionName}}", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.New{{.ClassName}}Controller().Create,
+				},
+				{
+					Method:       "PUT",
+					RelativePath: "/{{.ModuleName}}/{{.BusinessName}}",
+					Permission:   "{{.PermPrefix}}:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新{{.FunctionName}}", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.New{{.ClassName}}Controller().Update,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/{{.ModuleName}}/{{.BusinessName}}/:{{$pk.JsonField}}s",
+					Permission:   "{{.PermPrefix}}:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除{{.FunctionName}}", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.New{{.ClassName}}Controller().Delete,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/{{.ModuleName}}/{{.BusinessName}}/export",
+					Permission:   "{{.PermPrefix}}:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出{{.FunctionName}}", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.New{{.ClassName}}Controller().Export,
+				},
+			},
+		},
+	}
+}
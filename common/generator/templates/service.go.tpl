@@ -0,0 +1,75 @@
+{{- $pk := .PkColumn -}}
+package admin
+
+import (
+	"context"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/model"
+	"github.com/hugo8680/goat/model/dto"
+)
+
+type {{.ClassName}}Service struct {
+}
+
+// Create 创建{{.FunctionName}}
+func (s *{{.ClassName}}Service) Create(ctx context.Context, param dto.Save{{.ClassName}}Request) error {
+	return connector.DB(ctx).Model(model.{{.ModelName}}{}).Create(&model.{{.ModelName}}{
+{{- range .InsertColumns}}
+		{{.GoField}}: param.{{.GoField}},
+{{- end}}
+{{- if .HasColumn "create_by"}}
+		CreateBy: param.CreateBy,
+{{- end}}
+	}).Error
+}
+
+// Update 更新{{.FunctionName}}
+func (s *{{.ClassName}}Service) Update(ctx context.Context, param dto.Save{{.ClassName}}Request) error {
+	return connector.DB(ctx).Model(model.{{.ModelName}}{}).Where("{{$pk.ColumnName}} = ?", param.{{$pk.GoField}}).Updates(&model.{{.ModelName}}{
+{{- range .EditColumns}}
+		{{.GoField}}: param.{{.GoField}},
+{{- end}}
+{{- if .HasColumn "update_by"}}
+		UpdateBy: param.UpdateBy,
+{{- end}}
+	}).Error
+}
+
+// Delete 删除{{.FunctionName}}
+func (s *{{.ClassName}}Service) Delete(ctx context.Context, {{$pk.JsonField}}s []{{$pk.GoType}}) error {
+	return connector.DB(ctx).Model(model.{{.ModelName}}{}).Where("{{$pk.ColumnName}} IN ?", {{$pk.JsonField}}s).Delete(&model.{{.ModelName}}{}).Error
+}
+
+// List {{.FunctionName}}列表
+func (s *{{.ClassName}}Service) List(ctx context.Context, param dto.{{.ClassName}}ListRequest, isPaging bool) ([]dto.{{.ClassName}}ListResponse, int) {
+	var count int64
+	{{.VarName}}s := make([]dto.{{.ClassName}}ListResponse, 0)
+	query := connector.DB(ctx).Model(model.{{.ModelName}}{}).Order("{{$pk.ColumnName}}")
+{{- range .QueryColumns}}
+{{- if eq .QueryType "BETWEEN"}}
+	if param.Begin{{.GoField}} != "" && param.End{{.GoField}} != "" {
+		query.Where("{{.ColumnName}} BETWEEN ? AND ?", param.Begin{{.GoField}}, param.End{{.GoField}})
+	}
+{{- else if eq .QueryType "LIKE"}}
+	if {{.NotEmpty (print "param." .GoField)}} {
+		query.Where("{{.ColumnName}} LIKE ?", "%"+param.{{.GoField}}+"%")
+	}
+{{- else}}
+	if {{.NotEmpty (print "param." .GoField)}} {
+		query.Where("{{.ColumnName}} {{if eq .QueryType "NE"}}!={{else if eq .QueryType "GT"}}>{{else if eq .QueryType "GTE"}}>={{else if eq .QueryType "LT"}}<{{else if eq .QueryType "LTE"}}<={{else}}={{end}} ?", param.{{.GoField}})
+	}
+{{- end}}
+{{- end}}
+	if isPaging {
+		query.Count(&count).Offset((param.PageNum - 1) * param.PageSize).Limit(param.PageSize)
+	}
+	query.Find(&{{.VarName}}s)
+	return {{.VarName}}s, int(count)
+}
+
+// Get 根据{{$pk.ColumnComment}}获取{{.FunctionName}}详情
+func (s *{{.ClassName}}Service) Get(ctx context.Context, {{$pk.JsonField}} {{$pk.GoType}}) dto.{{.ClassName}}DetailResponse {
+	var {{.VarName}} dto.{{.ClassName}}DetailResponse
+	connector.DB(ctx).Model(model.{{.ModelName}}{}).Where("{{$pk.ColumnName}} = ?", {{$pk.JsonField}}).Last(&{{.VarName}})
+	return {{.VarName}}
+}
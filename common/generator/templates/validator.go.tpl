@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"errors"
+	"github.com/hugo8680/goat/model/dto"
+)
+
+// Create{{.ClassName}}Validator 添加{{.FunctionName}}验证
+func Create{{.ClassName}}Validator(param dto.Create{{.ClassName}}Request) error {
+{{- range .InsertColumns}}
+{{- if and .IsRequired .IsString}}
+	if param.{{.GoField}} == "" {
+		return errors.New("请输入{{.ColumnComment}}")
+	}
+{{- end}}
+{{- end}}
+	return nil
+}
+
+// Update{{.ClassName}}Validator 更新{{.FunctionName}}验证
+func Update{{.ClassName}}Validator(param dto.Update{{.ClassName}}Request) error {
+{{- with .PkColumn}}
+	if {{if .IsString}}param.{{.GoField}} == ""{{else}}param.{{.GoField}} <= 0{{end}} {
+		return errors.New("参数错误")
+	}
+{{- end}}
+{{- range .EditColumns}}
+{{- if and .IsRequired .IsString}}
+	if param.{{.GoField}} == "" {
+		return errors.New("请输入{{.ColumnComment}}")
+	}
+{{- end}}
+{{- end}}
+	return nil
+}
@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -70,7 +70,7 @@ func (r *Request) Send(requestParam *RequestParam) (string, error) {
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
-			log.Println(err)
+			slog.Warn("close response body", "module", "http_client", "error", err)
 		}
 	}(result.Body)
 	// 读取响应体内容，并加入缓冲区
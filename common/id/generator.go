@@ -1,7 +1,7 @@
 package id
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/bwmarrin/snowflake"
 )
@@ -9,7 +9,7 @@ import (
 func Gen(key int64) (int64, error) {
 	node, err := snowflake.NewNode(key)
 	if err != nil {
-		log.Println(err)
+		slog.Error("create snowflake node", "module", "id", "error", err)
 		return -1, err
 	}
 	return node.Generate().Int64(), nil
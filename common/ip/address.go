@@ -1,9 +1,6 @@
 package ip
 
 import (
-	"encoding/json"
-	"github.com/hugo8680/goat/common/http_client"
-	"github.com/hugo8680/goat/common/utils"
 	"net"
 
 	"github.com/mileusna/useragent"
@@ -25,38 +22,44 @@ type IpAddress struct {
 
 // GetAddress 根据ip获取地址
 func GetAddress(ip string, userAgent string) *IpAddress {
-	var ipAddress IpAddress
-	// 解析userAgent
+	ipAddress := lookup(ip)
+	ipAddress.Browser, ipAddress.Os = ParseUserAgent(userAgent)
+	return ipAddress
+}
+
+// GetLocation 根据ip获取归属地，使用在线查询时较慢，应避免在请求中同步调用
+func GetLocation(ip string) string {
+	return lookup(ip).Addr
+}
+
+// ParseUserAgent 解析userAgent中的浏览器和操作系统
+func ParseUserAgent(userAgent string) (browser, os string) {
 	userAgentData := useragent.Parse(userAgent)
-	ipAddress.Browser = userAgentData.Name
-	ipAddress.Os = userAgentData.OS
-	var internalIp = "(((\\d)|([1-9]\\d)|(1\\d{2})|(2[0-4]\\d)|(25[0-5]))\\.){3}((\\d)|([1-9]\\d)|(1\\d{2})|(2[0-4]\\d)|(25[0-5]))$"
-	if utils.CheckRegex(internalIp, ip) || ip == "127.0.0.1" || ip == "::1" {
-		ipAddress.Ip = ip
-		ipAddress.Addr = "内网地址"
-		return &ipAddress
+	return userAgentData.Name, userAgentData.OS
+}
+
+// lookup 查询ip归属地，内网地址不查询，查询结果优先从缓存读取
+func lookup(ip string) *IpAddress {
+	netIp := net.ParseIP(ip)
+	if netIp == nil || netIp.IsUnspecified() {
+		return &IpAddress{Ip: ip, Addr: "未知地址"}
 	}
-	if netIp := net.ParseIP(ip); netIp == nil || netIp.IsLoopback() {
-		ipAddress.Ip = ip
-		ipAddress.Addr = "未知地址"
-		return &ipAddress
+	if netIp.IsLoopback() || netIp.IsPrivate() || netIp.IsLinkLocalUnicast() {
+		return &IpAddress{Ip: ip, Addr: "内网地址"}
 	}
-	body, err := http_client.DefaultClient().Send(&http_client.RequestParam{
-		Url: "http://whois.pconline.com.cn/ipJson.jsp",
-		Query: map[string]interface{}{
-			"ip":   ip,
-			"json": true,
-		},
-	})
-	if err != nil {
-		ipAddress.Ip = ip
-		ipAddress.Addr = "未知地址"
+	// 统一IPv4及IPv6的写法，如::ffff:1.2.3.4与1.2.3.4使用同一缓存
+	key := netIp.String()
+	if ipAddress, ok := cache.Get(key); ok {
 		return &ipAddress
 	}
-	if err := json.Unmarshal([]byte(body), &ipAddress); err != nil {
-		ipAddress.Ip = ip
-		ipAddress.Addr = "未知地址"
-		return &ipAddress
+	ipAddress, ok := search(netIp)
+	if ipAddress == nil {
+		ipAddress = &IpAddress{Addr: "未知地址"}
+	}
+	ipAddress.Ip = ip
+	// 查询出错时不缓存，下次重新查询
+	if ok {
+		cache.Add(key, *ipAddress)
 	}
-	return &ipAddress
+	return ipAddress
 }
@@ -0,0 +1,46 @@
+package ip
+
+import (
+	"encoding/json"
+	"github.com/hugo8680/goat/common/http_client"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HttpProvider 通过太平洋网络接口在线查询，会将ip发送给第三方，内网环境无法使用
+type HttpProvider struct {
+	client *http_client.Request
+}
+
+// NewHttpProvider 初始化在线查询
+func NewHttpProvider() *HttpProvider {
+	return &HttpProvider{
+		client: http_client.NewClient(&http.Client{Timeout: 3 * time.Second}),
+	}
+}
+
+func (p *HttpProvider) Name() string {
+	return "http"
+}
+
+func (p *HttpProvider) Lookup(ip net.IP) (*IpAddress, error) {
+	body, err := p.client.Send(&http_client.RequestParam{
+		Url: "http://whois.pconline.com.cn/ipJson.jsp",
+		Query: map[string]interface{}{
+			"ip":   ip.String(),
+			"json": true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ipAddress IpAddress
+	if err := json.Unmarshal([]byte(body), &ipAddress); err != nil {
+		return nil, err
+	}
+	if ipAddress.Addr == "" {
+		return nil, nil
+	}
+	return &ipAddress, nil
+}
@@ -0,0 +1,107 @@
+package ip
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"strings"
+)
+
+// xdb文件结构：256字节文件头，256*256个8字节的二级索引，之后为14字节一条的ip段索引及归属地数据
+const (
+	xdbHeaderLength       = 256
+	xdbVectorIndexCols    = 256
+	xdbVectorIndexSize    = 8
+	xdbSegmentIndexSize   = 14
+	xdbVectorIndexLength  = xdbVectorIndexCols * xdbVectorIndexCols * xdbVectorIndexSize
+	xdbMinimumFileLength  = xdbHeaderLength + xdbVectorIndexLength
+	ip2regionUnknownField = "0"
+)
+
+// Ip2regionProvider 通过ip2region xdb格式的离线库查询，整个文件加载到内存，仅支持IPv4
+type Ip2regionProvider struct {
+	content []byte
+}
+
+// NewIp2regionProvider 加载xdb文件
+func NewIp2regionProvider(path string) (*Ip2regionProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < xdbMinimumFileLength {
+		return nil, errors.New("ip2region文件格式错误：" + path)
+	}
+	return &Ip2regionProvider{
+		content: content,
+	}, nil
+}
+
+func (p *Ip2regionProvider) Name() string {
+	return "ip2region"
+}
+
+func (p *Ip2regionProvider) Lookup(ip net.IP) (*IpAddress, error) {
+	ipv4 := ip.To4()
+	if ipv4 == nil {
+		return nil, nil
+	}
+	region, err := p.search(binary.BigEndian.Uint32(ipv4))
+	if err != nil || region == "" {
+		return nil, err
+	}
+	// 归属地格式为 国家|区域|省份|城市|运营商，新版数据去掉了区域，未知的字段为0
+	fields := strings.Split(region, "|")
+	if len(fields) == 5 {
+		fields = append(fields[:1], fields[2:]...)
+	}
+	for len(fields) < 4 {
+		fields = append(fields, ip2regionUnknownField)
+	}
+	for i, field := range fields {
+		if field == ip2regionUnknownField {
+			fields[i] = ""
+		}
+	}
+	ipAddress := IpAddress{
+		Pro:  fields[1],
+		City: fields[2],
+		Addr: joinAddr(fields[0], fields[1], fields[2], fields[3]),
+	}
+	if ipAddress.Addr == "" {
+		return nil, nil
+	}
+	return &ipAddress, nil
+}
+
+// search 根据ip前两个字节定位二级索引，在索引范围内二分查找所在ip段
+func (p *Ip2regionProvider) search(ip uint32) (string, error) {
+	offset := xdbHeaderLength + int(ip>>24)*xdbVectorIndexCols*xdbVectorIndexSize + int(ip>>16&0xFF)*xdbVectorIndexSize
+	start := int(binary.LittleEndian.Uint32(p.content[offset:]))
+	end := int(binary.LittleEndian.Uint32(p.content[offset+4:]))
+	if start == 0 && end == 0 {
+		return "", nil
+	}
+	if start < xdbMinimumFileLength || end < start || end > len(p.content)-xdbSegmentIndexSize {
+		return "", errors.New("ip2region文件已损坏")
+	}
+	low, high := 0, (end-start)/xdbSegmentIndexSize
+	for low <= high {
+		middle := (low + high) / 2
+		segment := p.content[start+middle*xdbSegmentIndexSize:]
+		if ip < binary.LittleEndian.Uint32(segment) {
+			high = middle - 1
+		} else if ip > binary.LittleEndian.Uint32(segment[4:]) {
+			low = middle + 1
+		} else {
+			length := int(binary.LittleEndian.Uint16(segment[8:]))
+			pointer := int(binary.LittleEndian.Uint32(segment[10:]))
+			if pointer+length > len(p.content) {
+				return "", errors.New("ip2region文件已损坏")
+			}
+			return string(p.content[pointer : pointer+length]), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,84 @@
+package ip
+
+import (
+	"net"
+	"net/netip"
+
+	"github.com/oschwald/maxminddb-golang/v2"
+)
+
+// MmdbProvider 通过MaxMind mmdb格式的离线库查询，支持IPv4及IPv6，如GeoLite2-City.mmdb
+type MmdbProvider struct {
+	reader   *maxminddb.Reader
+	language string
+}
+
+// mmdbRecord mmdb城市库中的归属地字段
+type mmdbRecord struct {
+	Country struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// NewMmdbProvider 打开mmdb文件，language为名称语言，为空时使用zh-CN
+func NewMmdbProvider(path, language string) (*MmdbProvider, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if language == "" {
+		language = "zh-CN"
+	}
+	return &MmdbProvider{
+		reader:   reader,
+		language: language,
+	}, nil
+}
+
+func (p *MmdbProvider) Name() string {
+	return "mmdb"
+}
+
+func (p *MmdbProvider) Lookup(ip net.IP) (*IpAddress, error) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return nil, nil
+	}
+	result := p.reader.Lookup(addr.Unmap())
+	if !result.Found() {
+		return nil, result.Err()
+	}
+	var record mmdbRecord
+	if err := result.Decode(&record); err != nil {
+		return nil, err
+	}
+	ipAddress := IpAddress{
+		City: p.name(record.City.Names),
+	}
+	if len(record.Subdivisions) > 0 {
+		ipAddress.Pro = p.name(record.Subdivisions[0].Names)
+	}
+	ipAddress.Addr = joinAddr(p.name(record.Country.Names), ipAddress.Pro, ipAddress.City, "")
+	if ipAddress.Addr == "" {
+		return nil, nil
+	}
+	return &ipAddress, nil
+}
+
+func (p *MmdbProvider) Close() error {
+	return p.reader.Close()
+}
+
+// name 取配置语言的名称，没有时使用英文名称
+func (p *MmdbProvider) name(names map[string]string) string {
+	if name, ok := names[p.language]; ok {
+		return name
+	}
+	return names["en"]
+}
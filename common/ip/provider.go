@@ -0,0 +1,108 @@
+package ip
+
+import (
+	"errors"
+	"github.com/hugo8680/goat/framework/config"
+	"github.com/hugo8680/goat/framework/logger"
+	"net"
+	"sync"
+)
+
+// Provider 归属地查询方式
+type Provider interface {
+	// Name 名称，用于日志区分
+	Name() string
+	// Lookup 查询归属地，未查到时返回nil
+	Lookup(ip net.IP) (*IpAddress, error)
+}
+
+var (
+	providers   []Provider
+	providersMu sync.RWMutex
+	cache       = newLRU(10000)
+)
+
+// Init 根据配置初始化归属地查询方式及缓存，返回的函数用于关闭离线库文件
+func Init(setting *config.Setting) (func() error, error) {
+	list := make([]Provider, 0, len(setting.Ip.Providers))
+	closeAll := func() error {
+		var errs []error
+		for _, provider := range list {
+			if closer, ok := provider.(interface{ Close() error }); ok {
+				errs = append(errs, closer.Close())
+			}
+		}
+		return errors.Join(errs...)
+	}
+	for _, name := range setting.Ip.Providers {
+		var (
+			provider Provider
+			err      error
+		)
+		switch name {
+		case "ip2region":
+			provider, err = NewIp2regionProvider(setting.Ip.Ip2regionPath)
+		case "mmdb":
+			provider, err = NewMmdbProvider(setting.Ip.MmdbPath, setting.Ip.MmdbLanguage)
+		case "http":
+			provider = NewHttpProvider()
+		default:
+			err = errors.New("未知的归属地查询方式：" + name)
+		}
+		if err != nil {
+			_ = closeAll()
+			return nil, err
+		}
+		list = append(list, provider)
+	}
+	Use(list...)
+	if setting.Ip.CacheSize > 0 {
+		cache = newLRU(setting.Ip.CacheSize)
+	}
+	return closeAll, nil
+}
+
+// Use 设置归属地查询方式，按顺序查询直到查到结果，设置后清空缓存
+func Use(list ...Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers = list
+	cache.Purge()
+}
+
+// search 按顺序查询归属地，ok表示查询过程未出错，结果可以缓存
+func search(ip net.IP) (ipAddress *IpAddress, ok bool) {
+	providersMu.RLock()
+	list := providers
+	providersMu.RUnlock()
+	ok = true
+	for _, provider := range list {
+		ipAddress, err := provider.Lookup(ip)
+		if err != nil {
+			ok = false
+			logger.Module("ip").Warn("lookup ip location", "provider", provider.Name(), "ip", ip.String(), "error", err)
+			continue
+		}
+		if ipAddress != nil {
+			return ipAddress, true
+		}
+	}
+	return nil, ok
+}
+
+// joinAddr 拼接归属地，国内地址省略国家，如 广东省深圳市 电信
+func joinAddr(country, province, city, isp string) string {
+	addr := province
+	if city != province {
+		addr += city
+	}
+	if country != "中国" && country != "China" {
+		addr = country + addr
+	} else if addr == "" {
+		addr = country
+	}
+	if isp != "" && addr != "" {
+		addr += " " + isp
+	}
+	return addr
+}
@@ -0,0 +1,242 @@
+package masker
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/framework/config"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// 完全脱敏后的内容
+const mask = "******"
+
+// 未配置时的最大长度，与sys_oper_log表中oper_param、json_result字段长度一致
+const defaultMaxLength = 2000
+
+// Pattern 按正则部分脱敏，保留匹配内容的前KeepPrefix位及后KeepSuffix位，正则包含分组时仅脱敏第一个分组
+type Pattern struct {
+	Regex      *regexp.Regexp
+	KeepPrefix int
+	KeepSuffix int
+}
+
+// Masker 脱敏器
+type Masker struct {
+	keys      map[string]struct{}
+	paths     map[string]struct{}
+	patterns  []Pattern
+	maxLength int
+}
+
+// DefaultKeys 内置的完全脱敏字段名，配置的字段名在此基础上追加，未配置时密码、令牌等同样不会明文入库
+var DefaultKeys = []string{
+	"password",
+	"oldPassword",
+	"newPassword",
+	"confirmPassword",
+	"token",
+	"accessToken",
+	"refreshToken",
+	"secretKey",
+	"secret",
+	"ticket",
+	"recoveryCode",
+	"recoveryCodes",
+}
+
+var (
+	defaultMasker atomic.Pointer[Masker]
+	watchOnce     sync.Once
+)
+
+func init() {
+	defaultMasker.Store(New(DefaultKeys, nil, nil, 0))
+}
+
+// New 初始化脱敏器
+//
+// keys 完全脱敏的字段名，不区分大小写，匹配任意层级
+//
+// paths 完全脱敏的JSON路径，层级用.分隔，数组不占层级，如data.user.password
+//
+// maxLength 最大长度，超出部分截断，为0时使用默认值
+func New(keys, paths []string, patterns []Pattern, maxLength int) *Masker {
+	m := &Masker{
+		keys:      make(map[string]struct{}, len(keys)),
+		paths:     make(map[string]struct{}, len(paths)),
+		patterns:  patterns,
+		maxLength: maxLength,
+	}
+	for _, key := range keys {
+		m.keys[strings.ToLower(key)] = struct{}{}
+	}
+	for _, path := range paths {
+		m.paths[strings.ToLower(path)] = struct{}{}
+	}
+	if m.maxLength <= 0 {
+		m.maxLength = defaultMaxLength
+	}
+	return m
+}
+
+// Init 根据操作日志配置初始化默认脱敏器，配置变更后重新初始化，多次调用时仅订阅一次配置变更
+func Init(setting *config.Setting) error {
+	m, err := fromSetting(setting)
+	if err != nil {
+		return err
+	}
+	defaultMasker.Store(m)
+	watchOnce.Do(func() {
+		config.OnChange(func(oldSetting, newSetting *config.Setting) {
+			if m, err := fromSetting(newSetting); err == nil {
+				defaultMasker.Store(m)
+			}
+		})
+	})
+	return nil
+}
+
+// Default 默认脱敏器
+func Default() *Masker {
+	return defaultMasker.Load()
+}
+
+func fromSetting(setting *config.Setting) (*Masker, error) {
+	patterns := make([]Pattern, 0, len(setting.OperLog.MaskPatterns))
+	for _, pattern := range setting.OperLog.MaskPatterns {
+		regex, err := regexp.Compile(pattern.Regex)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, Pattern{
+			Regex:      regex,
+			KeepPrefix: pattern.KeepPrefix,
+			KeepSuffix: pattern.KeepSuffix,
+		})
+	}
+	keys := append(append([]string{}, DefaultKeys...), setting.OperLog.MaskKeys...)
+	return New(keys, setting.OperLog.MaskPaths, patterns, setting.OperLog.MaxLength), nil
+}
+
+// Value 脱敏JSON结构的值，返回脱敏后的副本，不修改原值
+func (m *Masker) Value(value interface{}) interface{} {
+	return m.walk(value, "")
+}
+
+// Json 脱敏JSON字符串，无法解析时按正则脱敏
+func (m *Masker) Json(content string) string {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	// 避免大整数转为浮点数后丢失精度
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return m.String(content)
+	}
+	return m.Marshal(m.Value(value))
+}
+
+// Marshal 序列化为JSON字符串，不转义HTML字符
+func (m *Masker) Marshal(value interface{}) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// String 按正则脱敏字符串
+func (m *Masker) String(content string) string {
+	for _, pattern := range m.patterns {
+		content = pattern.replace(content)
+	}
+	return content
+}
+
+// Truncate 截断超出最大长度的内容
+func (m *Masker) Truncate(content string) string {
+	if utf8.RuneCountInString(content) <= m.maxLength {
+		return content
+	}
+	return string([]rune(content)[:m.maxLength])
+}
+
+func (m *Masker) walk(value interface{}, path string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			itemPath := key
+			if path != "" {
+				itemPath = path + "." + key
+			}
+			if m.hit(key, itemPath) {
+				result[key] = mask
+				continue
+			}
+			result[key] = m.walk(item, itemPath)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			result = append(result, m.walk(item, path))
+		}
+		return result
+	case []string:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, m.String(item))
+		}
+		return result
+	case string:
+		return m.String(v)
+	default:
+		return v
+	}
+}
+
+// hit 字段名或路径是否需要完全脱敏
+func (m *Masker) hit(key, path string) bool {
+	if _, ok := m.keys[strings.ToLower(key)]; ok {
+		return true
+	}
+	_, ok := m.paths[strings.ToLower(path)]
+	return ok
+}
+
+// replace 替换所有匹配内容，有分组时仅替换第一个分组
+func (p Pattern) replace(content string) string {
+	matches := p.Regex.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content
+	}
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if len(match) >= 4 && match[2] >= 0 {
+			start, end = match[2], match[3]
+		}
+		builder.WriteString(content[last:start])
+		builder.WriteString(p.desensitize(content[start:end]))
+		last = end
+	}
+	builder.WriteString(content[last:])
+	return builder.String()
+}
+
+// desensitize 保留前后若干位，内容过短时全部脱敏
+func (p Pattern) desensitize(content string) string {
+	length := utf8.RuneCountInString(content)
+	if length <= p.KeepPrefix+p.KeepSuffix {
+		return utils.Desensitize(content, 0, length-1)
+	}
+	return utils.Desensitize(content, p.KeepPrefix, length-p.KeepSuffix-1)
+}
@@ -0,0 +1,78 @@
+package password
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 字符类型
+const (
+	CharLower  = "lower"  // 小写字母
+	CharUpper  = "upper"  // 大写字母
+	CharDigit  = "digit"  // 数字
+	CharSymbol = "symbol" // 特殊字符
+)
+
+// bcrypt只使用前72个字节，超出部分不参与校验
+const maxLength = 72
+
+var charNames = map[string]string{
+	CharLower:  "小写字母",
+	CharUpper:  "大写字母",
+	CharDigit:  "数字",
+	CharSymbol: "特殊字符",
+}
+
+// Policy 密码策略
+//
+// MinLength 最小长度，0不限制
+// CharTypes 必须包含的字符类型，可选lower、upper、digit、symbol
+// BannedWords 禁用词，密码不能包含用户名及禁用词，忽略大小写
+type Policy struct {
+	MinLength   int
+	CharTypes   []string
+	BannedWords []string
+}
+
+// Check 校验密码是否满足策略
+func (p Policy) Check(userName, password string) error {
+	if password == "" {
+		return errors.New("密码不能为空")
+	}
+	if len([]rune(password)) < p.MinLength {
+		return errors.New("密码长度不能少于" + strconv.Itoa(p.MinLength) + "位")
+	}
+	if len(password) > maxLength {
+		return errors.New("密码长度不能超过" + strconv.Itoa(maxLength) + "个字节")
+	}
+	has := make(map[string]bool)
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			has[CharLower] = true
+		case unicode.IsUpper(r):
+			has[CharUpper] = true
+		case unicode.IsDigit(r):
+			has[CharDigit] = true
+		default:
+			has[CharSymbol] = true
+		}
+	}
+	for _, charType := range p.CharTypes {
+		if name, ok := charNames[charType]; ok && !has[charType] {
+			return errors.New("密码必须包含" + name)
+		}
+	}
+	lower := strings.ToLower(password)
+	if userName != "" && strings.Contains(lower, strings.ToLower(userName)) {
+		return errors.New("密码不能包含用户名")
+	}
+	for _, word := range p.BannedWords {
+		if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+			return errors.New("密码不能包含常用词" + word)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package permission
+
+import (
+	"errors"
+	"strings"
+)
+
+// Subject 权限校验对象，通常为当前登录用户
+type Subject interface {
+	// HasPermission 是否具备权限，需支持通配符
+	HasPermission(perm string) bool
+	// HasRole 是否拥有角色
+	HasRole(roleKey string) bool
+}
+
+// Expression 权限表达式
+//
+// 支持 && 、 || 及括号，&& 优先于 ||，role(角色key) 表示需要拥有角色，其余为权限标识，如：
+//
+// system:user:list
+//
+// system:user:add && system:user:edit
+//
+// role(admin) || (system:user:remove && system:user:export)
+type Expression interface {
+	Eval(subject Subject) bool
+	String() string
+}
+
+type permNode string
+
+func (n permNode) Eval(subject Subject) bool {
+	return subject.HasPermission(string(n))
+}
+
+func (n permNode) String() string {
+	return string(n)
+}
+
+type roleNode string
+
+func (n roleNode) Eval(subject Subject) bool {
+	return subject.HasRole(string(n))
+}
+
+func (n roleNode) String() string {
+	return "role(" + string(n) + ")"
+}
+
+type andNode struct {
+	left, right Expression
+}
+
+func (n andNode) Eval(subject Subject) bool {
+	return n.left.Eval(subject) && n.right.Eval(subject)
+}
+
+func (n andNode) String() string {
+	return "(" + n.left.String() + " && " + n.right.String() + ")"
+}
+
+type orNode struct {
+	left, right Expression
+}
+
+func (n orNode) Eval(subject Subject) bool {
+	return n.left.Eval(subject) || n.right.Eval(subject)
+}
+
+func (n orNode) String() string {
+	return "(" + n.left.String() + " || " + n.right.String() + ")"
+}
+
+// Parse 解析权限表达式
+func Parse(expression string) (Expression, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("权限表达式不能为空")
+	}
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.New("权限表达式格式错误，多余的内容：" + p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+// MustParse 解析权限表达式，格式错误时panic，用于注册路由
+func MustParse(expression string) Expression {
+	node, err := Parse(expression)
+	if err != nil {
+		panic(err.Error() + "：" + expression)
+	}
+	return node
+}
+
+// Match 判断拥有的权限是否匹配需要的权限，*匹配任意一段，末尾的*匹配剩余所有段
+//
+// 如 system:user:* 匹配 system:user:list，*:*:* 匹配所有权限
+func Match(granted, required string) bool {
+	if granted == required {
+		return true
+	}
+	grantedParts := strings.Split(granted, ":")
+	requiredParts := strings.Split(required, ":")
+	for i, grantedPart := range grantedParts {
+		if i >= len(requiredParts) {
+			return false
+		}
+		if grantedPart == "*" && i == len(grantedParts)-1 {
+			return true
+		}
+		if grantedPart != "*" && grantedPart != requiredParts[i] {
+			return false
+		}
+	}
+	return len(grantedParts) == len(requiredParts)
+}
+
+// MatchAny 拥有的权限中是否有匹配需要的权限
+func MatchAny(granted []string, required string) bool {
+	for _, perm := range granted {
+		if perm != "" && Match(perm, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize 拆分为 && 、 || 、括号及标识符
+func tokenize(expression string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '&' || c == '|':
+			if i+1 >= len(expression) || expression[i+1] != c {
+				return nil, errors.New("权限表达式格式错误，应使用&&或||")
+			}
+			tokens = append(tokens, expression[i:i+2])
+			i += 2
+		default:
+			start := i
+			for i < len(expression) && !strings.ContainsRune(" \t\n()&|", rune(expression[i])) {
+				i++
+			}
+			tokens = append(tokens, expression[start:i])
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	switch token := p.next(); token {
+	case "":
+		return nil, errors.New("权限表达式格式错误，缺少权限标识")
+	case "&&", "||", ")":
+		return nil, errors.New("权限表达式格式错误，" + token + "前缺少权限标识")
+	case "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("权限表达式格式错误，缺少)")
+		}
+		return node, nil
+	case "role":
+		if p.next() != "(" {
+			return nil, errors.New("权限表达式格式错误，角色应使用role(角色key)")
+		}
+		roleKey := p.next()
+		if roleKey == "" || strings.ContainsAny(roleKey, "()&|") {
+			return nil, errors.New("权限表达式格式错误，缺少角色key")
+		}
+		if p.next() != ")" {
+			return nil, errors.New("权限表达式格式错误，role缺少)")
+		}
+		return roleNode(roleKey), nil
+	default:
+		return permNode(token), nil
+	}
+}
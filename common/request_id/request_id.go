@@ -0,0 +1,53 @@
+package request_id
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const (
+	// Header 请求id的请求头及响应头
+	Header = "X-Request-Id"
+	// ContextKey 请求id在gin.Context及context.Context中的键名
+	ContextKey = "ctx_request_id"
+	// 请求头中请求id的最大长度
+	maxLength = 64
+)
+
+type contextKey struct{}
+
+// New 生成请求id
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid 校验请求头中传入的请求id，只允许字母、数字及-_.:，避免日志注入
+func Valid(requestId string) bool {
+	if requestId == "" || len(requestId) > maxLength {
+		return false
+	}
+	for _, r := range requestId {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+// WithContext 将请求id写入context
+func WithContext(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, contextKey{}, requestId)
+}
+
+// FromContext 从context中获取请求id，支持gin.Context
+func FromContext(ctx context.Context) string {
+	if requestId, ok := ctx.Value(contextKey{}).(string); ok {
+		return requestId
+	}
+	// gin.Context.Value按键名读取ctx.Keys
+	requestId, _ := ctx.Value(ContextKey).(string)
+	return requestId
+}
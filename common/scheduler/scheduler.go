@@ -0,0 +1,276 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/hugo8680/goat/common/constant/job_key"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// 单次补偿的最大执行次数，避免长时间停机后集中执行
+const maxMisfireCount = 100
+
+// 支持可选的秒字段，兼容Quartz的?写法，如0/10 * * * * ?
+var parser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Job 定时任务
+type Job struct {
+	JobId          int
+	JobName        string
+	JobGroup       string
+	InvokeTarget   string
+	CronExpression string
+	MisfirePolicy  string
+	Concurrent     string
+	PrevTime       time.Time // 上次执行时间，仅在调度器启动前添加时用于计算错过的执行次数
+}
+
+// Result 执行结果
+type Result struct {
+	Job       Job
+	StartTime time.Time
+	StopTime  time.Time
+	Err       error
+}
+
+// Handler 执行结果处理函数，如记录执行日志
+type Handler func(result Result)
+
+// Scheduler 定时任务调度器
+type Scheduler struct {
+	cron     *cron.Cron
+	handler  Handler
+	mu       sync.Mutex
+	entries  map[int]cron.EntryID
+	running  map[int]bool
+	misfires map[int]Job // 启动前添加的任务，启动时补偿错过的执行
+	started  bool
+	stopped  bool
+	wg       sync.WaitGroup // 补偿及立即执行的任务，不由cron管理
+}
+
+var defaultScheduler = New()
+
+// New 初始化调度器
+func New() *Scheduler {
+	return &Scheduler{
+		cron:     cron.New(cron.WithParser(parser)),
+		entries:  make(map[int]cron.EntryID),
+		running:  make(map[int]bool),
+		misfires: make(map[int]Job),
+	}
+}
+
+// Default 默认调度器
+func Default() *Scheduler {
+	return defaultScheduler
+}
+
+// SetHandler 设置执行结果处理函数
+func (s *Scheduler) SetHandler(handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+// Start 启动调度器，根据计划执行错误策略补偿启动前添加的任务在上次执行时间到现在之间错过的执行
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	for _, job := range s.misfires {
+		schedule, err := parser.Parse(job.CronExpression)
+		if err != nil {
+			continue
+		}
+		if count := s.misfireCount(job, schedule); count > 0 {
+			s.wg.Add(1)
+			go func(job Job) {
+				defer s.wg.Done()
+				for i := 0; i < count; i++ {
+					s.execute(job)
+				}
+			}(job)
+		}
+	}
+	s.misfires = make(map[int]Job)
+	s.cron.Start()
+}
+
+// Stop 停止调度器，返回的context在正在执行的任务（包括补偿及立即执行的任务）结束后关闭
+func (s *Scheduler) Stop() context.Context {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	cronCtx := s.cron.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-cronCtx.Done()
+		s.wg.Wait()
+		cancel()
+	}()
+	return ctx
+}
+
+// Add 添加任务，已存在的同id任务会被替换
+//
+// 启动前添加的任务在启动时补偿错过的执行，启动后添加（修改、恢复任务）不补偿
+func (s *Scheduler) Add(job Job) error {
+	schedule, err := parser.Parse(job.CronExpression)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entryId, ok := s.entries[job.JobId]; ok {
+		s.cron.Remove(entryId)
+	}
+	s.entries[job.JobId] = s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.execute(job)
+	}))
+	if !s.started {
+		s.misfires[job.JobId] = job
+	}
+	return nil
+}
+
+// Remove 移除任务
+func (s *Scheduler) Remove(jobId int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entryId, ok := s.entries[jobId]; ok {
+		s.cron.Remove(entryId)
+		delete(s.entries, jobId)
+	}
+	delete(s.misfires, jobId)
+}
+
+// RunOnce 立即执行一次任务，调度器停止后不再执行
+func (s *Scheduler) RunOnce(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(job)
+	}()
+}
+
+// NextTime 任务下次执行时间，任务未调度时返回零值
+func (s *Scheduler) NextTime(jobId int) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entryId, ok := s.entries[jobId]; ok {
+		return s.cron.Entry(entryId).Next
+	}
+	return time.Time{}
+}
+
+// execute 执行任务，禁止并发时上一次执行未结束则跳过本次执行
+func (s *Scheduler) execute(job Job) {
+	if job.Concurrent == job_key.CONCURRENT_FORBID {
+		if !s.acquire(job.JobId) {
+			return
+		}
+		defer s.release(job.JobId)
+	}
+	result := Result{
+		Job:       job,
+		StartTime: time.Now(),
+	}
+	result.Err = s.invoke(job)
+	result.StopTime = time.Now()
+	s.mu.Lock()
+	handler := s.handler
+	s.mu.Unlock()
+	if handler != nil {
+		handler(result)
+	}
+}
+
+// invoke 调用目标，捕获调用过程中的panic
+func (s *Scheduler) invoke(job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	name, params, err := ParseInvokeTarget(job.InvokeTarget)
+	if err != nil {
+		return err
+	}
+	task, ok := GetTask(name)
+	if !ok {
+		return fmt.Errorf("调用目标%s未注册", name)
+	}
+	return task(context.Background(), params)
+}
+
+func (s *Scheduler) acquire(jobId int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[jobId] {
+		return false
+	}
+	s.running[jobId] = true
+	return true
+}
+
+func (s *Scheduler) release(jobId int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, jobId)
+}
+
+// misfireCount 根据计划执行错误策略计算需要补偿的执行次数
+func (s *Scheduler) misfireCount(job Job, schedule cron.Schedule) int {
+	if job.PrevTime.IsZero() {
+		return 0
+	}
+	now := time.Now()
+	count := 0
+	for next := schedule.Next(job.PrevTime); !next.IsZero() && next.Before(now) && count < maxMisfireCount; next = schedule.Next(next) {
+		count++
+	}
+	switch job.MisfirePolicy {
+	case job_key.MISFIRE_IGNORE_MISFIRES:
+		return count
+	case job_key.MISFIRE_FIRE_AND_PROCEED:
+		if count > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ValidCronExpression 校验cron表达式
+func ValidCronExpression(cronExpression string) error {
+	_, err := parser.Parse(cronExpression)
+	return err
+}
+
+// NextTimes 根据cron表达式计算接下来n次的执行时间
+func NextTimes(cronExpression string, n int) ([]time.Time, error) {
+	schedule, err := parser.Parse(cronExpression)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, 0, n)
+	next := time.Now()
+	for i := 0; i < n; i++ {
+		if next = schedule.Next(next); next.IsZero() {
+			break
+		}
+		times = append(times, next)
+	}
+	return times, nil
+}
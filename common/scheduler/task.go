@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Task 调用目标，params为调用目标字符串中括号内的参数
+type Task func(ctx context.Context, params string) error
+
+var (
+	tasks   = make(map[string]Task)
+	tasksMu sync.RWMutex
+)
+
+// RegisterTask 注册调用目标，同名调用目标会被覆盖
+func RegisterTask(name string, task Task) {
+	tasksMu.Lock()
+	defer tasksMu.Unlock()
+	tasks[name] = task
+}
+
+// GetTask 获取调用目标
+func GetTask(name string) (Task, bool) {
+	tasksMu.RLock()
+	defer tasksMu.RUnlock()
+	task, ok := tasks[name]
+	return task, ok
+}
+
+// ListTaskNames 已注册的调用目标名称
+func ListTaskNames() []string {
+	tasksMu.RLock()
+	defer tasksMu.RUnlock()
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseInvokeTarget 解析调用目标字符串
+//
+// 格式为name或name(params)，如cleanOperLog(30)，参数两端的引号会被去除
+func ParseInvokeTarget(invokeTarget string) (string, string, error) {
+	invokeTarget = strings.TrimSpace(invokeTarget)
+	index := strings.Index(invokeTarget, "(")
+	if index < 0 {
+		if invokeTarget == "" {
+			return "", "", errors.New("调用目标不能为空")
+		}
+		return invokeTarget, "", nil
+	}
+	if !strings.HasSuffix(invokeTarget, ")") {
+		return "", "", errors.New("调用目标格式错误，缺少右括号")
+	}
+	name := strings.TrimSpace(invokeTarget[:index])
+	if name == "" {
+		return "", "", errors.New("调用目标不能为空")
+	}
+	params := strings.TrimSpace(invokeTarget[index+1 : len(invokeTarget)-1])
+	params = strings.Trim(params, "'\"")
+	return name, params, nil
+}
+
+// ValidInvokeTarget 校验调用目标是否已注册
+func ValidInvokeTarget(invokeTarget string) error {
+	name, _, err := ParseInvokeTarget(invokeTarget)
+	if err != nil {
+		return err
+	}
+	if _, ok := GetTask(name); !ok {
+		return errors.New("调用目标" + name + "未注册")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package uploader
+
+import (
+	"errors"
+	"sync"
+)
+
+// Driver 存储驱动
+type Driver interface {
+	// Put 将文件保存到savePath目录下，返回文件访问地址
+	Put(savePath, urlPath, fileName string, file *File) (string, error)
+	// DefaultSavePath 未设置保存路径时使用的默认路径，datePath为当天日期目录
+	DefaultSavePath(datePath string) string
+}
+
+var (
+	drivers = map[string]Driver{
+		UploadLocalDriver: &LocalDriver{},
+		UploadOssDriver:   &OssDriver{},
+	}
+	driversMu sync.RWMutex
+)
+
+// RegisterDriver 注册存储驱动，同名驱动会被覆盖
+func RegisterDriver(name string, driver Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	drivers[name] = driver
+}
+
+// GetDriver 获取存储驱动
+func GetDriver(name string) (Driver, error) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	driver, ok := drivers[name]
+	if !ok {
+		return nil, errors.New("未找到存储驱动：" + name)
+	}
+	return driver, nil
+}
@@ -0,0 +1,49 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"github.com/hugo8680/goat/framework/config"
+	"os"
+)
+
+// LocalDriver 本地存储驱动
+type LocalDriver struct {
+}
+
+// Put 保存到本地
+func (d *LocalDriver) Put(savePath, urlPath, fileName string, file *File) (string, error) {
+	if config.GetSetting().System.Host == "" {
+		return "", errors.New("未找到域名，无法生成访问地址")
+	}
+	if _, err := os.Stat(savePath); err != nil {
+		if os.IsNotExist(err) {
+			if err := os.MkdirAll(savePath, 0755); err != nil {
+				return "", err
+			}
+		}
+	}
+	if err := os.WriteFile(savePath+fileName, file.FileContent, 0644); err != nil {
+		return "", err
+	}
+	return config.GetSetting().System.Host + "/" + urlPath + fileName, nil
+}
+
+// DefaultSavePath 默认保存到上传目录
+func (d *LocalDriver) DefaultSavePath(datePath string) string {
+	return config.GetSetting().System.UploadPath + datePath
+}
+
+// CheckWritable 检查上传目录是否可写，用于就绪检查
+func CheckWritable(ctx context.Context) error {
+	uploadPath := config.GetSetting().System.UploadPath
+	if err := os.MkdirAll(uploadPath, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(uploadPath, ".health-*")
+	if err != nil {
+		return err
+	}
+	_ = file.Close()
+	return os.Remove(file.Name())
+}
@@ -0,0 +1,76 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"github.com/hugo8680/goat/framework/config"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// OssDriver S3兼容对象存储驱动，支持MinIO、阿里云OSS、腾讯云COS等
+type OssDriver struct {
+	mu     sync.Mutex
+	client *minio.Client
+	key    string // 创建客户端时使用的连接配置，配置热更新后重新创建客户端
+}
+
+// Put 保存到对象存储
+func (d *OssDriver) Put(savePath, urlPath, fileName string, file *File) (string, error) {
+	client, err := d.getClient()
+	if err != nil {
+		return "", err
+	}
+	oss := config.GetSetting().Storage.Oss
+	objectName := strings.TrimPrefix(savePath+fileName, "/")
+	if _, err = client.PutObject(context.Background(), oss.Bucket, objectName, bytes.NewReader(file.FileContent), int64(len(file.FileContent)), minio.PutObjectOptions{
+		ContentType: file.FileHeader.Get("Content-Type"),
+	}); err != nil {
+		return "", err
+	}
+	return d.domain() + "/" + strings.TrimPrefix(urlPath+fileName, "/"), nil
+}
+
+// DefaultSavePath 默认保存到配置的路径前缀下
+func (d *OssDriver) DefaultSavePath(datePath string) string {
+	return config.GetSetting().Storage.Oss.BasePath + datePath
+}
+
+// getClient 初始化对象存储客户端，连接配置变更时重新创建
+func (d *OssDriver) getClient() (*minio.Client, error) {
+	oss := config.GetSetting().Storage.Oss
+	key := strings.Join([]string{oss.Endpoint, oss.AccessKey, oss.SecretKey, oss.Region, strconv.FormatBool(oss.UseSSL)}, "|")
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil && d.key == key {
+		return d.client, nil
+	}
+	client, err := minio.New(oss.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(oss.AccessKey, oss.SecretKey, ""),
+		Secure: oss.UseSSL,
+		Region: oss.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.client = client
+	d.key = key
+	return client, nil
+}
+
+// domain 访问域名，未配置时使用服务地址和存储桶拼接
+func (d *OssDriver) domain() string {
+	oss := config.GetSetting().Storage.Oss
+	if oss.Domain != "" {
+		return strings.TrimSuffix(oss.Domain, "/")
+	}
+	scheme := "http://"
+	if oss.UseSSL {
+		scheme = "https://"
+	}
+	return scheme + oss.Endpoint + "/" + oss.Bucket
+}
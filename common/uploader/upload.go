@@ -5,9 +5,9 @@ import (
 	"errors"
 	"github.com/hugo8680/goat/common/serializer/datetime"
 	"github.com/hugo8680/goat/framework/config"
+	"github.com/hugo8680/goat/framework/metrics"
 	"math/rand"
 	"net/textproto"
-	"os"
 	"strings"
 	"time"
 )
@@ -55,21 +55,29 @@ type Result struct {
 	Url          string `json:"url"`
 }
 
-var setting = config.GetSetting()
-
 // NewUploader 初始化上传对象
+//
+// 未设置保存路径和访问地址路径时，由存储驱动按当天日期生成
 func NewUploader(options ...UploadOption) *Uploader {
-	todayPath := time.Now().Format(datetime.DATE_FORMAT1) + "/"
 	// 配置默认驱动
 	c := &UploadConfig{
-		Driver:     UploadLocalDriver,
-		UrlPath:    setting.System.UploadPath + todayPath,
-		SavePath:   setting.System.UploadPath + todayPath,
+		Driver:     config.GetSetting().Storage.Driver,
 		RandomName: false,
 	}
+	if c.Driver == "" {
+		c.Driver = UploadLocalDriver
+	}
 	for _, option := range options {
 		option(c)
 	}
+	if c.SavePath == "" {
+		if driver, err := GetDriver(c.Driver); err == nil {
+			c.SavePath = driver.DefaultSavePath(time.Now().Format(datetime.DATE_FORMAT1) + "/")
+		}
+	}
+	if c.UrlPath == "" {
+		c.UrlPath = c.SavePath
+	}
 	return &Uploader{
 		Config: c,
 	}
@@ -126,11 +134,10 @@ func (u *Uploader) SetFile(file *File) *Uploader {
 
 // Save 保存文件
 func (u *Uploader) Save() (*Result, error) {
-	var err error
-	if setting.System.Host == "" {
-		return nil, errors.New("未找到域名，无法生成访问地址")
+	driver, err := GetDriver(u.Config.Driver)
+	if err != nil {
+		return nil, err
 	}
-	domain := setting.System.Host
 	if u.File == nil || len(u.File.FileContent) <= 0 {
 		return nil, errors.New("上传文件数据不全，无法保存")
 	}
@@ -150,14 +157,8 @@ func (u *Uploader) Save() (*Result, error) {
 	if err = u.checkLimitType(); err != nil {
 		return nil, err
 	}
-	switch u.Config.Driver {
-	case UploadLocalDriver:
-		err = u.saveToLocal(randomName)
-	case UploadOssDriver:
-		err = u.saveToOss()
-	default:
-		err = u.saveToLocal(randomName)
-	}
+	url, err := driver.Put(u.Config.SavePath, u.Config.UrlPath, randomName, u.File)
+	metrics.ObserveUpload(u.Config.Driver, len(u.File.FileContent), err == nil)
 	if err != nil {
 		return nil, err
 	}
@@ -168,8 +169,8 @@ func (u *Uploader) Save() (*Result, error) {
 		FileType:     u.File.FileType,
 		SavePath:     u.Config.SavePath,
 		UrlPath:      u.Config.UrlPath,
-		Url:          domain + "/" + u.Config.UrlPath + randomName,
-	}, err
+		Url:          url,
+	}, nil
 }
 
 // 检查文件大小
@@ -208,23 +209,3 @@ func (u *Uploader) generateRandomName() string {
 	}
 	return randomName
 }
-
-// 保存到本地
-func (u *Uploader) saveToLocal(randomName string) error {
-	if _, err := os.Stat(u.Config.SavePath); err != nil {
-		if os.IsNotExist(err) {
-			if err := os.MkdirAll(u.Config.SavePath, 0644); err != nil {
-				return err
-			}
-		}
-	}
-	return os.WriteFile(u.Config.SavePath+randomName, u.File.FileContent, 0644)
-}
-
-// 保存到Oss
-func (u *Uploader) saveToOss() error {
-
-	// TODO
-
-	return nil
-}
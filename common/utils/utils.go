@@ -42,12 +42,13 @@ func Filter[T interface{}](slice []T, condition func(T) bool) (result []T) {
 }
 
 // Desensitize 脱敏工具
+// 将第start至第end个字符替换为*，按字符而非字节计算位置
 func Desensitize(content string, start, end int) string {
 	if start < 0 || end < 0 || start > end {
 		return content
 	}
 	var contentRune []rune
-	for key, value := range content {
+	for key, value := range []rune(content) {
 		if key >= start && key <= end {
 			contentRune = append(contentRune, '*')
 		} else {
@@ -17,6 +17,63 @@
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for gen_table
+-- ----------------------------
+DROP TABLE IF EXISTS `gen_table`;
+CREATE TABLE `gen_table` (
+  `table_id` bigint NOT NULL AUTO_INCREMENT COMMENT '编号',
+  `table_name` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '表名称',
+  `table_comment` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '表描述',
+  `class_name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '实体类名称',
+  `tpl_category` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'crud' COMMENT '使用的模板：crud-单表操作',
+  `package_name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '生成包路径',
+  `module_name` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '生成模块名',
+  `business_name` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '生成业务名',
+  `function_name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '生成功能名',
+  `function_author` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '生成功能作者',
+  `gen_type` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '生成代码方式：0-zip压缩包；1-自定义路径',
+  `gen_path` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '/' COMMENT '生成路径（不填默认项目路径）',
+  `options` varchar(1000) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '其它生成选项',
+  `create_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '创建者',
+  `create_time` datetime NOT NULL COMMENT '创建时间',
+  `update_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '更新者',
+  `update_time` datetime DEFAULT NULL COMMENT '更新时间',
+  `remark` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '备注',
+  PRIMARY KEY (`table_id`) USING BTREE
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='代码生成业务表';
+
+-- ----------------------------
+-- Table structure for gen_table_column
+-- ----------------------------
+DROP TABLE IF EXISTS `gen_table_column`;
+CREATE TABLE `gen_table_column` (
+  `column_id` bigint NOT NULL AUTO_INCREMENT COMMENT '编号',
+  `table_id` bigint NOT NULL DEFAULT '0' COMMENT '归属表编号',
+  `column_name` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '列名称',
+  `column_comment` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '列描述',
+  `column_type` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '列类型',
+  `go_type` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'Go类型',
+  `go_field` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'Go字段名',
+  `is_pk` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '是否主键：0-否；1-是',
+  `is_increment` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '是否自增：0-否；1-是',
+  `is_required` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '是否必填：0-否；1-是',
+  `is_insert` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '是否为新增字段：0-否；1-是',
+  `is_edit` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '是否为编辑字段：0-否；1-是',
+  `is_list` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '是否为列表字段：0-否；1-是',
+  `is_query` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '是否为查询字段：0-否；1-是',
+  `query_type` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'EQ' COMMENT '查询方式：EQ-等于；NE-不等于；GT-大于；GTE-大于等于；LT-小于；LTE-小于等于；LIKE-模糊；BETWEEN-范围',
+  `html_type` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '显示类型：input-文本框；textarea-文本域；select-下拉框；radio-单选框；checkbox-复选框；datetime-日期控件；imageUpload-图片上传；fileUpload-文件上传；editor-富文本控件',
+  `dict_type` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '字典类型',
+  `sort` int NOT NULL DEFAULT '0' COMMENT '排序',
+  `create_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '创建者',
+  `create_time` datetime NOT NULL COMMENT '创建时间',
+  `update_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '更新者',
+  `update_time` datetime DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`column_id`) USING BTREE,
+  KEY `idx_table_id` (`table_id`) USING BTREE
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='代码生成业务表字段';
+
 -- ----------------------------
 -- Table structure for sys_config
 -- ----------------------------
@@ -33,7 +90,7 @@ CREATE TABLE `sys_config` (
   `update_time` datetime DEFAULT NULL COMMENT '更新时间',
   `remark` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '备注',
   PRIMARY KEY (`config_id`) USING BTREE
-) ENGINE=InnoDB AUTO_INCREMENT=6 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='参数配置表';
+) ENGINE=InnoDB AUTO_INCREMENT=13 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='参数配置表';
 
 -- ----------------------------
 -- Records of sys_config
@@ -44,6 +101,13 @@ INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_valu
 INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (3, '主框架页-侧边栏主题', 'sys.index.sideTheme', 'theme-dark', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '深色主题theme-dark，浅色主题theme-light');
 INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (4, '账号自助-验证码开关', 'sys.account.captchaEnabled', 'true', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '是否开启验证码功能（true开启，false关闭）');
 INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (5, '账号自助-是否开启用户注册功能', 'sys.account.registerUser', 'false', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '是否开启注册用户功能（true开启，false关闭）');
+INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (6, '账号自助-强制两步验证的角色', 'sys.account.twoFactorRoles', '', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '拥有这些角色的用户登录时必须启用两步验证，多个角色权限字符用逗号分隔，为空不强制');
+INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (7, '密码策略-最小长度', 'sys.account.pwdMinLength', '6', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '密码最小长度');
+INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (8, '密码策略-必须包含的字符类型', 'sys.account.pwdCharTypes', '', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '可选lower（小写字母）、upper（大写字母）、digit（数字）、symbol（特殊字符），多个用逗号分隔，为空不限制');
+INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (9, '密码策略-禁用词', 'sys.account.pwdBannedWords', 'password,qwerty', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '密码不能包含用户名及这些词，忽略大小写，多个用逗号分隔');
+INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (10, '密码策略-历史密码数', 'sys.account.pwdHistory', '3', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '不能重复使用最近N次的密码，0不限制');
+INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (11, '密码策略-密码有效天数', 'sys.account.pwdMaxAge', '0', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '密码超过天数后登录须修改，0不限制');
+INSERT INTO `sys_config` (`config_id`, `config_name`, `config_key`, `config_value`, `config_type`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (12, '密码策略-初始密码强制修改', 'sys.account.initPwdModify', 'true', 'Y', 'admin', '2025-10-06 02:44:02', '', NULL, '管理员新增、导入用户及重置密码后，用户登录须修改密码（true开启，false关闭）');
 COMMIT;
 
 -- ----------------------------
@@ -176,6 +240,56 @@ INSERT INTO `sys_dict_type` (`dict_id`, `dict_name`, `dict_type`, `status`, `cre
 INSERT INTO `sys_dict_type` (`dict_id`, `dict_name`, `dict_type`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (11, 'test', 'test', '0', 'admin', '2025-10-17 03:37:58', '', '2025-10-17 03:37:58', '');
 COMMIT;
 
+-- ----------------------------
+-- Table structure for sys_job
+-- ----------------------------
+DROP TABLE IF EXISTS `sys_job`;
+CREATE TABLE `sys_job` (
+  `job_id` bigint NOT NULL AUTO_INCREMENT COMMENT '任务id',
+  `job_name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '任务名称',
+  `job_group` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'DEFAULT' COMMENT '任务组名：DEFAULT-默认；SYSTEM-系统',
+  `invoke_target` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '调用目标字符串',
+  `cron_expression` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'cron执行表达式',
+  `misfire_policy` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '3' COMMENT '计划执行错误策略：1-立即执行；2-执行一次；3-放弃执行',
+  `concurrent` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '1' COMMENT '是否并发执行：0-允许；1-禁止',
+  `status` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '状态：0-正常；1-暂停',
+  `create_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '创建者',
+  `create_time` datetime NOT NULL COMMENT '创建时间',
+  `update_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '更新者',
+  `update_time` datetime DEFAULT NULL COMMENT '更新时间',
+  `remark` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '备注',
+  PRIMARY KEY (`job_id`) USING BTREE
+) ENGINE=InnoDB AUTO_INCREMENT=4 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='定时任务调度表';
+
+-- ----------------------------
+-- Records of sys_job
+-- ----------------------------
+BEGIN;
+INSERT INTO `sys_job` (`job_id`, `job_name`, `job_group`, `invoke_target`, `cron_expression`, `misfire_policy`, `concurrent`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (1, '清理操作日志', 'SYSTEM', 'cleanOperLog(30)', '0 0 2 * * ?', '3', '1', '1', 'admin', '2025-10-06 02:44:02', '', NULL, '清理30天前的操作日志');
+INSERT INTO `sys_job` (`job_id`, `job_name`, `job_group`, `invoke_target`, `cron_expression`, `misfire_policy`, `concurrent`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (2, '清理登录日志', 'SYSTEM', 'cleanLoginLog(30)', '0 10 2 * * ?', '3', '1', '1', 'admin', '2025-10-06 02:44:02', '', NULL, '清理30天前的登录日志');
+INSERT INTO `sys_job` (`job_id`, `job_name`, `job_group`, `invoke_target`, `cron_expression`, `misfire_policy`, `concurrent`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `remark`) VALUES (3, '清理调度日志', 'SYSTEM', 'cleanJobLog(7)', '0 20 2 * * ?', '3', '1', '1', 'admin', '2025-10-06 02:44:02', '', NULL, '清理7天前的调度日志');
+COMMIT;
+
+-- ----------------------------
+-- Table structure for sys_job_log
+-- ----------------------------
+DROP TABLE IF EXISTS `sys_job_log`;
+CREATE TABLE `sys_job_log` (
+  `job_log_id` bigint NOT NULL AUTO_INCREMENT COMMENT '任务日志id',
+  `job_id` bigint NOT NULL DEFAULT '0' COMMENT '任务id',
+  `job_name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '任务名称',
+  `job_group` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '任务组名',
+  `invoke_target` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '调用目标字符串',
+  `job_message` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '日志信息',
+  `status` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '执行状态：0-正常；1-失败',
+  `exception_info` varchar(2000) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '异常信息',
+  `start_time` datetime NOT NULL COMMENT '开始时间',
+  `stop_time` datetime NOT NULL COMMENT '结束时间',
+  `create_time` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`job_log_id`) USING BTREE,
+  KEY `idx_sys_job_log_ji` (`job_id`) USING BTREE
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='定时任务调度日志表';
+
 -- ----------------------------
 -- Table structure for sys_login_log
 -- ----------------------------
@@ -190,9 +304,11 @@ CREATE TABLE `sys_login_log` (
   `status` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '状态：0-成功；1-失败',
   `msg` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '提示消息',
   `login_time` datetime NOT NULL COMMENT '访问时间',
+  `request_id` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '请求id',
   PRIMARY KEY (`info_id`) USING BTREE,
   KEY `idx_sys_logininfor_s` (`status`) USING BTREE,
-  KEY `idx_sys_logininfor_lt` (`login_time`) USING BTREE
+  KEY `idx_sys_logininfor_lt` (`login_time`) USING BTREE,
+  KEY `idx_sys_logininfor_ri` (`request_id`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=62 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='系统访问记录';
 
 -- ----------------------------
@@ -289,13 +405,15 @@ CREATE TABLE `sys_menu` (
   `delete_time` datetime DEFAULT NULL COMMENT '删除时间',
   `remark` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '备注',
   PRIMARY KEY (`menu_id`) USING BTREE
-) ENGINE=InnoDB AUTO_INCREMENT=1046 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='菜单权限表';
+) ENGINE=InnoDB AUTO_INCREMENT=1061 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='菜单权限表';
 
 -- ----------------------------
 -- Records of sys_menu
 -- ----------------------------
 BEGIN;
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1, '系统管理', 0, 1, 'system', NULL, '', '', 1, 0, 'M', '0', '', 'system', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '系统管理目录');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (2, '系统监控', 0, 2, 'monitor', NULL, '', '', 1, 0, 'M', '0', '', 'monitor', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '系统监控目录');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (3, '系统工具', 0, 3, 'tool', NULL, '', '', 1, 0, 'M', '0', '', 'tool', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '系统工具目录');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (100, '用户管理', 1, 1, 'user', 'system/user/index', '', '', 1, 0, 'C', '0', 'system:user:list', 'user', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '用户管理菜单');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (101, '角色管理', 1, 2, 'role', 'system/role/index', '', '', 1, 0, 'C', '0', 'system:role:list', 'peoples', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '角色管理菜单');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (102, '菜单管理', 1, 3, 'menu', 'system/menu/index', '', '', 1, 0, 'C', '0', 'system:menu:list', 'tree-table', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '菜单管理菜单');
@@ -304,6 +422,12 @@ INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (105, '字典管理', 1, 6, 'dict', 'system/dict/index', '', '', 1, 0, 'C', '0', 'system:dict:list', 'dict', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '字典管理菜单');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (106, '参数设置', 1, 7, 'config', 'system/config/index', '', '', 1, 0, 'C', '0', 'system:config:list', 'edit', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '参数设置菜单');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (108, '日志管理', 1, 9, 'log', '', '', '', 1, 0, 'M', '0', '', 'log', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '日志管理菜单');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (109, '在线用户', 2, 1, 'online', 'monitor/online/index', '', '', 1, 0, 'C', '0', 'monitor:online:list', 'online', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '在线用户菜单');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (110, '定时任务', 2, 2, 'job', 'monitor/job/index', '', '', 1, 0, 'C', '0', 'monitor:job:list', 'job', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '定时任务菜单');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (111, '服务监控', 2, 4, 'server', 'monitor/server/index', '', '', 1, 0, 'C', '0', 'monitor:server:list', 'server', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '服务监控菜单');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (113, '缓存监控', 2, 5, 'cache', 'monitor/cache/index', '', '', 1, 0, 'C', '0', 'monitor:cache:list', 'redis', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '缓存监控菜单');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (114, '缓存列表', 2, 6, 'cacheList', 'monitor/cache/list', '', '', 1, 0, 'C', '0', 'monitor:cache:list', 'redis-list', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '缓存列表菜单');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (115, '代码生成', 3, 1, 'gen', 'tool/gen/index', '', '', 1, 0, 'C', '0', 'tool:gen:list', 'code', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '代码生成菜单');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (500, '操作日志', 108, 1, 'operLog', 'system/operLog/index', '', '', 1, 0, 'C', '0', 'system:operLog:list', 'form', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '操作日志菜单');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (501, '登录日志', 108, 2, 'loginLog', 'system/loginLog/index', '', '', 1, 0, 'C', '0', 'system:loginLog:list', 'IconDesktop', '0', 'admin', '2025-10-06 02:44:02', 'admin', '2025-10-18 02:28:34', NULL, '登录日志菜单');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1000, '用户查询', 100, 1, '', '', '', '', 1, 0, 'F', '0', 'system:user:query', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
@@ -348,6 +472,19 @@ INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1043, '登录删除', 501, 2, '#', '', '', '', 1, 0, 'F', '0', 'system: loginLog:remove', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1044, '日志导出', 501, 3, '#', '', '', '', 1, 0, 'F', '0', 'system: loginLog:export', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
 INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1045, '账户解锁', 501, 4, '#', '', '', '', 1, 0, 'F', '0', 'system: loginLog:unlock', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1048, '单条强退', 109, 1, '#', '', '', '', 1, 0, 'F', '0', 'monitor:online:forceLogout', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1049, '生成查询', 115, 1, '', '', '', '', 1, 0, 'F', '0', 'tool:gen:query', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1050, '生成修改', 115, 2, '', '', '', '', 1, 0, 'F', '0', 'tool:gen:edit', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1051, '生成删除', 115, 3, '', '', '', '', 1, 0, 'F', '0', 'tool:gen:remove', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1052, '导入代码', 115, 4, '', '', '', '', 1, 0, 'F', '0', 'tool:gen:import', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1053, '预览代码', 115, 5, '', '', '', '', 1, 0, 'F', '0', 'tool:gen:preview', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1054, '生成代码', 115, 6, '', '', '', '', 1, 0, 'F', '0', 'tool:gen:code', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1055, '任务查询', 110, 1, '', '', '', '', 1, 0, 'F', '0', 'monitor:job:query', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1056, '任务新增', 110, 2, '', '', '', '', 1, 0, 'F', '0', 'monitor:job:add', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1057, '任务修改', 110, 3, '', '', '', '', 1, 0, 'F', '0', 'monitor:job:edit', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1058, '任务删除', 110, 4, '', '', '', '', 1, 0, 'F', '0', 'monitor:job:remove', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1059, '状态修改', 110, 5, '', '', '', '', 1, 0, 'F', '0', 'monitor:job:changeStatus', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
+INSERT INTO `sys_menu` (`menu_id`, `menu_name`, `parent_id`, `order_num`, `path`, `component`, `query`, `route_name`, `is_frame`, `is_cache`, `menu_type`, `visible`, `perms`, `icon`, `status`, `create_by`, `create_time`, `update_by`, `update_time`, `delete_time`, `remark`) VALUES (1060, '任务导出', 110, 6, '', '', '', '', 1, 0, 'F', '0', 'monitor:job:export', '#', '0', 'admin', '2025-10-06 02:44:02', '', NULL, NULL, '');
 COMMIT;
 
 -- ----------------------------
@@ -371,10 +508,12 @@ CREATE TABLE `sys_oper_log` (
   `error_msg` varchar(2000) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '错误消息',
   `oper_time` datetime NOT NULL COMMENT '操作时间',
   `cost_time` bigint NOT NULL DEFAULT '0' COMMENT '消耗时间',
+  `request_id` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '请求id',
   PRIMARY KEY (`oper_id`) USING BTREE,
   KEY `idx_sys_oper_log_bt` (`business_type`) USING BTREE,
   KEY `idx_sys_oper_log_s` (`status`) USING BTREE,
-  KEY `idx_sys_oper_log_ot` (`oper_time`) USING BTREE
+  KEY `idx_sys_oper_log_ot` (`oper_time`) USING BTREE,
+  KEY `idx_sys_oper_log_ri` (`request_id`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=28 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='操作日志记录';
 
 -- ----------------------------
@@ -410,6 +549,19 @@ INSERT INTO `sys_oper_log` (`oper_id`, `title`, `business_type`, `method`, `requ
 INSERT INTO `sys_oper_log` (`oper_id`, `title`, `business_type`, `method`, `request_method`, `oper_name`, `dept_name`, `oper_url`, `oper_ip`, `oper_location`, `oper_param`, `json_result`, `status`, `error_msg`, `oper_time`, `cost_time`) VALUES (27, '修改部门', 2, 'github.com/hugo8680/goat/api/controller/admin.(*DeptController).Update-fm', 'PUT', '超级管理员', '研发部门', '/api/system/dept', '127.0.0.1', '内网地址', '{\"ancestors\":\"0,100,101\",\"createTime\":\"2025-10-06 02:44:02\",\"deptId\":105,\"deptName\":\"测试部门\",\"email\":\"zhang8680@outlook.com\",\"leader\":\"hugo\",\"orderNum\":3,\"parentId\":101,\"phone\":\"18243088680\",\"status\":\"0\"}', '{\"code\":200,\"msg\":\"成功\"}', '0', '', '2025-10-19 00:55:15', 2);
 COMMIT;
 
+-- ----------------------------
+-- Table structure for sys_password_history
+-- ----------------------------
+DROP TABLE IF EXISTS `sys_password_history`;
+CREATE TABLE `sys_password_history` (
+  `history_id` bigint NOT NULL AUTO_INCREMENT COMMENT '历史id',
+  `user_id` bigint NOT NULL COMMENT '用户id',
+  `password` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '密码',
+  `create_time` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`history_id`) USING BTREE,
+  KEY `idx_sys_password_history_ui` (`user_id`) USING BTREE
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户历史密码表';
+
 -- ----------------------------
 -- Table structure for sys_post
 -- ----------------------------
@@ -582,6 +734,11 @@ CREATE TABLE `sys_user` (
   `login_ip` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '最后登录ip',
   `login_date` datetime DEFAULT NULL COMMENT '最后登录时间',
   `status` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '状态：0-正常；1-停用',
+  `totp_secret` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '两步验证密钥',
+  `totp_enabled` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '两步验证：0-未启用；1-已启用',
+  `recovery_codes` varchar(1000) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '两步验证恢复码摘要，逗号分隔',
+  `pwd_update_time` datetime DEFAULT NULL COMMENT '密码最后修改时间',
+  `pwd_must_change` char(1) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '下次登录须修改密码：0-否；1-是',
   `create_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '创建者',
   `create_time` datetime NOT NULL COMMENT '创建时间',
   `update_by` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '更新者',
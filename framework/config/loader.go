@@ -0,0 +1,239 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix 环境变量前缀，如db.password对应GOAT_DB_PASSWORD
+	EnvPrefix = "GOAT"
+	// ProfileEnv 指定环境的环境变量
+	ProfileEnv = EnvPrefix + "_PROFILE"
+)
+
+// 匹配${VAR}及${VAR:默认值}
+var placeholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::([^}]*))?}`)
+
+// Load 从配置文件加载系统配置
+//
+// path 基础配置文件路径，如application.yml
+//
+// profile 环境，为空时读取GOAT_PROFILE环境变量，非空时将application-{profile}.yml合并到基础配置之上
+//
+// 加载顺序为基础配置、环境配置、环境变量，后加载的覆盖先加载的，加载完成后校验配置
+func Load(path string, profile string) (*Setting, error) {
+	setting, err := parse(path, profile)
+	if err != nil {
+		return nil, err
+	}
+	SetSetting(setting)
+	return setting, nil
+}
+
+// Reload 重新加载配置文件
+//
+// 数据源、服务端口等启动时生效、需要重启的配置保持不变，其余配置整体替换后通知订阅者，加载失败时保留原配置
+func Reload(path string, profile string) (*Setting, error) {
+	setting, err := parse(path, profile)
+	if err != nil {
+		return nil, err
+	}
+	oldSetting := GetSetting()
+	changed := make([]string, 0)
+	keep(&changed, "system.name", &setting.System.Name, oldSetting.System.Name)
+	keep(&changed, "system.uploadPath", &setting.System.UploadPath, oldSetting.System.UploadPath)
+	keep(&changed, "server", &setting.Server, oldSetting.Server)
+	// 日志仅级别支持热更新
+	keep(&changed, "log.format", &setting.Log.Format, oldSetting.Log.Format)
+	keep(&changed, "log.output", &setting.Log.Output, oldSetting.Log.Output)
+	keep(&changed, "log.slowThreshold", &setting.Log.SlowThreshold, oldSetting.Log.SlowThreshold)
+	keep(&changed, "log.file", &setting.Log.File, oldSetting.Log.File)
+	keep(&changed, "trace", &setting.Trace, oldSetting.Trace)
+	keep(&changed, "metrics.enabled", &setting.Metrics.Enabled, oldSetting.Metrics.Enabled)
+	keep(&changed, "metrics.path", &setting.Metrics.Path, oldSetting.Metrics.Path)
+	keep(&changed, "logQueue", &setting.LogQueue, oldSetting.LogQueue)
+	keep(&changed, "ip", &setting.Ip, oldSetting.Ip)
+	keep(&changed, "db", &setting.DB, oldSetting.DB)
+	keep(&changed, "cache", &setting.Cache, oldSetting.Cache)
+	if len(changed) > 0 {
+		slog.Warn(strings.Join(changed, "、")+"配置需要重启后生效", "module", "config")
+	}
+	SetSetting(setting)
+	return setting, nil
+}
+
+// keep 需要重启才能生效的配置保持原值，有变更时记录配置项
+func keep[T any](changed *[]string, key string, value *T, oldValue T) {
+	if !reflect.DeepEqual(*value, oldValue) {
+		*changed = append(*changed, key)
+		*value = oldValue
+	}
+}
+
+// parse 解析并校验配置
+func parse(path string, profile string) (*Setting, error) {
+	if profile == "" {
+		profile = os.Getenv(ProfileEnv)
+	}
+	setting := &Setting{
+		Profile: profile,
+	}
+	if err := loadFile(path, setting); err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		if err := loadFile(ProfilePath(path, profile), setting); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(setting).Elem(), EnvPrefix); err != nil {
+		return nil, err
+	}
+	if err := setting.Validate(); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+// ProfilePath 环境配置文件路径，如application.yml对应application-dev.yml
+func ProfilePath(path string, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + profile + ext
+}
+
+// loadFile 读取配置文件并解析到setting，文件中未出现的字段保持原值
+func loadFile(path string, setting *Setting) error {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(file, &node); err != nil {
+		return err
+	}
+	if node.Kind == 0 {
+		return nil
+	}
+	interpolate(&node)
+	return node.Decode(setting)
+}
+
+// interpolate 替换配置中标量值的${VAR}占位符，环境变量不存在时使用默认值
+//
+// 在解析后的节点上替换，值中的#、:、*等字符不会改变配置结构
+func interpolate(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		value := placeholder.ReplaceAllStringFunc(node.Value, func(match string) string {
+			groups := placeholder.FindStringSubmatch(match)
+			if value, ok := os.LookupEnv(groups[1]); ok {
+				return value
+			}
+			return groups[2]
+		})
+		if value != node.Value {
+			node.Value = value
+			// 未加引号的值按替换后的内容重新推断类型，如${PORT:8080}解析为整数
+			if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				node.Tag = ""
+			}
+		}
+		return
+	}
+	for _, child := range node.Content {
+		interpolate(child)
+	}
+}
+
+// applyEnv 使用环境变量覆盖配置，变量名由前缀和yaml键名转换为大写下划线格式拼接而成
+func applyEnv(value reflect.Value, prefix string) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" || name == "" {
+			continue
+		}
+		key := prefix + "_" + envName(name)
+		fieldValue := value.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(fieldValue, key); err != nil {
+				return err
+			}
+			continue
+		}
+		env, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := setValue(fieldValue, env); err != nil {
+			return &EnvError{Key: key, Err: err}
+		}
+	}
+	return nil
+}
+
+// envName 将驼峰格式的键名转换为大写下划线格式，如expireIn转换为EXPIRE_IN
+func envName(name string) string {
+	var builder strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			builder.WriteByte('_')
+		}
+		builder.WriteRune(unicode.ToUpper(r))
+	}
+	return builder.String()
+}
+
+func setValue(value reflect.Value, env string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(env)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(env, 10, 64)
+		if err != nil {
+			return err
+		}
+		value.SetInt(number)
+	case reflect.Bool:
+		boolean, err := strconv.ParseBool(env)
+		if err != nil {
+			return err
+		}
+		value.SetBool(boolean)
+	case reflect.Float32, reflect.Float64:
+		number, err := strconv.ParseFloat(env, 64)
+		if err != nil {
+			return err
+		}
+		value.SetFloat(number)
+	case reflect.Slice:
+		// 字符串切片使用逗号分隔
+		if value.Type().Elem().Kind() == reflect.String {
+			value.Set(reflect.ValueOf(strings.Split(env, ",")))
+		}
+	}
+	return nil
+}
+
+// EnvError 环境变量解析错误
+type EnvError struct {
+	Key string
+	Err error
+}
+
+func (e *EnvError) Error() string {
+	return "环境变量" + e.Key + "解析失败：" + e.Err.Error()
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
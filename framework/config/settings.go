@@ -1,15 +1,17 @@
 package config
 
 import (
-	"os"
-
-	"gopkg.in/yaml.v3"
+	"sync"
+	"sync/atomic"
 )
 
 // Setting 系统配置
 //
-// 从根目录的`application.yml`读取
+// 默认从根目录的`application.yml`读取，可通过环境配置文件及环境变量覆盖
 type Setting struct {
+	// 当前环境，如dev、prod
+	Profile string `yaml:"-"`
+
 	// 系统配置
 	System struct {
 		// 名称
@@ -28,8 +30,135 @@ type Setting struct {
 		Port int `yaml:"port"`
 		// 模式，可选值：debug、test、release
 		Mode string `yaml:"mode"`
+		// 读取请求超时时间，单位秒（默认30秒）
+		ReadTimeout int `yaml:"readTimeout"`
+		// 写入响应超时时间，单位秒（默认60秒）
+		WriteTimeout int `yaml:"writeTimeout"`
+		// 空闲连接超时时间，单位秒（默认120秒）
+		IdleTimeout int `yaml:"idleTimeout"`
+		// 优雅关闭等待时间，单位秒（默认10秒）
+		ShutdownTimeout int `yaml:"shutdownTimeout"`
+		// 关闭钩子执行时间，在等待请求结束后单独计时，单位秒（默认10秒）
+		HookTimeout int `yaml:"hookTimeout"`
+		// 受信任的代理ip或网段，仅来自这些地址的请求才读取X-Forwarded-For获取客户端ip，为空时使用连接地址
+		TrustedProxies []string `yaml:"trustedProxies"`
 	} `yaml:"server"`
 
+	// 跨域配置
+	Cors struct {
+		// 允许的来源，为空时允许所有来源
+		AllowOrigins []string `yaml:"allowOrigins"`
+	} `yaml:"cors"`
+
+	// 日志配置
+	Log struct {
+		// 日志级别，可选值：debug、info、warn、error
+		Level string `yaml:"level"`
+		// 输出格式，可选值：text、json
+		Format string `yaml:"format"`
+		// 输出位置，可选值：console、file、both
+		Output string `yaml:"output"`
+		// 慢查询阈值，单位毫秒，0为不记录慢查询
+		SlowThreshold int `yaml:"slowThreshold"`
+		// 日志文件配置
+		File struct {
+			// 文件路径
+			Path string `yaml:"path"`
+			// 单个文件最大大小，单位MB
+			MaxSize int `yaml:"maxSize"`
+			// 保留的旧文件最大数量，0为不限制
+			MaxBackups int `yaml:"maxBackups"`
+			// 旧文件保留天数，0为不限制
+			MaxAge int `yaml:"maxAge"`
+			// 是否压缩旧文件
+			Compress bool `yaml:"compress"`
+			// 是否每天切割
+			Daily bool `yaml:"daily"`
+		} `yaml:"file"`
+	} `yaml:"log"`
+
+	// 链路追踪配置
+	Trace struct {
+		// 是否启用
+		Enabled bool `yaml:"enabled"`
+		// 导出方式，可选值：otlp、stdout
+		Exporter string `yaml:"exporter"`
+		// OTLP HTTP接收地址，不包含协议，如localhost:4318
+		Endpoint string `yaml:"endpoint"`
+		// 是否使用http连接
+		Insecure bool `yaml:"insecure"`
+		// 服务名称，为空时使用system.name
+		ServiceName string `yaml:"serviceName"`
+		// 采样率，0-1
+		SampleRate float64 `yaml:"sampleRate"`
+	} `yaml:"trace"`
+
+	// 监控指标配置
+	Metrics struct {
+		// 是否启用
+		Enabled bool `yaml:"enabled"`
+		// 指标接口路径
+		Path string `yaml:"path"`
+		// 允许访问的ip或网段，如127.0.0.1、10.0.0.0/8
+		AllowIps []string `yaml:"allowIps"`
+		// basic认证用户名，为空时不启用basic认证
+		Username string `yaml:"username"`
+		// basic认证密码
+		Password string `yaml:"password"`
+	} `yaml:"metrics"`
+
+	// 健康检查配置
+	Health struct {
+		// 单项就绪检查超时时间，单位秒（默认3秒）
+		Timeout int `yaml:"timeout"`
+		// 关闭前等待时间，单位秒，期间就绪检查返回未就绪，便于负载均衡摘除流量（默认0秒）
+		ShutdownDelay int `yaml:"shutdownDelay"`
+	} `yaml:"health"`
+
+	// 异步日志配置，操作日志及登录日志先写入内存队列，由后台协程批量入库
+	LogQueue struct {
+		// 队列容量（默认10000）
+		Size int `yaml:"size"`
+		// 单批写入条数（默认100）
+		BatchSize int `yaml:"batchSize"`
+		// 未满一批时的写入间隔，单位毫秒（默认1000毫秒）
+		FlushInterval int `yaml:"flushInterval"`
+		// 写入协程数（默认2）
+		Workers int `yaml:"workers"`
+		// 队列满时的最长等待时间，单位毫秒，超时后丢弃日志，为0时不等待直接丢弃
+		EnqueueTimeout int `yaml:"enqueueTimeout"`
+	} `yaml:"logQueue"`
+
+	// 操作日志配置
+	OperLog struct {
+		// 完全脱敏的字段名，不区分大小写，匹配任意层级，在内置字段名基础上追加
+		MaskKeys []string `yaml:"maskKeys"`
+		// 完全脱敏的JSON路径，层级用.分隔，数组不占层级，如data.user.password
+		MaskPaths []string `yaml:"maskPaths"`
+		// 按正则部分脱敏，保留匹配内容的前后若干位，正则包含分组时仅脱敏第一个分组
+		MaskPatterns []struct {
+			Regex      string `yaml:"regex"`
+			KeepPrefix int    `yaml:"keepPrefix"`
+			KeepSuffix int    `yaml:"keepSuffix"`
+		} `yaml:"maskPatterns"`
+		// 请求参数及返回结果的最大长度，超出部分截断（默认2000）
+		MaxLength int `yaml:"maxLength"`
+	} `yaml:"operLog"`
+
+	// ip归属地配置
+	Ip struct {
+		// 查询方式，按顺序查询直到查到结果，可选值：ip2region、mmdb、http，为空时不查询归属地
+		Providers []string `yaml:"providers"`
+		// ip2region xdb文件路径，仅支持IPv4
+		Ip2regionPath string `yaml:"ip2regionPath"`
+		// MaxMind mmdb文件路径，支持IPv4及IPv6
+		MmdbPath string `yaml:"mmdbPath"`
+		// mmdb名称语言（默认zh-CN）
+		MmdbLanguage string `yaml:"mmdbLanguage"`
+		// 缓存条数（默认10000）
+		CacheSize int `yaml:"cacheSize"`
+	} `yaml:"ip"`
+
 	// 数据库配置
 	DB struct {
 		Host string `yaml:"host"`
@@ -70,32 +199,133 @@ type Setting struct {
 			Secret string `yaml:"secret"`
 			// 令牌有效期（默认30分钟）
 			ExpireIn int `yaml:"expireIn"`
+			// 令牌模式，sliding：单一令牌临期自动续期，refresh：短期访问令牌加刷新令牌，默认sliding
+			Mode string `yaml:"mode"`
+			// 各客户端类型的令牌有效期，仅refresh模式生效
+			Clients struct {
+				// 网页端
+				Web TokenExpire `yaml:"web"`
+				// 移动端
+				Mobile TokenExpire `yaml:"mobile"`
+			} `yaml:"clients"`
 		} `yaml:"token"`
 		// 密码配置
 		Password struct {
-			// 密码最大错误次数
+			// 同一ip下同一账号的密码最大错误次数（默认5次）
 			MaxRetryCount int `yaml:"maxRetryCount"`
-			// 密码锁定时间（默认10分钟）
+			// 首次锁定时间，再次锁定时翻倍（默认10分钟）
 			LockTime int `yaml:"lockTime"`
 		} `yaml:"password"`
+		// 登录限流配置，按ip、账号及ip加账号分别统计滑动窗口内的登录失败次数，ip及ip加账号达到上限后锁定，账号达到上限后须输入验证码并延迟响应
+		Throttle struct {
+			// 滑动窗口，单位分钟（默认15分钟）
+			Window int `yaml:"window"`
+			// 同一ip最大失败次数，限制对多个账号尝试同一密码（默认30次）
+			IpMaxRetry int `yaml:"ipMaxRetry"`
+			// 同一账号最大失败次数，限制多个ip尝试同一账号，达到后不锁定账号，须输入图形验证码且每次登录延迟响应（默认20次）
+			UserMaxRetry int `yaml:"userMaxRetry"`
+			// 最长锁定时间，单位分钟（默认1440分钟）
+			MaxLockTime int `yaml:"maxLockTime"`
+			// 失败次数达到后登录须输入图形验证码，关闭验证码功能时同样生效（默认3次）
+			CaptchaAfter int `yaml:"captchaAfter"`
+		} `yaml:"throttle"`
+		// 登录会话配置
+		Session struct {
+			// 会话策略，multiple：不限制，limit：每个用户最多maxCount个会话，超出时踢出最早登录的会话，single：新登录踢出之前的会话，默认multiple
+			Policy string `yaml:"policy"`
+			// 每个用户最大会话数，仅limit策略生效
+			MaxCount int `yaml:"maxCount"`
+		} `yaml:"session"`
+		// 超级管理员配置，拥有全部权限和数据权限，两者都为空时默认角色为admin
+		SuperAdmin struct {
+			// 超级管理员角色权限字符
+			Roles []string `yaml:"roles"`
+			// 超级管理员用户名
+			Users []string `yaml:"users"`
+		} `yaml:"superAdmin"`
 	} `yaml:"auth"`
+
+	// 文件存储配置
+	Storage struct {
+		// 存储驱动，可选值：local、oss
+		Driver string `yaml:"driver"`
+		// S3兼容对象存储配置，支持MinIO、阿里云OSS、腾讯云COS等
+		Oss struct {
+			// 服务地址，不包含协议，如localhost:9000
+			Endpoint string `yaml:"endpoint"`
+			// 访问密钥
+			AccessKey string `yaml:"accessKey"`
+			// 私有密钥
+			SecretKey string `yaml:"secretKey"`
+			// 存储桶名称
+			Bucket string `yaml:"bucket"`
+			// 区域
+			Region string `yaml:"region"`
+			// 是否使用https
+			UseSSL bool `yaml:"useSSL"`
+			// 访问域名，为空时使用服务地址和存储桶拼接
+			Domain string `yaml:"domain"`
+			// 文件保存路径前缀
+			BasePath string `yaml:"basePath"`
+		} `yaml:"oss"`
+	} `yaml:"storage"`
+
+	// 代码生成配置
+	Gen struct {
+		// 作者
+		Author string `yaml:"author"`
+		// 默认模块名
+		ModuleName string `yaml:"moduleName"`
+		// 是否自动去除表前缀
+		AutoRemovePre bool `yaml:"autoRemovePre"`
+		// 表前缀，多个用逗号分隔
+		TablePrefix string `yaml:"tablePrefix"`
+	} `yaml:"gen"`
+}
+
+// TokenExpire 令牌有效期，单位分钟
+type TokenExpire struct {
+	// 访问令牌有效期
+	AccessExpireIn int `yaml:"accessExpireIn"`
+	// 刷新令牌有效期，同时为登录会话的最长有效期
+	RefreshExpireIn int `yaml:"refreshExpireIn"`
 }
 
-var conf *Setting
+var (
+	conf        atomic.Pointer[Setting]
+	listenersMu sync.Mutex
+	listeners   []func(oldSetting, newSetting *Setting)
+)
 
-func init() {
-	file, err := os.ReadFile("application.yml")
-	if err != nil {
-		panic(err)
+// SetSetting 设置系统配置并通知订阅者，可在测试中注入自定义配置
+func SetSetting(setting *Setting) {
+	oldSetting := conf.Swap(setting)
+	if oldSetting == nil {
+		return
 	}
-
-	conf = &Setting{}
-	err = yaml.Unmarshal(file, conf)
-	if err != nil {
-		panic(err)
+	listenersMu.Lock()
+	fns := append([]func(oldSetting, newSetting *Setting){}, listeners...)
+	listenersMu.Unlock()
+	for _, fn := range fns {
+		fn(oldSetting, setting)
 	}
 }
 
+// GetSetting 获取当前系统配置
+//
+// 配置热更新时会整体替换，需要读取最新配置的地方应每次调用获取，不要长期持有返回值
+//
+// 未加载配置文件时返回零值，避免引用配置的包在测试中导入时出现空指针
 func GetSetting() *Setting {
-	return conf
+	if setting := conf.Load(); setting != nil {
+		return setting
+	}
+	return &Setting{}
+}
+
+// OnChange 订阅配置变更，配置热更新后以新旧配置调用
+func OnChange(fn func(oldSetting, newSetting *Setting)) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, fn)
 }
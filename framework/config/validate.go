@@ -0,0 +1,181 @@
+package config
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// ValidationError 配置校验错误，包含全部不合法的配置项
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return "配置校验失败：\n  " + strings.Join(e.Errors, "\n  ")
+}
+
+// Validate 校验配置，一次性返回所有缺失或不合法的配置项
+func (s *Setting) Validate() error {
+	var errs []string
+	required := func(key, value string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, key+" 不能为空")
+		}
+	}
+	port := func(key string, value int) {
+		if value <= 0 || value > 65535 {
+			errs = append(errs, key+" 必须在1-65535之间")
+		}
+	}
+	oneOf := func(key, value string, options ...string) {
+		for _, option := range options {
+			if value == option {
+				return
+			}
+		}
+		errs = append(errs, key+" 可选值："+strings.Join(options, "、"))
+	}
+	nonNegative := func(key string, value int) {
+		if value < 0 {
+			errs = append(errs, key+" 不能小于0")
+		}
+	}
+
+	required("system.name", s.System.Name)
+	port("server.port", s.Server.Port)
+	oneOf("server.mode", s.Server.Mode, "debug", "test", "release")
+	nonNegative("server.readTimeout", s.Server.ReadTimeout)
+	nonNegative("server.writeTimeout", s.Server.WriteTimeout)
+	nonNegative("server.idleTimeout", s.Server.IdleTimeout)
+	nonNegative("server.shutdownTimeout", s.Server.ShutdownTimeout)
+	nonNegative("server.hookTimeout", s.Server.HookTimeout)
+	for _, proxy := range s.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				errs = append(errs, "server.trustedProxies 格式错误："+proxy)
+			}
+		}
+	}
+
+	if s.Log.Level != "" {
+		oneOf("log.level", s.Log.Level, "debug", "info", "warn", "error")
+	}
+	if s.Log.Format != "" {
+		oneOf("log.format", s.Log.Format, "text", "json")
+	}
+	if s.Log.Output != "" {
+		oneOf("log.output", s.Log.Output, "console", "file", "both")
+	}
+	if s.Log.Output == "file" || s.Log.Output == "both" {
+		required("log.file.path", s.Log.File.Path)
+	}
+	nonNegative("log.slowThreshold", s.Log.SlowThreshold)
+	nonNegative("log.file.maxSize", s.Log.File.MaxSize)
+	nonNegative("log.file.maxBackups", s.Log.File.MaxBackups)
+	nonNegative("log.file.maxAge", s.Log.File.MaxAge)
+
+	if s.Trace.Enabled {
+		oneOf("trace.exporter", s.Trace.Exporter, "otlp", "stdout")
+		if s.Trace.Exporter == "otlp" {
+			required("trace.endpoint", s.Trace.Endpoint)
+		}
+		if s.Trace.SampleRate < 0 || s.Trace.SampleRate > 1 {
+			errs = append(errs, "trace.sampleRate 必须在0-1之间")
+		}
+	}
+
+	if s.Metrics.Enabled {
+		if !strings.HasPrefix(s.Metrics.Path, "/") {
+			errs = append(errs, "metrics.path 必须以/开头")
+		}
+		for _, allowIp := range s.Metrics.AllowIps {
+			if net.ParseIP(allowIp) == nil {
+				if _, _, err := net.ParseCIDR(allowIp); err != nil {
+					errs = append(errs, "metrics.allowIps 格式错误："+allowIp)
+				}
+			}
+		}
+	}
+
+	nonNegative("health.timeout", s.Health.Timeout)
+	nonNegative("health.shutdownDelay", s.Health.ShutdownDelay)
+	nonNegative("logQueue.size", s.LogQueue.Size)
+	nonNegative("logQueue.batchSize", s.LogQueue.BatchSize)
+	nonNegative("logQueue.flushInterval", s.LogQueue.FlushInterval)
+	nonNegative("logQueue.workers", s.LogQueue.Workers)
+	nonNegative("logQueue.enqueueTimeout", s.LogQueue.EnqueueTimeout)
+
+	for _, pattern := range s.OperLog.MaskPatterns {
+		if _, err := regexp.Compile(pattern.Regex); err != nil {
+			errs = append(errs, "operLog.maskPatterns 正则格式错误："+pattern.Regex)
+		}
+		nonNegative("operLog.maskPatterns.keepPrefix", pattern.KeepPrefix)
+		nonNegative("operLog.maskPatterns.keepSuffix", pattern.KeepSuffix)
+	}
+	nonNegative("operLog.maxLength", s.OperLog.MaxLength)
+
+	for _, provider := range s.Ip.Providers {
+		oneOf("ip.providers", provider, "ip2region", "mmdb", "http")
+		switch provider {
+		case "ip2region":
+			required("ip.ip2regionPath", s.Ip.Ip2regionPath)
+		case "mmdb":
+			required("ip.mmdbPath", s.Ip.MmdbPath)
+		}
+	}
+	nonNegative("ip.cacheSize", s.Ip.CacheSize)
+
+	required("db.host", s.DB.Host)
+	port("db.port", s.DB.Port)
+	required("db.database", s.DB.Database)
+	required("db.username", s.DB.Username)
+	required("db.charset", s.DB.Charset)
+	nonNegative("db.maxIdleConn", s.DB.MaxIdleConn)
+	nonNegative("db.maxOpenConn", s.DB.MaxOpenConn)
+
+	required("cache.host", s.Cache.Host)
+	port("cache.port", s.Cache.Port)
+	nonNegative("cache.database", s.Cache.Database)
+
+	required("auth.token.header", s.Auth.Token.Header)
+	required("auth.token.secret", s.Auth.Token.Secret)
+	if s.Auth.Token.ExpireIn <= 0 {
+		errs = append(errs, "auth.token.expireIn 必须大于0")
+	}
+	if s.Auth.Token.Mode != "" {
+		oneOf("auth.token.mode", s.Auth.Token.Mode, "sliding", "refresh")
+	}
+	nonNegative("auth.token.clients.web.accessExpireIn", s.Auth.Token.Clients.Web.AccessExpireIn)
+	nonNegative("auth.token.clients.web.refreshExpireIn", s.Auth.Token.Clients.Web.RefreshExpireIn)
+	nonNegative("auth.token.clients.mobile.accessExpireIn", s.Auth.Token.Clients.Mobile.AccessExpireIn)
+	nonNegative("auth.token.clients.mobile.refreshExpireIn", s.Auth.Token.Clients.Mobile.RefreshExpireIn)
+	if s.Auth.Session.Policy != "" {
+		oneOf("auth.session.policy", s.Auth.Session.Policy, "multiple", "limit", "single")
+	}
+	if s.Auth.Session.Policy == "limit" && s.Auth.Session.MaxCount <= 0 {
+		errs = append(errs, "auth.session.maxCount 必须大于0")
+	}
+	nonNegative("auth.password.maxRetryCount", s.Auth.Password.MaxRetryCount)
+	nonNegative("auth.password.lockTime", s.Auth.Password.LockTime)
+	nonNegative("auth.throttle.window", s.Auth.Throttle.Window)
+	nonNegative("auth.throttle.ipMaxRetry", s.Auth.Throttle.IpMaxRetry)
+	nonNegative("auth.throttle.userMaxRetry", s.Auth.Throttle.UserMaxRetry)
+	nonNegative("auth.throttle.maxLockTime", s.Auth.Throttle.MaxLockTime)
+	nonNegative("auth.throttle.captchaAfter", s.Auth.Throttle.CaptchaAfter)
+
+	if s.Storage.Driver != "" {
+		oneOf("storage.driver", s.Storage.Driver, "local", "oss")
+	}
+	if s.Storage.Driver == "oss" {
+		required("storage.oss.endpoint", s.Storage.Oss.Endpoint)
+		required("storage.oss.accessKey", s.Storage.Oss.AccessKey)
+		required("storage.oss.secretKey", s.Storage.Oss.SecretKey)
+		required("storage.oss.bucket", s.Storage.Oss.Bucket)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
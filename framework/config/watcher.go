@@ -0,0 +1,78 @@
+package config
+
+import (
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// 文件变更后等待的时间，合并编辑器保存时产生的多次事件
+const reloadDelay = 500 * time.Millisecond
+
+// Watch 监听基础配置及环境配置文件，文件变更后重新加载配置
+//
+// 监听文件所在目录而不是文件本身，兼容编辑器及配置中心以替换文件的方式更新配置
+//
+// 返回的函数用于停止监听
+func Watch(path string, profile string) (func() error, error) {
+	if profile == "" {
+		profile = GetSetting().Profile
+	}
+	files := map[string]bool{}
+	for _, file := range []string{path, ProfilePath(path, profile)} {
+		if file == path || profile != "" {
+			absPath, err := filepath.Abs(file)
+			if err != nil {
+				return nil, err
+			}
+			files[absPath] = true
+		}
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	dirs := map[string]bool{}
+	for file := range files {
+		dirs[filepath.Dir(file)] = true
+	}
+	for dir := range dirs {
+		if err = watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, err
+		}
+	}
+
+	go func() {
+		var timer *time.Timer
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !files[filepath.Clean(event.Name)] || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(reloadDelay, func() {
+					if _, err := Reload(path, profile); err != nil {
+						slog.Error("reload config", "module", "config", "error", err)
+						return
+					}
+					slog.Info("config reloaded", "module", "config")
+				})
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("watch config", "module", "config", "error", err)
+			}
+		}
+	}()
+	return watcher.Close, nil
+}
@@ -10,20 +10,34 @@ import (
 
 var cache *redis.Client
 
-func ConnectToRedis() {
-	conf := config.GetSetting()
-	cache = redis.NewClient(&redis.Options{
+// NewRedis 根据配置创建redis连接
+func NewRedis(conf *config.Setting) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
 		Addr:     conf.Cache.Host + ":" + strconv.Itoa(conf.Cache.Port),
 		Password: conf.Cache.Password,
 		DB:       conf.Cache.Database,
 	})
 
-	_, err := cache.Ping(context.Background()).Result()
-	if err != nil {
-		panic(err)
+	if _, err := client.Ping(context.Background()).Result(); err != nil {
+		_ = client.Close()
+		return nil, err
 	}
+	return client, nil
+}
+
+// SetCache 设置全局redis连接，可在测试中注入
+func SetCache(client *redis.Client) {
+	cache = client
 }
 
 func GetCache() *redis.Client {
 	return cache
 }
+
+// CloseRedis 关闭redis连接
+func CloseRedis() error {
+	if cache == nil {
+		return nil
+	}
+	return cache.Close()
+}
@@ -1,49 +1,38 @@
 package connector
 
 import (
-	"fmt"
-	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/framework/logger"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	GREEN   = "\033[97;42m"
-	WHITE   = "\033[90;47m"
-	YELLOW  = "\033[90;43m"
-	RED     = "\033[97;41m"
-	BLUE    = "\033[97;44m"
-	MAGENTA = "\033[97;45m"
-	CYAN    = "\033[97;46m"
-	RESET   = "\033[0m"
-)
-
+// InitializeLogger 记录请求日志，5xx记录为error，4xx记录为warn
 func InitializeLogger(server *gin.Engine) {
-	gin.ForceConsoleColor()
-	server.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		var statusColor, methodColor, resetColor string
-		if param.IsOutputColor() {
-			statusColor = param.StatusCodeColor()
-			methodColor = param.MethodColor()
-			resetColor = param.ResetColor()
+	server.Use(func(ctx *gin.Context) {
+		start := time.Now()
+		path := ctx.Request.URL.Path
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		} else if status >= 400 {
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("ip", ctx.ClientIP()),
 		}
-		if param.Latency > time.Minute {
-			param.Latency = param.Latency.Truncate(time.Second)
+		if errs := ctx.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			attrs = append(attrs, slog.String("error", errs))
 		}
-		return fmt.Sprintf("[GIN-%s] %v |%s %3d %s| %13v | %12s |%s %-7s %s %#v\n%s",
-			gin.Mode(),
-			param.TimeStamp.Format(datetime.DATETIME_FORMAT0),
-			statusColor,
-			param.StatusCode,
-			resetColor,
-			param.Latency,
-			param.ClientIP,
-			methodColor,
-			param.Method,
-			resetColor,
-			param.Path,
-			param.ErrorMessage,
-		)
-	}))
+		// 使用请求的context，附带中间件写入的请求id、用户id等字段
+		logger.Module("http").LogAttrs(ctx.Request.Context(), level, "request", attrs...)
+	})
 }
@@ -1,52 +1,71 @@
 package connector
 
 import (
+	"context"
 	"github.com/hugo8680/goat/framework/config"
-	"log"
+	"github.com/hugo8680/goat/framework/logger"
 	"strconv"
 	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
 
 var db *gorm.DB
 
-func ConnectToMySQL() {
-	var err error
-	conf := config.GetSetting()
+// NewMySQL 根据配置创建数据库连接
+func NewMySQL(conf *config.Setting) (*gorm.DB, error) {
 	dsn := conf.DB.Username + ":" + conf.DB.Password + "@tcp(" + conf.DB.Host + ":" + strconv.Itoa(conf.DB.Port) + ")/" + conf.DB.Database + "?charset=" + conf.DB.Charset + "&parseTime=True&loc=Local"
-	db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+	conn, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
 		SkipDefaultTransaction: true, // 跳过默认事务
 		NamingStrategy: schema.NamingStrategy{
 			SingularTable: true,
 		},
-		Logger: logger.New(log.Default(), logger.Config{
-			LogLevel:                  logger.Error, // 打印错误日志
-			IgnoreRecordNotFoundError: true,
-		}),
+		Logger: logger.NewGormLogger(time.Duration(conf.Log.SlowThreshold) * time.Millisecond), // SQL日志写入全局日志
 	})
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	sqlDB, err := db.DB()
+	sqlDB, err := conn.DB()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	sqlDB.SetMaxOpenConns(conf.DB.MaxOpenConn)
 	sqlDB.SetMaxIdleConns(conf.DB.MaxIdleConn)
 	sqlDB.SetConnMaxLifetime(time.Minute * 30)
 
-	err = sqlDB.Ping()
-	if err != nil {
-		panic(err)
+	if err = sqlDB.Ping(); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
 	}
+	return conn, nil
+}
+
+// SetDB 设置全局数据库连接，可在测试中注入
+func SetDB(conn *gorm.DB) {
+	db = conn
 }
 
 func GetDB() *gorm.DB {
 	return db
 }
+
+// DB 获取使用指定context的数据库连接，传入请求的context时查询会加入请求链路
+func DB(ctx context.Context) *gorm.DB {
+	return db.WithContext(ctx)
+}
+
+// CloseMySQL 关闭数据库连接池
+func CloseMySQL() error {
+	if db == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// Check 就绪检查，返回错误表示未就绪
+type Check func(ctx context.Context) error
+
+// Result 检查结果，错误信息可能包含内部地址，仅用于记录日志，不返回给调用方
+type Result struct {
+	Status  string `json:"status"`
+	Error   string `json:"-"`
+	Latency string `json:"latency"`
+}
+
+// Report 就绪检查报告
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+var (
+	mu           sync.RWMutex
+	checks       = map[string]Check{}
+	shuttingDown atomic.Bool
+)
+
+// Register 注册就绪检查，同名检查会被覆盖
+func Register(name string, check Check) {
+	mu.Lock()
+	defer mu.Unlock()
+	checks[name] = check
+}
+
+// SetShuttingDown 标记服务正在关闭，之后的就绪检查均返回未就绪
+func SetShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// Ready 并发执行所有就绪检查，每项检查的超时时间为timeout
+func Ready(ctx context.Context, timeout time.Duration) Report {
+	mu.RLock()
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fns := make([]Check, 0, len(names))
+	for _, name := range names {
+		fns = append(fns, checks[name])
+	}
+	mu.RUnlock()
+
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]Result, len(names)+1),
+	}
+	if shuttingDown.Load() {
+		report.Status = StatusDown
+		report.Checks["shutdown"] = Result{Status: StatusDown, Error: "服务正在关闭"}
+	}
+
+	results := make([]Result, len(fns))
+	var wg sync.WaitGroup
+	for i, fn := range fns {
+		wg.Add(1)
+		go func(i int, fn Check) {
+			defer wg.Done()
+			results[i] = run(ctx, fn, timeout)
+		}(i, fn)
+	}
+	wg.Wait()
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// run 执行单项检查，超时后不再等待检查返回
+func run(ctx context.Context, fn Check, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := Result{
+		Status:  StatusUp,
+		Latency: time.Since(start).Truncate(time.Microsecond).String(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	hookMu        sync.Mutex
+	startHooks    []func() error
+	shutdownHooks []func(ctx context.Context) error
+)
+
+// OnStart 注册启动钩子，在路由注册完成后、开始监听端口前按注册顺序执行
+//
+// 任意钩子返回错误时服务不会启动
+func OnStart(hook func() error) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	startHooks = append(startHooks, hook)
+}
+
+// OnShutdown 注册关闭钩子，在停止接收请求并处理完进行中的请求后按注册的逆序执行
+//
+// ctx 在关闭超时后取消，钩子应在ctx取消前完成资源的释放
+func OnShutdown(hook func(ctx context.Context) error) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	shutdownHooks = append(shutdownHooks, hook)
+}
+
+func runStartHooks() error {
+	hookMu.Lock()
+	hooks := append([]func() error{}, startHooks...)
+	hookMu.Unlock()
+	for _, hook := range hooks {
+		if err := hook(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shutdownHookCount 已注册的关闭钩子数量
+func shutdownHookCount() int {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	return len(shutdownHooks)
+}
+
+// discardShutdownHooks 按注册的逆序执行并移除第from个之后注册的关闭钩子，用于初始化失败时释放已创建的资源
+func discardShutdownHooks(ctx context.Context, from int) []error {
+	hookMu.Lock()
+	if from > len(shutdownHooks) {
+		from = len(shutdownHooks)
+	}
+	hooks := append([]func(ctx context.Context) error{}, shutdownHooks[from:]...)
+	shutdownHooks = shutdownHooks[:from]
+	hookMu.Unlock()
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// runShutdownHooks 执行全部关闭钩子，单个钩子出错不影响后续钩子的执行
+func runShutdownHooks(ctx context.Context) []error {
+	hookMu.Lock()
+	hooks := append([]func(ctx context.Context) error{}, shutdownHooks...)
+	hookMu.Unlock()
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
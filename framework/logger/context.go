@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey struct{}
+
+// WithAttrs 在context中附加日志字段，使用该context记录的日志会自动带上这些字段
+//
+// 如请求id、用户id
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if existing, ok := ctx.Value(contextKey{}).([]slog.Attr); ok {
+		attrs = append(append(make([]slog.Attr, 0, len(existing)+len(attrs)), existing...), attrs...)
+	}
+	return context.WithValue(ctx, contextKey{}, attrs)
+}
+
+// Attrs 获取context中附加的日志字段
+func Attrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextHandler 将context中附加的字段写入日志
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		record.AddAttrs(Attrs(ctx)...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
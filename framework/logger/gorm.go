@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// GormLogger 将Gorm的SQL日志写入全局日志
+//
+// debug级别记录全部SQL，超过慢查询阈值的SQL记录为warn，执行出错的SQL记录为error
+type GormLogger struct {
+	SlowThreshold time.Duration
+}
+
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{
+		SlowThreshold: slowThreshold,
+	}
+}
+
+// LogMode 日志级别由全局日志控制
+func (l *GormLogger) LogMode(gormLogger.LogLevel) gormLogger.Interface {
+	return l
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	Module("gorm").InfoContext(ctx, msg, "data", data)
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	Module("gorm").WarnContext(ctx, msg, "data", data)
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	Module("gorm").ErrorContext(ctx, msg, "data", data)
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		Module("gorm").ErrorContext(ctx, "sql error", "error", err, "sql", sql, "rows", rows, "elapsed", elapsed)
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		sql, rows := fc()
+		Module("gorm").WarnContext(ctx, "slow sql", "sql", sql, "rows", rows, "elapsed", elapsed, "threshold", l.SlowThreshold)
+	case Enabled(slog.LevelDebug):
+		sql, rows := fc()
+		Module("gorm").DebugContext(ctx, "sql", "sql", sql, "rows", rows, "elapsed", elapsed)
+	}
+}
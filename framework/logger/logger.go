@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+	"github.com/hugo8680/goat/framework/config"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	OutputConsole = "console" // 输出到控制台
+	OutputFile    = "file"    // 输出到文件
+	OutputBoth    = "both"    // 同时输出到控制台和文件
+
+	FormatText = "text"
+	FormatJson = "json"
+)
+
+// 日志级别，配置热更新时动态调整
+var level = new(slog.LevelVar)
+
+// Init 根据配置初始化全局日志，标准库log的输出同样会写入该日志
+//
+// 返回的函数用于停止按天切割并关闭日志文件
+func Init(setting *config.Setting) (func() error, error) {
+	conf := setting.Log
+	level.Set(ParseLevel(conf.Level))
+
+	var writers []io.Writer
+	var file *lumberjack.Logger
+	if conf.Output == "" || conf.Output == OutputConsole || conf.Output == OutputBoth {
+		writers = append(writers, os.Stdout)
+	}
+	if conf.Output == OutputFile || conf.Output == OutputBoth {
+		file = &lumberjack.Logger{
+			Filename:   conf.File.Path,
+			MaxSize:    conf.File.MaxSize,
+			MaxBackups: conf.File.MaxBackups,
+			MaxAge:     conf.File.MaxAge,
+			LocalTime:  true,
+			Compress:   conf.File.Compress,
+		}
+		writers = append(writers, file)
+	}
+
+	options := &slog.HandlerOptions{
+		Level: level,
+	}
+	var handler slog.Handler
+	if conf.Format == FormatJson {
+		handler = slog.NewJSONHandler(io.MultiWriter(writers...), options)
+	} else {
+		handler = slog.NewTextHandler(io.MultiWriter(writers...), options)
+	}
+	slog.SetDefault(slog.New(&contextHandler{Handler: handler}))
+
+	config.OnChange(func(oldSetting, newSetting *config.Setting) {
+		level.Set(ParseLevel(newSetting.Log.Level))
+	})
+
+	stop := make(chan struct{})
+	if file != nil && conf.File.Daily {
+		go rotateDaily(file, stop)
+	}
+	return func() error {
+		close(stop)
+		if file != nil {
+			return file.Close()
+		}
+		return nil
+	}, nil
+}
+
+// Module 获取模块日志，日志中附带module字段
+func Module(name string) *slog.Logger {
+	return slog.Default().With("module", name)
+}
+
+// ParseLevel 解析日志级别，无法识别时使用info
+func ParseLevel(value string) slog.Level {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// Enabled 是否输出指定级别的日志
+func Enabled(l slog.Level) bool {
+	return slog.Default().Enabled(context.Background(), l)
+}
+
+// rotateDaily 每天零点切割日志文件
+func rotateDaily(file *lumberjack.Logger, stop chan struct{}) {
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-timer.C:
+			if err := file.Rotate(); err != nil {
+				slog.Error("rotate log file", "error", err)
+			}
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
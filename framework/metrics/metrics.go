@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "goat"
+
+// 独立的注册表，避免第三方库注册到默认注册表的指标混入
+var registry = newRegistry()
+
+var factory = promauto.With(registry)
+
+var (
+	httpRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP请求总数",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP请求耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	loginTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_login_total",
+		Help:      "登录次数，result为success或failure",
+	}, []string{"result"})
+
+	tokenRefreshTotal = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_token_refresh_total",
+		Help:      "token刷新次数",
+	})
+
+	uploadBytesTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_bytes_total",
+		Help:      "上传文件字节数",
+	}, []string{"driver"})
+
+	uploadFilesTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_files_total",
+		Help:      "上传文件数，result为success或failure",
+	}, []string{"driver", "result"})
+
+	queueOverflowTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "batch_queue_overflow_total",
+		Help:      "写入时队列已满的次数",
+	}, []string{"queue"})
+
+	queueDroppedTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "batch_queue_dropped_total",
+		Help:      "队列已满或已关闭时丢弃的条数",
+	}, []string{"queue"})
+
+	queueWrittenTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "batch_queue_written_total",
+		Help:      "批量写入条数，result为success或failure",
+	}, []string{"queue", "result"})
+)
+
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+// Handler 指标输出接口
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHttpRequest 记录HTTP请求，route为路由模板，避免路径参数导致标签过多
+func ObserveHttpRequest(method, route, status string, seconds float64) {
+	httpRequestsTotal.WithLabelValues(method, route, status).Inc()
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(seconds)
+}
+
+// ObserveLogin 记录登录结果
+func ObserveLogin(success bool) {
+	loginTotal.WithLabelValues(result(success)).Inc()
+}
+
+// ObserveTokenRefresh 记录token刷新
+func ObserveTokenRefresh() {
+	tokenRefreshTotal.Inc()
+}
+
+// ObserveUpload 记录文件上传
+func ObserveUpload(driver string, bytes int, success bool) {
+	uploadFilesTotal.WithLabelValues(driver, result(success)).Inc()
+	if success {
+		uploadBytesTotal.WithLabelValues(driver).Add(float64(bytes))
+	}
+}
+
+// ObserveQueueOverflow 记录队列已满
+func ObserveQueueOverflow(queue string) {
+	queueOverflowTotal.WithLabelValues(queue).Inc()
+}
+
+// ObserveQueueDropped 记录丢弃的条数
+func ObserveQueueDropped(queue string) {
+	queueDroppedTotal.WithLabelValues(queue).Inc()
+}
+
+// ObserveQueueWritten 记录批量写入的条数
+func ObserveQueueWritten(queue string, count int, success bool) {
+	queueWrittenTotal.WithLabelValues(queue, result(success)).Add(float64(count))
+}
+
+// RegisterQueue 注册队列长度指标，采集时调用length获取当前长度
+func RegisterQueue(queue string, length func() float64) error {
+	return registry.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "batch_queue_length",
+		Help:        "队列中等待写入的条数",
+		ConstLabels: prometheus.Labels{"queue": queue},
+	}, length))
+}
+
+// RegisterDB 注册数据库连接池指标
+func RegisterDB(db *sql.DB, dbName string) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// RegisterRedis 注册redis连接池指标
+func RegisterRedis(client *redis.Client) error {
+	return registry.Register(&redisCollector{client: client})
+}
+
+func result(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	redisHitsDesc     = prometheus.NewDesc(namespace+"_redis_pool_hits_total", "连接池命中次数", nil, nil)
+	redisMissesDesc   = prometheus.NewDesc(namespace+"_redis_pool_misses_total", "连接池未命中次数", nil, nil)
+	redisTimeoutsDesc = prometheus.NewDesc(namespace+"_redis_pool_timeouts_total", "获取连接超时次数", nil, nil)
+	redisTotalDesc    = prometheus.NewDesc(namespace+"_redis_pool_total_conns", "连接总数", nil, nil)
+	redisIdleDesc     = prometheus.NewDesc(namespace+"_redis_pool_idle_conns", "空闲连接数", nil, nil)
+	redisStaleDesc    = prometheus.NewDesc(namespace+"_redis_pool_stale_conns_total", "被移除的过期连接数", nil, nil)
+)
+
+// redisCollector 采集时读取redis连接池状态
+type redisCollector struct {
+	client *redis.Client
+}
+
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisHitsDesc
+	ch <- redisMissesDesc
+	ch <- redisTimeoutsDesc
+	ch <- redisTotalDesc
+	ch <- redisIdleDesc
+	ch <- redisStaleDesc
+}
+
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisTotalDesc, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisIdleDesc, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisStaleDesc, prometheus.CounterValue, float64(stats.StaleConns))
+}
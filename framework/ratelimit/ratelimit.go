@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"github.com/hugo8680/goat/common/uuid"
+	"github.com/hugo8680/goat/framework/connector"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Algorithm 限流算法
+type Algorithm int
+
+const (
+	// TokenBucket 令牌桶，桶容量为Limit，每个Window补满，允许一定的突发请求
+	TokenBucket Algorithm = iota
+	// SlidingWindow 滑动窗口，任意Window时长内最多Limit次请求
+	SlidingWindow
+)
+
+// Result 限流结果
+//
+// Allowed 是否放行
+// Limit 限流上限
+// Remaining 剩余可用次数
+// Reset 恢复到上限所需时间
+// RetryAfter 被限流时距下次可请求的时间
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// 脚本使用redis服务器时间，多实例时间不一致时计数仍然准确
+//
+// KEYS[1]限流键名，ARGV[1]上限，ARGV[2]窗口毫秒，ARGV[3]请求id，返回{是否放行，剩余次数，恢复毫秒，重试毫秒}
+var tokenBucketScript = redis.NewScript(`
+redis.replicate_commands()
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local rate = limit / window
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = limit
+	ts = now
+end
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return {allowed, math.floor(tokens), math.ceil((limit - tokens) / rate), retry}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+redis.replicate_commands()
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = 0
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+local retry = 0
+if allowed == 0 then
+	retry = reset
+end
+return {allowed, limit - count, reset, retry}
+`)
+
+// Allow 按算法消耗一次请求额度，limit为上限，window为窗口或令牌桶补满的时长
+func Allow(ctx context.Context, algorithm Algorithm, key string, limit int, window time.Duration) (Result, error) {
+	if limit <= 0 || window < time.Millisecond {
+		return Result{}, errors.New("限流上限及窗口必须大于0")
+	}
+	var script *redis.Script
+	switch algorithm {
+	case TokenBucket:
+		script = tokenBucketScript
+	case SlidingWindow:
+		script = slidingWindowScript
+	default:
+		return Result{}, errors.New("不支持的限流算法")
+	}
+	member, err := uuid.CreateId()
+	if err != nil {
+		return Result{}, err
+	}
+	values, err := script.Run(ctx, connector.GetCache(), []string{key}, limit, window.Milliseconds(), member).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 4 {
+		return Result{}, errors.New("限流脚本返回值错误")
+	}
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		Reset:      time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
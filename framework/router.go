@@ -1,59 +1,101 @@
 package framework
 
 import (
+	"fmt"
 	"github.com/hugo8680/goat/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Access 访问方式
+type Access int
+
+const (
+	// AccessDefault 路由沿用路由组的访问方式，路由组未设置时需要登录
+	AccessDefault Access = iota
+	// AccessAnonymous 无需登录
+	AccessAnonymous
+	// AccessLogin 登录即可访问
+	AccessLogin
+)
+
 // Route 路由配置
 //
 // Method Http方法 GET POST PUT DELETE OPTIONS
 // RelativePath 路由前缀
+// Access 访问方式
+// Permission 权限表达式，如 system:user:list、system:user:add && system:user:edit、role(admin) || system:user:*，为空时不校验权限
+// PwdChangeExempt 须修改密码的会话仍可访问，用于获取用户信息、修改密码等
 // Middlewares 中间件组
 // Function 控制器方法
 type Route struct {
-	Method       string
-	RelativePath string
-	Middlewares  gin.HandlersChain
-	Function     gin.HandlerFunc
+	Method          string
+	RelativePath    string
+	Access          Access
+	Permission      string
+	PwdChangeExempt bool
+	Middlewares     gin.HandlersChain
+	Function        gin.HandlerFunc
 }
 
 // RouteGroup 路由组
 //
 // Name 名称
 // RelativePath 路由前缀
+// Access 组内路由默认的访问方式
 // Middlewares 中间件组
 // Routes 路由组子项
 type RouteGroup struct {
 	Name         string
 	RelativePath string
+	Access       Access
 	Middlewares  gin.HandlersChain
 	Routes       []Route
 }
 
 func registerCommonMiddlewares(server *gin.Engine) {
 	server.Use(middleware.RecoveryMiddleware())
+	server.Use(middleware.RequestIdMiddleware())
+	server.Use(middleware.TraceMiddleware())
+	server.Use(middleware.MetricsMiddleware())
 	server.Use(middleware.CorsMiddleware())
 }
 
 // registerRouteGroups 注册分组路由
-func registerRouteGroups(groups []RouteGroup) {
-	server := getServer()
-	rootRoute := server.Group("/api")
+func registerRouteGroups(engine *gin.Engine, groups []RouteGroup) {
+	rootRoute := engine.Group("/api")
 	for _, group := range groups {
 		g := rootRoute.Group(group.RelativePath)
 		if group.Middlewares != nil {
 			g.Use(group.Middlewares...)
 		}
 		for _, route := range group.Routes {
-			if route.Middlewares != nil {
-				handlers := route.Middlewares
-				handlers = append(handlers, route.Function)
-				g.Handle(route.Method, route.RelativePath, handlers...)
-			} else {
-				g.Handle(route.Method, route.RelativePath, route.Function)
-			}
+			handlers := routeAccessHandlers(group, route)
+			handlers = append(handlers, route.Middlewares...)
+			handlers = append(handlers, route.Function)
+			g.Handle(route.Method, route.RelativePath, handlers...)
+		}
+	}
+}
+
+// routeAccessHandlers 根据访问方式及权限表达式生成认证、修改密码检查及鉴权中间件，在路由组中间件之后、路由中间件之前执行
+func routeAccessHandlers(group RouteGroup, route Route) gin.HandlersChain {
+	access := route.Access
+	if access == AccessDefault {
+		access = group.Access
+	}
+	if access == AccessAnonymous {
+		if route.Permission != "" {
+			panic(fmt.Sprintf("路由%s %s无需登录，不能设置权限表达式", route.Method, route.RelativePath))
 		}
+		return gin.HandlersChain{}
+	}
+	handlers := gin.HandlersChain{middleware.AdminAuthMiddleware()}
+	if !route.PwdChangeExempt {
+		handlers = append(handlers, middleware.PwdChangeCheckMiddleware())
+	}
+	if route.Permission != "" {
+		handlers = append(handlers, middleware.PermissionCheckMiddleware(route.Permission))
 	}
+	return handlers
 }
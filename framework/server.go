@@ -1,54 +1,270 @@
 package framework
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/common/ip"
+	"github.com/hugo8680/goat/common/masker"
+	"github.com/hugo8680/goat/common/uploader"
 	"github.com/hugo8680/goat/framework/config"
 	"github.com/hugo8680/goat/framework/connector"
-	"strconv"
-	"sync"
+	"github.com/hugo8680/goat/framework/health"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/framework/metrics"
+	"github.com/hugo8680/goat/framework/tracing"
+	"github.com/hugo8680/goat/middleware"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
 )
 
-type Server struct {
-	*gin.Engine
-	Port string
+// App 应用程序，负责加载配置、创建数据源连接并运行服务
+//
+// DB及Cache与connector中注入的连接为同一实例，service通过connector获取，自定义的启动及关闭钩子可直接使用
+type App struct {
+	Setting *config.Setting
+	Engine  *gin.Engine
+	DB      *gorm.DB
+	Cache   *redis.Client
 }
 
-var server *Server
-var once sync.Once
+// NewApp 初始化应用程序
+//
+// configPath 配置文件路径
+//
+// profile 环境，为空时读取GOAT_PROFILE环境变量
+//
+// 创建的数据源连接会注入到connector中供service使用，并在服务关闭时释放，初始化失败时立即释放
+func NewApp(configPath string, profile string) (app *App, err error) {
+	setting, err := config.Load(configPath, profile)
+	if err != nil {
+		return nil, err
+	}
+	// 初始化失败时执行本次注册的关闭钩子，释放日志、链路追踪及数据源连接
+	hookCount := shutdownHookCount()
+	defer func() {
+		if err == nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), secondsOrDefault(setting.Server.HookTimeout, 10))
+		defer cancel()
+		for _, hookErr := range discardShutdownHooks(ctx, hookCount) {
+			slog.Error("shutdown hook", "error", hookErr)
+		}
+	}()
+	redis_key.Init(setting.System.Name)
+	closeLogger, err := logger.Init(setting)
+	if err != nil {
+		return nil, err
+	}
+	// 日志最先注册，最后关闭
+	OnShutdown(func(ctx context.Context) error {
+		return closeLogger()
+	})
+	shutdownTracing, err := tracing.Init(setting)
+	if err != nil {
+		return nil, err
+	}
+	OnShutdown(shutdownTracing)
+	if err = masker.Init(setting); err != nil {
+		return nil, err
+	}
+	closeIp, err := ip.Init(setting)
+	if err != nil {
+		return nil, err
+	}
+	OnShutdown(func(ctx context.Context) error {
+		return closeIp()
+	})
+
+	gin.SetMode(setting.Server.Mode)
+	engine := gin.New()
+	// 默认信任所有代理，客户端可伪造X-Forwarded-For绕过按ip的登录及接口限流
+	if err = engine.SetTrustedProxies(setting.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
+	registerCommonMiddlewares(engine)
 
-func init() {
-	conf := config.GetSetting()
-	gin.SetMode(conf.Server.Mode)
-	server = &Server{
-		Engine: gin.New(),
-		Port:   strconv.Itoa(conf.Server.Port),
+	db, err := connector.NewMySQL(setting)
+	if err != nil {
+		return nil, err
 	}
-	once.Do(func() {
-		registerCommonMiddlewares(server.Engine)
-		connector.ConnectToMySQL()
-		connector.ConnectToRedis()
-		connector.InitializeLogger(server.Engine)
+	// 连接先于服务的关闭钩子注册，在其之后关闭，保证其他关闭钩子执行时连接仍可用；直接关闭创建的实例，注入connector前出错同样可以释放
+	OnShutdown(func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
 	})
-	server.Static(conf.System.UploadPath, conf.System.UploadPath)
-}
+	cache, err := connector.NewRedis(setting)
+	if err != nil {
+		return nil, err
+	}
+	OnShutdown(func(ctx context.Context) error {
+		return cache.Close()
+	})
+	// Gorm查询及redis命令加入请求链路
+	if err = db.Use(tracing.NewGormPlugin()); err != nil {
+		return nil, err
+	}
+	cache.AddHook(tracing.NewRedisHook())
+	if setting.Metrics.Enabled {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		if err = metrics.RegisterDB(sqlDB, setting.DB.Database); err != nil {
+			return nil, err
+		}
+		if err = metrics.RegisterRedis(cache); err != nil {
+			return nil, err
+		}
+		engine.GET(setting.Metrics.Path, middleware.MetricsAuthMiddleware(), gin.WrapH(metrics.Handler()))
+	}
+	connector.SetDB(db)
+	connector.SetCache(cache)
+	registerHealthChecks(engine)
+
+	// 监听配置文件，变更后热更新
+	if stopWatch, err := config.Watch(configPath, setting.Profile); err != nil {
+		logger.Module("config").Warn("watch config", "error", err)
+	} else {
+		OnShutdown(func(ctx context.Context) error {
+			return stopWatch()
+		})
+	}
 
-func getServer() *Server {
-	return server
+	connector.InitializeLogger(engine)
+	engine.Static(setting.System.UploadPath, setting.System.UploadPath)
+	return &App{
+		Setting: setting,
+		Engine:  engine,
+		DB:      db,
+		Cache:   cache,
+	}, nil
 }
 
-// RunServer 运行程序
+// Run 运行程序
 //
 // routeGroups 路由组，可传多个
-func RunServer(routeGroups ...[]RouteGroup) {
+//
+// 收到SIGINT或SIGTERM信号后停止接收新请求，等待进行中的请求处理完成后执行关闭钩子
+func (a *App) Run(routeGroups ...[]RouteGroup) {
 	if routeGroups != nil && len(routeGroups) != 0 {
 		for _, group := range routeGroups {
-			registerRouteGroups(group)
+			registerRouteGroups(a.Engine, group)
 		}
 	}
-	err := server.Run(fmt.Sprintf(":%s", server.Port))
-	if err != nil {
+	// 启动钩子出错时之前的钩子可能已启动定时任务及日志写入协程，执行关闭钩子写入剩余日志并释放连接
+	if err := runStartHooks(); err != nil {
+		a.runShutdownHooks()
 		panic(err)
 	}
+
+	httpServer := &http.Server{
+		Addr:         fmt.Sprintf(":%d", a.Setting.Server.Port),
+		Handler:      a.Engine,
+		ReadTimeout:  secondsOrDefault(a.Setting.Server.ReadTimeout, 30),
+		WriteTimeout: secondsOrDefault(a.Setting.Server.WriteTimeout, 60),
+		IdleTimeout:  secondsOrDefault(a.Setting.Server.IdleTimeout, 120),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			a.shutdown(httpServer)
+			panic(err)
+		}
+	case <-ctx.Done():
+		slog.Info("shutting down server")
+		a.shutdown(httpServer)
+		slog.Info("server exited")
+	}
+}
+
+// shutdown 关闭服务并执行关闭钩子
+//
+// 关闭前先将就绪检查标记为未就绪，等待shutdownDelay后再停止接收新请求，
+// 关闭钩子使用单独的超时时间，避免等待请求结束耗尽时间后日志等数据无法写入
+func (a *App) shutdown(httpServer *http.Server) {
+	health.SetShuttingDown()
+	if delay := a.Setting.Health.ShutdownDelay; delay > 0 {
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secondsOrDefault(a.Setting.Server.ShutdownTimeout, 10))
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		slog.Error("server shutdown", "error", err)
+	}
+	a.runShutdownHooks()
+}
+
+// runShutdownHooks 执行关闭钩子，使用单独的超时时间
+func (a *App) runShutdownHooks() {
+	ctx, cancel := context.WithTimeout(context.Background(), secondsOrDefault(a.Setting.Server.HookTimeout, 10))
+	defer cancel()
+	for _, err := range runShutdownHooks(ctx) {
+		slog.Error("shutdown hook", "error", err)
+	}
+}
+
+// registerHealthChecks 注册内置就绪检查及存活、就绪检查接口
+//
+// 检查接口不经过访问日志中间件，避免探针请求刷屏
+func registerHealthChecks(engine *gin.Engine) {
+	health.Register("mysql", func(ctx context.Context) error {
+		sqlDB, err := connector.GetDB().DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+	health.Register("redis", func(ctx context.Context) error {
+		return connector.GetCache().Ping(ctx).Err()
+	})
+	health.Register("upload", uploader.CheckWritable)
+
+	engine.GET("/healthz", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+	})
+	engine.GET("/readyz", func(ctx *gin.Context) {
+		timeout := secondsOrDefault(config.GetSetting().Health.Timeout, 3)
+		report := health.Ready(ctx.Request.Context(), timeout)
+		// 接口无需认证，错误详情仅写入日志
+		for name, result := range report.Checks {
+			if result.Error != "" {
+				logger.Module("health").WarnContext(ctx.Request.Context(), "readiness check failed", "check", name, "error", result.Error)
+			}
+		}
+		if report.Status != health.StatusUp {
+			ctx.JSON(http.StatusServiceUnavailable, report)
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	})
+}
+
+// secondsOrDefault 将秒数转换为时长，未配置时使用默认值
+func secondsOrDefault(seconds, defaultSeconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = defaultSeconds
+	}
+	return time.Duration(seconds) * time.Second
 }
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// GormPlugin 为Gorm查询创建span
+//
+// 只在Statement.Context中存在span时创建子span，service通过connector.DB(ctx)传入请求的context
+type GormPlugin struct {
+}
+
+const gormSpanKey = "tracing:span"
+
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	return errors.Join(
+		db.Callback().Create().Before("gorm:create").Register("tracing:before_create", p.before("gorm.create")),
+		db.Callback().Create().After("gorm:create").Register("tracing:after_create", p.after),
+		db.Callback().Query().Before("gorm:query").Register("tracing:before_query", p.before("gorm.query")),
+		db.Callback().Query().After("gorm:query").Register("tracing:after_query", p.after),
+		db.Callback().Update().Before("gorm:update").Register("tracing:before_update", p.before("gorm.update")),
+		db.Callback().Update().After("gorm:update").Register("tracing:after_update", p.after),
+		db.Callback().Delete().Before("gorm:delete").Register("tracing:before_delete", p.before("gorm.delete")),
+		db.Callback().Delete().After("gorm:delete").Register("tracing:after_delete", p.after),
+		db.Callback().Row().Before("gorm:row").Register("tracing:before_row", p.before("gorm.row")),
+		db.Callback().Row().After("gorm:row").Register("tracing:after_row", p.after),
+		db.Callback().Raw().Before("gorm:raw").Register("tracing:before_raw", p.before("gorm.raw")),
+		db.Callback().Raw().After("gorm:raw").Register("tracing:after_raw", p.after),
+	)
+}
+
+func (p *GormPlugin) before(spanName string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return
+		}
+		_, span := Tracer().Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			attribute.String("db.sql.table", db.Statement.Table),
+		))
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func (p *GormPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"github.com/hugo8680/goat/framework/config"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOtlp   = "otlp"   // 导出到OTLP HTTP接收端，如本地的OpenTelemetry Collector、Jaeger
+	ExporterStdout = "stdout" // 输出到控制台，用于调试
+
+	instrumentationName = "github.com/hugo8680/goat"
+)
+
+// Init 根据配置初始化链路追踪，未启用时使用默认的空实现
+//
+// 返回的函数用于导出剩余的span并关闭
+func Init(setting *config.Setting) (func(ctx context.Context) error, error) {
+	conf := setting.Trace
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !conf.Enabled {
+		return func(ctx context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch conf.Exporter {
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(conf.Endpoint)}
+		if conf.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), options...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := conf.ServiceName
+	if serviceName == "" {
+		serviceName = setting.System.Name
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", setting.System.Version),
+			attribute.String("deployment.environment", setting.Profile),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRate))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer 获取项目的tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
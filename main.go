@@ -1,10 +1,33 @@
 package main
 
 import (
+	"flag"
 	"github.com/hugo8680/goat/framework"
 	"github.com/hugo8680/goat/route"
+	"github.com/hugo8680/goat/service/admin"
 )
 
 func main() {
-	framework.RunServer(route.DefaultRoutes(), route.APIRoutes())
+	configPath := flag.String("config", "application.yml", "配置文件路径")
+	profile := flag.String("profile", "", "环境，如dev、prod，为空时读取GOAT_PROFILE环境变量")
+	flag.Parse()
+
+	app, err := framework.NewApp(*configPath, *profile)
+	if err != nil {
+		panic(err)
+	}
+	// 超级管理员配置热更新后清除权限缓存
+	framework.OnStart((&admin.PermissionService{}).WatchSuperAdmin)
+	jobService := &admin.JobService{}
+	// 加载定时任务，服务关闭时停止调度
+	framework.OnStart(jobService.InitScheduler)
+	framework.OnShutdown(jobService.StopScheduler)
+	// 启动操作日志及登录日志写入协程，服务关闭时写入队列中剩余的日志
+	operLogService := &admin.OperLogService{}
+	loginLogService := &admin.LoginLogService{}
+	framework.OnStart(operLogService.StartWriter)
+	framework.OnStart(loginLogService.StartWriter)
+	framework.OnShutdown(operLogService.StopWriter)
+	framework.OnShutdown(loginLogService.StopWriter)
+	app.Run(route.DefaultRoutes(), route.APIRoutes())
 }
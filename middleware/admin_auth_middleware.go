@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"github.com/hugo8680/goat/common/constant/auth"
+	"github.com/hugo8680/goat/framework/logger"
 	"github.com/hugo8680/goat/framework/response"
 	"github.com/hugo8680/goat/service/admin"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,8 +22,8 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		// 判断token临期，小于20分钟刷新
-		if authUser.ExpireTime.Time.Before(time.Now().Add(time.Minute * 20)) {
+		// sliding模式下判断token临期，小于20分钟刷新
+		if tokenService.Mode() == auth.TOKEN_MODE_SLIDING && authUser.ExpireTime.Time.Before(time.Now().Add(time.Minute*20)) {
 			tokenService.Refresh(ctx, authUser)
 		}
 		if authUser.Status != "0" {
@@ -30,6 +32,8 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 		ctx.Set(auth.CONTEXT_USER_KEY, authUser)
+		// 后续日志附带用户信息
+		ctx.Request = ctx.Request.WithContext(logger.WithAttrs(ctx.Request.Context(), slog.Int("user_id", authUser.UserId), slog.String("user_name", authUser.UserName)))
 		ctx.Next()
 	}
 }
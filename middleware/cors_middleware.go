@@ -1,21 +1,34 @@
 package middleware
 
 import (
+	"github.com/hugo8680/goat/common/request_id"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/framework/config"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // CorsMiddleware 跨域中间件
+//
+// 允许的来源读取cors.allowOrigins配置，支持热更新，未配置时允许所有来源
 func CorsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		method := c.Request.Method
 		origin := c.Request.Header.Get("Origin")
-		if origin != "" {
-			c.Header("Access-Control-Allow-Origin", "*")
+		allowOrigin := "*"
+		if allowOrigins := config.GetSetting().Cors.AllowOrigins; len(allowOrigins) > 0 {
+			allowOrigin = ""
+			if utils.Contains(allowOrigins, origin) || utils.Contains(allowOrigins, "*") {
+				allowOrigin = origin
+			}
+			c.Header("Vary", "Origin")
+		}
+		if origin != "" && allowOrigin != "" {
+			c.Header("Access-Control-Allow-Origin", allowOrigin)
 			c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, UPDATE")
-			c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, Authorization")
-			c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Cache-Control, Content-Language, Content-Type")
+			c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, Authorization, "+request_id.Header)
+			c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Cache-Control, Content-Language, Content-Type, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, "+request_id.Header)
 			c.Header("Access-Control-Allow-Credentials", "true")
 		}
 		if method == "OPTIONS" {
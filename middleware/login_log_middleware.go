@@ -3,7 +3,9 @@ package middleware
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/hugo8680/goat/common/constant/auth"
 	"github.com/hugo8680/goat/common/ip"
+	"github.com/hugo8680/goat/common/request_id"
 	"github.com/hugo8680/goat/common/response_writer"
 	"github.com/hugo8680/goat/common/serializer/datetime"
 	"github.com/hugo8680/goat/framework/response"
@@ -35,18 +37,27 @@ func LoginLogMiddleware() gin.HandlerFunc {
 		}
 		// 因ctx.ShouldBind后，请求体的数据流会被消耗完毕，需要将缓存的请求体重新赋值给ctx.Request.Body
 		ctx.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		ipAddr := ip.GetAddress(ctx.ClientIP(), ctx.Request.UserAgent())
+		// ip归属地由后台写入时查询，避免阻塞登录请求
+		browser, os := ip.ParseUserAgent(ctx.Request.UserAgent())
 		loginLog := dto.SaveLoginLogRequest{
-			UserName:      param.Username,
-			Ipaddr:        ipAddr.Ip,
-			LoginLocation: ipAddr.Addr,
-			Browser:       ipAddr.Browser,
-			Os:            ipAddr.Os,
-			Status:        "0",
-			LoginTime:     datetime.Datetime{Time: time.Now()},
+			UserName:  param.Username,
+			Ipaddr:    ctx.ClientIP(),
+			Browser:   browser,
+			Os:        os,
+			Status:    "0",
+			LoginTime: datetime.Datetime{Time: time.Now()},
+			RequestId: request_id.FromContext(ctx),
 		}
 		ctx.Writer = rw
 		ctx.Next()
+		// 需两步验证时登录尚未完成，由两步验证请求记录结果
+		if ctx.GetBool(auth.CONTEXT_LOGIN_PENDING_KEY) {
+			return
+		}
+		// 两步验证等请求体中不含用户名的登录由处理函数设置用户名
+		if userName := ctx.GetString(auth.CONTEXT_LOGIN_USER_NAME_KEY); userName != "" {
+			loginLog.UserName = userName
+		}
 		// 解析响应
 		var body response.Response
 		err := json.Unmarshal(rw.Body.Bytes(), &body)
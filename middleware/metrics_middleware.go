@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/hugo8680/goat/framework/config"
+	"github.com/hugo8680/goat/framework/metrics"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware 记录请求数及耗时，按路由模板统计，未匹配的路由统一记为unmatched
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHttpRequest(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status()), time.Since(start).Seconds())
+	}
+}
+
+// MetricsAuthMiddleware 指标接口访问控制
+//
+// 请求来源在metrics.allowIps中或通过basic认证即可访问，两者都未配置时不限制
+func MetricsAuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		conf := config.GetSetting().Metrics
+		if len(conf.AllowIps) == 0 && conf.Username == "" {
+			ctx.Next()
+			return
+		}
+		// 使用直连地址而不是X-Forwarded-For，避免伪造请求头绕过
+		if len(conf.AllowIps) > 0 && ipAllowed(ctx.RemoteIP(), conf.AllowIps) {
+			ctx.Next()
+			return
+		}
+		if conf.Username != "" {
+			username, password, ok := ctx.Request.BasicAuth()
+			if ok && subtle.ConstantTimeCompare([]byte(username), []byte(conf.Username)) == 1 && subtle.ConstantTimeCompare([]byte(password), []byte(conf.Password)) == 1 {
+				ctx.Next()
+				return
+			}
+			ctx.Header("WWW-Authenticate", `Basic realm="metrics"`)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx.AbortWithStatus(http.StatusForbidden)
+	}
+}
+
+// ipAllowed ip是否在允许列表中，支持单个ip及CIDR网段
+func ipAllowed(remoteIp string, allowIps []string) bool {
+	ip := net.ParseIP(remoteIp)
+	if ip == nil {
+		return false
+	}
+	for _, allowIp := range allowIps {
+		allowIp = strings.TrimSpace(allowIp)
+		if strings.Contains(allowIp, "/") {
+			if _, ipNet, err := net.ParseCIDR(allowIp); err == nil && ipNet.Contains(ip) {
+				return true
+			}
+		} else if allowed := net.ParseIP(allowIp); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,14 +3,15 @@ package middleware
 import (
 	"bytes"
 	"encoding/json"
-	"github.com/hugo8680/goat/common/ip"
+	"github.com/hugo8680/goat/common/masker"
+	"github.com/hugo8680/goat/common/request_id"
 	"github.com/hugo8680/goat/common/response_writer"
 	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/framework/logger"
 	"github.com/hugo8680/goat/framework/response"
 	"github.com/hugo8680/goat/model/dto"
 	"github.com/hugo8680/goat/service/admin"
 	"io"
-	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -44,7 +45,7 @@ func OperLogMiddleware(title string, businessType int) gin.HandlerFunc {
 		if err != nil {
 			err = json.Unmarshal(bodyBytes, &param)
 			if err != nil {
-				log.Println(err)
+				logger.Module("oper_log").WarnContext(ctx.Request.Context(), "parse request body", "error", err)
 			}
 		} else {
 			// 因ctx.ShouldBind后，请求体的数据流会被消耗完毕，需要将缓存的请求体重新赋值给ctx.Request.Body
@@ -54,8 +55,9 @@ func OperLogMiddleware(title string, businessType int) gin.HandlerFunc {
 		for key, value := range ctx.Request.URL.Query() {
 			param[key] = value
 		}
-		operParam, _ := json.Marshal(&param)
-		ipAddress := ip.GetAddress(ctx.ClientIP(), ctx.Request.UserAgent())
+		// 脱敏后入库，避免记录明文密码等敏感信息
+		operMasker := masker.Default()
+		operParam := operMasker.Truncate(operMasker.Marshal(operMasker.Value(param)))
 		sysOperLog := dto.SaveOperLogRequest{
 			Title:         title,
 			BusinessType:  businessType,
@@ -64,25 +66,25 @@ func OperLogMiddleware(title string, businessType int) gin.HandlerFunc {
 			OperName:      operName,
 			DeptName:      deptName,
 			OperUrl:       ctx.Request.URL.Path,
-			OperIp:        ipAddress.Ip,
-			OperLocation:  ipAddress.Addr,
-			OperParam:     string(operParam),
+			OperIp:        ctx.ClientIP(),
+			OperParam:     operParam,
 			JsonResult:    "",
 			Status:        "0",
 			ErrorMsg:      "",
 			OperTime:      datetime.Datetime{Time: time.Now()},
 			CostTime:      0,
+			RequestId:     request_id.FromContext(ctx),
 		}
 		ctx.Writer = rw
 		ctx.Next()
 		// 解析响应
 		var body response.Response
 		if ctx.Request.Header.Get("Content-Type") == "application/json" {
-			sysOperLog.JsonResult = rw.Body.String()
+			sysOperLog.JsonResult = operMasker.Truncate(operMasker.Json(rw.Body.String()))
 			err = json.Unmarshal(rw.Body.Bytes(), &body)
 			if err != nil || body.Code != 200 {
 				sysOperLog.Status = "1"
-				sysOperLog.ErrorMsg = body.Msg
+				sysOperLog.ErrorMsg = operMasker.Truncate(body.Msg)
 			}
 		} else {
 			sysOperLog.Status = "0"
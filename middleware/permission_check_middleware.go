@@ -1,22 +1,22 @@
 package middleware
 
 import (
+	"github.com/hugo8680/goat/common/permission"
 	"github.com/hugo8680/goat/framework/response"
 	"github.com/hugo8680/goat/service/admin"
 
 	"github.com/gin-gonic/gin"
 )
 
-// PermissionCheckMiddleware 验证用户是否具备某权限
-func PermissionCheckMiddleware(perm string) gin.HandlerFunc {
+// PermissionCheckMiddleware 验证用户是否满足权限表达式，超级管理员不做校验
+//
+// expression 权限表达式，如 system:user:list、system:user:add && system:user:edit、role(admin) || system:user:*，格式错误时panic
+func PermissionCheckMiddleware(expression string) gin.HandlerFunc {
+	parsed := permission.MustParse(expression)
 	return func(ctx *gin.Context) {
 		securityService := &admin.SecurityService{}
 		authUserId, _ := securityService.GetCurrentUserId(ctx)
-		if authUserId == 1 {
-			ctx.Next()
-			return
-		}
-		if hasPerm := securityService.HasPerm(authUserId, perm); !hasPerm {
+		if !securityService.Check(ctx.Request.Context(), authUserId, parsed) {
 			response.Error(ctx).SetCode(601).SetMsg("权限不足").Json()
 			ctx.Abort()
 			return
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/service/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PwdChangeCheckMiddleware 初始密码须修改或密码已过期的会话，修改密码前拒绝访问
+func PwdChangeCheckMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authUser, err := (&admin.SecurityService{}).GetCurrentUser(ctx)
+		if err == nil && authUser.PwdChange {
+			response.Error(ctx).SetCode(403).SetMsg("请先修改密码").Json()
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/framework/ratelimit"
+	"github.com/hugo8680/goat/framework/response"
+	"github.com/hugo8680/goat/service/admin"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKeyFunc 生成限流键，相同键共用请求额度
+type RateLimitKeyFunc func(ctx *gin.Context) string
+
+// RateLimitByIp 按客户端ip限流
+//
+// 仅来自server.trustedProxies的请求才读取X-Forwarded-For，客户端无法通过伪造请求头更换限流键
+func RateLimitByIp(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// RateLimitByUser 按登录用户限流，未登录时按客户端ip限流
+func RateLimitByUser(ctx *gin.Context) string {
+	if userId, err := (&admin.SecurityService{}).GetCurrentUserId(ctx); err == nil && userId > 0 {
+		return "user:" + strconv.Itoa(userId)
+	}
+	return RateLimitByIp(ctx)
+}
+
+// RateLimit 限流配置
+//
+// Name 限流名称，相同名称的路由共用请求额度，为空时按路由分别限流
+// Algorithm 限流算法，默认令牌桶
+// Limit 窗口内允许的请求数，令牌桶时为桶容量
+// Window 窗口时长，令牌桶时为桶从空到满的时长
+// Key 限流键，默认按客户端ip
+type RateLimit struct {
+	Name      string
+	Algorithm ratelimit.Algorithm
+	Limit     int
+	Window    time.Duration
+	Key       RateLimitKeyFunc
+}
+
+// RateLimitMiddleware 限流中间件，计数保存在redis中多实例共享，超出限制返回429
+//
+// 可用于路由或路由组的Middlewares，用于路由组时在认证中间件之前执行，按用户限流需用于路由
+func RateLimitMiddleware(option RateLimit) gin.HandlerFunc {
+	if option.Limit <= 0 || option.Window <= 0 {
+		panic("限流上限及窗口必须大于0")
+	}
+	if option.Key == nil {
+		option.Key = RateLimitByIp
+	}
+	return func(ctx *gin.Context) {
+		name := option.Name
+		if name == "" {
+			name = ctx.Request.Method + ":" + ctx.FullPath()
+		}
+		result, err := ratelimit.Allow(ctx.Request.Context(), option.Algorithm, redis_key.RateLimitKey+name+":"+option.Key(ctx), option.Limit, option.Window)
+		// redis不可用时放行，避免限流影响正常访问
+		if err != nil {
+			logger.Module("ratelimit").WarnContext(ctx.Request.Context(), "rate limit", "name", name, "error", err)
+			ctx.Next()
+			return
+		}
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("X-RateLimit-Reset", seconds(result.Reset))
+		if !result.Allowed {
+			ctx.Header("Retry-After", seconds(result.RetryAfter))
+			response.Error(ctx).SetStatus(http.StatusTooManyRequests).SetCode(http.StatusTooManyRequests).SetMsg("请求过于频繁，请稍后再试").Json()
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// seconds 时长向上取整为秒
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
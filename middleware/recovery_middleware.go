@@ -1,9 +1,10 @@
 package middleware
 
 import (
+	"github.com/hugo8680/goat/framework/logger"
 	"github.com/hugo8680/goat/framework/response"
-	"log"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,7 +14,7 @@ func RecoveryMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("recovered: %v", err)
+				logger.Module("http").ErrorContext(ctx.Request.Context(), "recovered", "error", err, "stack", string(debug.Stack()))
 				response.Error(ctx).SetCode(http.StatusInternalServerError).SetMsg("服务器错误").Json()
 				ctx.Abort()
 			}
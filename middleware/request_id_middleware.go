@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/hugo8680/goat/common/request_id"
+	"github.com/hugo8680/goat/framework/logger"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIdMiddleware 请求id中间件
+//
+// 优先使用请求头中合法的X-Request-Id，否则生成新的请求id，写入上下文、日志字段及响应头
+func RequestIdMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetHeader(request_id.Header)
+		if !request_id.Valid(requestId) {
+			requestId = request_id.New()
+		}
+		ctx.Set(request_id.ContextKey, requestId)
+		ctx.Header(request_id.Header, requestId)
+		requestCtx := request_id.WithContext(ctx.Request.Context(), requestId)
+		ctx.Request = ctx.Request.WithContext(logger.WithAttrs(requestCtx, slog.String("request_id", requestId)))
+		ctx.Next()
+	}
+}
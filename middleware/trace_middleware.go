@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/hugo8680/goat/common/request_id"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/framework/tracing"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceMiddleware 链路追踪中间件，为每个请求创建span，并继承请求头中传入的链路信息
+//
+// 需在RequestIdMiddleware之后注册
+func TraceMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestCtx := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		route := ctx.FullPath()
+		if route == "" {
+			route = ctx.Request.URL.Path
+		}
+		requestCtx, span := tracing.Tracer().Start(requestCtx, ctx.Request.Method+" "+route, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			attribute.String("http.request.method", ctx.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("url.path", ctx.Request.URL.Path),
+			attribute.String("client.address", ctx.ClientIP()),
+			attribute.String("request.id", request_id.FromContext(ctx)),
+		))
+		defer span.End()
+		if span.SpanContext().IsValid() {
+			requestCtx = logger.WithAttrs(requestCtx, slog.String("trace_id", span.SpanContext().TraceID().String()))
+		}
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, "")
+		}
+		if len(ctx.Errors) > 0 {
+			span.RecordError(ctx.Errors.Last())
+		}
+	}
+}
@@ -11,8 +11,14 @@ type RegisterRequest struct {
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Code     string `json:"code"`
-	Uuid     string `json:"uuid"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	Code       string `json:"code"`
+	Uuid       string `json:"uuid"`
+	ClientType string `json:"clientType"` // 客户端类型，web或mobile，默认web
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
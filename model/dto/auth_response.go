@@ -7,11 +7,25 @@ type CaptchaResponse struct {
 	CaptchaEnabled bool   `json:"captchaEnabled"`
 }
 
+// TokenResponse 登录令牌，sliding模式下仅返回token
+//
+// 需要两步验证时不返回token，仅返回两步验证登录凭证
+type TokenResponse struct {
+	Token           string   `json:"token"`
+	RefreshToken    string   `json:"refreshToken,omitempty"`
+	ExpiresIn       int      `json:"expiresIn,omitempty"` // 访问令牌有效期，单位秒
+	TwoFactorTicket string   `json:"ticket,omitempty"`
+	TwoFactorSetup  bool     `json:"setup,omitempty"`         // 角色要求两步验证但尚未绑定
+	RecoveryCodes   []string `json:"recoveryCodes,omitempty"` // 登录时完成绑定返回的恢复码
+	PwdChangeMsg    string   `json:"pwdChangeMsg,omitempty"`  // 须修改密码的原因，为空时无需修改
+}
+
 type RegisterResponse struct {
 }
 
 type AuthInfoResponse struct {
-	User        AuthUserInfoResponse `json:"user"`
-	Roles       []string             `json:"roles"`
-	Permissions []string             `json:"permissions"`
+	User         AuthUserInfoResponse `json:"user"`
+	Roles        []string             `json:"roles"`
+	Permissions  []string             `json:"permissions"`
+	PwdChangeMsg string               `json:"pwdChangeMsg"`
 }
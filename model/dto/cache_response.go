@@ -0,0 +1,29 @@
+package dto
+
+// CacheInfoResponse 缓存监控信息
+type CacheInfoResponse struct {
+	Info         map[string]string          `json:"info"`
+	DbSize       int64                      `json:"dbSize"`
+	CommandStats []CacheCommandStatResponse `json:"commandStats"`
+}
+
+// CacheCommandStatResponse 命令统计
+type CacheCommandStatResponse struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CacheNameResponse 缓存名称
+type CacheNameResponse struct {
+	CacheName string `json:"cacheName"`
+	Remark    string `json:"remark"`
+}
+
+// CacheValueResponse 缓存内容
+type CacheValueResponse struct {
+	CacheName  string `json:"cacheName"`
+	CacheKey   string `json:"cacheKey"`
+	CacheType  string `json:"cacheType"`
+	CacheValue string `json:"cacheValue"`
+	Ttl        int64  `json:"ttl"`
+}
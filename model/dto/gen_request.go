@@ -0,0 +1,101 @@
+package dto
+
+// GenTableListRequest 代码生成业务表列表
+type GenTableListRequest struct {
+	PageRequest
+	TableName    string `query:"tableName" form:"tableName"`
+	TableComment string `query:"tableComment" form:"tableComment"`
+	BeginTime    string `query:"params[beginTime]" form:"params[beginTime]"`
+	EndTime      string `query:"params[endTime]" form:"params[endTime]"`
+}
+
+// DbTableListRequest 数据库表列表
+type DbTableListRequest struct {
+	PageRequest
+	TableName    string `query:"tableName" form:"tableName"`
+	TableComment string `query:"tableComment" form:"tableComment"`
+}
+
+// ImportGenTableRequest 导入表结构
+type ImportGenTableRequest struct {
+	Tables string `query:"tables" form:"tables"`
+}
+
+// SaveGenTableRequest 保存代码生成业务表
+type SaveGenTableRequest struct {
+	TableId        int    `json:"tableId"`
+	TableName      string `json:"tableName"`
+	TableComment   string `json:"tableComment"`
+	ClassName      string `json:"className"`
+	TplCategory    string `json:"tplCategory"`
+	PackageName    string `json:"packageName"`
+	ModuleName     string `json:"moduleName"`
+	BusinessName   string `json:"businessName"`
+	FunctionName   string `json:"functionName"`
+	FunctionAuthor string `json:"functionAuthor"`
+	GenType        string `json:"genType"`
+	GenPath        string `json:"genPath"`
+	Options        string `json:"options"`
+	CreateBy       string `json:"createBy"`
+	UpdateBy       string `json:"updateBy"`
+	Remark         string `json:"remark"`
+}
+
+// SaveGenTableColumnRequest 保存代码生成业务表字段
+type SaveGenTableColumnRequest struct {
+	ColumnId      int    `json:"columnId"`
+	TableId       int    `json:"tableId"`
+	ColumnName    string `json:"columnName"`
+	ColumnComment string `json:"columnComment"`
+	ColumnType    string `json:"columnType"`
+	GoType        string `json:"goType"`
+	GoField       string `json:"goField"`
+	IsPk          string `json:"isPk"`
+	IsIncrement   string `json:"isIncrement"`
+	IsRequired    string `json:"isRequired"`
+	IsInsert      string `json:"isInsert"`
+	IsEdit        string `json:"isEdit"`
+	IsList        string `json:"isList"`
+	IsQuery       string `json:"isQuery"`
+	QueryType     string `json:"queryType"`
+	HtmlType      string `json:"htmlType"`
+	DictType      string `json:"dictType"`
+	Sort          int    `json:"sort"`
+	CreateBy      string `json:"createBy"`
+	UpdateBy      string `json:"updateBy"`
+}
+
+// UpdateGenTableRequest 修改代码生成业务表
+type UpdateGenTableRequest struct {
+	TableId        int                           `json:"tableId"`
+	TableName      string                        `json:"tableName"`
+	TableComment   string                        `json:"tableComment"`
+	ClassName      string                        `json:"className"`
+	TplCategory    string                        `json:"tplCategory"`
+	PackageName    string                        `json:"packageName"`
+	ModuleName     string                        `json:"moduleName"`
+	BusinessName   string                        `json:"businessName"`
+	FunctionName   string                        `json:"functionName"`
+	FunctionAuthor string                        `json:"functionAuthor"`
+	GenType        string                        `json:"genType"`
+	GenPath        string                        `json:"genPath"`
+	ParentMenuId   int                           `json:"parentMenuId"`
+	Remark         string                        `json:"remark"`
+	Columns        []UpdateGenTableColumnRequest `json:"columns"`
+}
+
+// UpdateGenTableColumnRequest 修改代码生成业务表字段
+type UpdateGenTableColumnRequest struct {
+	ColumnId      int    `json:"columnId"`
+	ColumnComment string `json:"columnComment"`
+	GoType        string `json:"goType"`
+	GoField       string `json:"goField"`
+	IsRequired    string `json:"isRequired"`
+	IsInsert      string `json:"isInsert"`
+	IsEdit        string `json:"isEdit"`
+	IsList        string `json:"isList"`
+	IsQuery       string `json:"isQuery"`
+	QueryType     string `json:"queryType"`
+	HtmlType      string `json:"htmlType"`
+	DictType      string `json:"dictType"`
+}
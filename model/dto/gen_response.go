@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+// GenTableListResponse 代码生成业务表列表
+type GenTableListResponse struct {
+	TableId      int               `json:"tableId"`
+	TableName    string            `json:"tableName"`
+	TableComment string            `json:"tableComment"`
+	ClassName    string            `json:"className"`
+	CreateTime   datetime.Datetime `json:"createTime"`
+	UpdateTime   datetime.Datetime `json:"updateTime"`
+}
+
+// GenTableDetailResponse 代码生成业务表详情
+type GenTableDetailResponse struct {
+	TableId        int                      `json:"tableId"`
+	TableName      string                   `json:"tableName"`
+	TableComment   string                   `json:"tableComment"`
+	ClassName      string                   `json:"className"`
+	TplCategory    string                   `json:"tplCategory"`
+	PackageName    string                   `json:"packageName"`
+	ModuleName     string                   `json:"moduleName"`
+	BusinessName   string                   `json:"businessName"`
+	FunctionName   string                   `json:"functionName"`
+	FunctionAuthor string                   `json:"functionAuthor"`
+	GenType        string                   `json:"genType"`
+	GenPath        string                   `json:"genPath"`
+	Options        string                   `json:"options"`
+	ParentMenuId   int                      `json:"parentMenuId" gorm:"-"`
+	Remark         string                   `json:"remark"`
+	Columns        []GenTableColumnResponse `json:"columns" gorm:"-"`
+}
+
+// GenTableColumnResponse 代码生成业务表字段
+type GenTableColumnResponse struct {
+	ColumnId      int    `json:"columnId"`
+	TableId       int    `json:"tableId"`
+	ColumnName    string `json:"columnName"`
+	ColumnComment string `json:"columnComment"`
+	ColumnType    string `json:"columnType"`
+	GoType        string `json:"goType"`
+	GoField       string `json:"goField"`
+	IsPk          string `json:"isPk"`
+	IsIncrement   string `json:"isIncrement"`
+	IsRequired    string `json:"isRequired"`
+	IsInsert      string `json:"isInsert"`
+	IsEdit        string `json:"isEdit"`
+	IsList        string `json:"isList"`
+	IsQuery       string `json:"isQuery"`
+	QueryType     string `json:"queryType"`
+	HtmlType      string `json:"htmlType"`
+	DictType      string `json:"dictType"`
+	Sort          int    `json:"sort"`
+}
+
+// DbTableListResponse 数据库表列表
+type DbTableListResponse struct {
+	TableName    string            `json:"tableName"`
+	TableComment string            `json:"tableComment"`
+	CreateTime   datetime.Datetime `json:"createTime"`
+	UpdateTime   datetime.Datetime `json:"updateTime"`
+}
+
+// DbTableColumnResponse 数据库表字段
+type DbTableColumnResponse struct {
+	ColumnName    string `json:"columnName"`
+	ColumnComment string `json:"columnComment"`
+	ColumnType    string `json:"columnType"`
+	IsRequired    string `json:"isRequired"`
+	IsPk          string `json:"isPk"`
+	IsIncrement   string `json:"isIncrement"`
+	Sort          int    `json:"sort"`
+}
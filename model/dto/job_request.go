@@ -0,0 +1,61 @@
+package dto
+
+// SaveJobRequest 保存定时任务
+type SaveJobRequest struct {
+	JobId          int    `json:"jobId"`
+	JobName        string `json:"jobName"`
+	JobGroup       string `json:"jobGroup"`
+	InvokeTarget   string `json:"invokeTarget"`
+	CronExpression string `json:"cronExpression"`
+	MisfirePolicy  string `json:"misfirePolicy"`
+	Concurrent     string `json:"concurrent"`
+	Status         string `json:"status"`
+	CreateBy       string `json:"createBy"`
+	UpdateBy       string `json:"updateBy"`
+	Remark         string `json:"remark"`
+}
+
+// JobListRequest 定时任务列表
+type JobListRequest struct {
+	PageRequest
+	JobName      string `query:"jobName" form:"jobName"`
+	JobGroup     string `query:"jobGroup" form:"jobGroup"`
+	InvokeTarget string `query:"invokeTarget" form:"invokeTarget"`
+	Status       string `query:"status" form:"status"`
+}
+
+// CreateJobRequest 新增定时任务
+type CreateJobRequest struct {
+	JobName        string `json:"jobName"`
+	JobGroup       string `json:"jobGroup"`
+	InvokeTarget   string `json:"invokeTarget"`
+	CronExpression string `json:"cronExpression"`
+	MisfirePolicy  string `json:"misfirePolicy"`
+	Concurrent     string `json:"concurrent"`
+	Status         string `json:"status"`
+	Remark         string `json:"remark"`
+}
+
+// UpdateJobRequest 更新定时任务
+type UpdateJobRequest struct {
+	JobId          int    `json:"jobId"`
+	JobName        string `json:"jobName"`
+	JobGroup       string `json:"jobGroup"`
+	InvokeTarget   string `json:"invokeTarget"`
+	CronExpression string `json:"cronExpression"`
+	MisfirePolicy  string `json:"misfirePolicy"`
+	Concurrent     string `json:"concurrent"`
+	Status         string `json:"status"`
+	Remark         string `json:"remark"`
+}
+
+// JobLogListRequest 调度日志列表
+type JobLogListRequest struct {
+	PageRequest
+	JobName      string `query:"jobName" form:"jobName"`
+	JobGroup     string `query:"jobGroup" form:"jobGroup"`
+	InvokeTarget string `query:"invokeTarget" form:"invokeTarget"`
+	Status       string `query:"status" form:"status"`
+	BeginTime    string `query:"params[beginTime]" form:"params[beginTime]"`
+	EndTime      string `query:"params[endTime]" form:"params[endTime]"`
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+// JobListResponse 定时任务列表
+type JobListResponse struct {
+	JobId          int               `json:"jobId"`
+	JobName        string            `json:"jobName"`
+	JobGroup       string            `json:"jobGroup"`
+	InvokeTarget   string            `json:"invokeTarget"`
+	CronExpression string            `json:"cronExpression"`
+	MisfirePolicy  string            `json:"misfirePolicy"`
+	Concurrent     string            `json:"concurrent"`
+	Status         string            `json:"status"`
+	CreateTime     datetime.Datetime `json:"createTime"`
+}
+
+// JobDetailResponse 定时任务详情
+type JobDetailResponse struct {
+	JobId          int               `json:"jobId"`
+	JobName        string            `json:"jobName"`
+	JobGroup       string            `json:"jobGroup"`
+	InvokeTarget   string            `json:"invokeTarget"`
+	CronExpression string            `json:"cronExpression"`
+	MisfirePolicy  string            `json:"misfirePolicy"`
+	Concurrent     string            `json:"concurrent"`
+	Status         string            `json:"status"`
+	CreateTime     datetime.Datetime `json:"createTime"`
+	Remark         string            `json:"remark"`
+	NextValidTime  string            `json:"nextValidTime" gorm:"-"`
+}
+
+// JobExportResponse 定时任务导出
+type JobExportResponse struct {
+	JobId          int    `excel:"name:任务序号;"`
+	JobName        string `excel:"name:任务名称;"`
+	JobGroup       string `excel:"name:任务组名;replace:DEFAULT_默认,SYSTEM_系统;"`
+	InvokeTarget   string `excel:"name:调用目标字符串;"`
+	CronExpression string `excel:"name:执行表达式;"`
+	MisfirePolicy  string `excel:"name:计划策略;replace:0_默认,1_立即执行,2_执行一次,3_放弃执行;"`
+	Concurrent     string `excel:"name:并发执行;replace:0_允许,1_禁止;"`
+	Status         string `excel:"name:任务状态;replace:0_正常,1_暂停;"`
+}
+
+// JobLogListResponse 调度日志列表
+type JobLogListResponse struct {
+	JobLogId      int               `json:"jobLogId"`
+	JobId         int               `json:"jobId"`
+	JobName       string            `json:"jobName"`
+	JobGroup      string            `json:"jobGroup"`
+	InvokeTarget  string            `json:"invokeTarget"`
+	JobMessage    string            `json:"jobMessage"`
+	Status        string            `json:"status"`
+	ExceptionInfo string            `json:"exceptionInfo"`
+	StartTime     datetime.Datetime `json:"startTime"`
+	StopTime      datetime.Datetime `json:"stopTime"`
+	CreateTime    datetime.Datetime `json:"createTime"`
+}
+
+// JobLogExportResponse 调度日志导出
+type JobLogExportResponse struct {
+	JobLogId      int    `excel:"name:日志序号;"`
+	JobName       string `excel:"name:任务名称;"`
+	JobGroup      string `excel:"name:任务组名;replace:DEFAULT_默认,SYSTEM_系统;"`
+	InvokeTarget  string `excel:"name:调用目标字符串;"`
+	JobMessage    string `excel:"name:日志信息;"`
+	Status        string `excel:"name:执行状态;replace:0_正常,1_失败;"`
+	ExceptionInfo string `excel:"name:异常信息;"`
+	CreateTime    string `excel:"name:执行时间;"`
+}
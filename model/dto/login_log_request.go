@@ -8,6 +8,7 @@ import (
 type LoginLogListRequest struct {
 	PageRequest
 	Ipaddr        string `query:"ipaddr" form:"ipaddr"`
+	RequestId     string `query:"requestId" form:"requestId"`
 	UserName      string `query:"userName" form:"userName"`
 	Status        string `query:"status" form:"status"`
 	BeginTime     string `query:"params[beginTime]" form:"params[beginTime]"`
@@ -27,4 +28,5 @@ type SaveLoginLogRequest struct {
 	Status        string            `json:"status"`
 	Msg           string            `json:"msg"`
 	LoginTime     datetime.Datetime `json:"loginTime"`
+	RequestId     string            `json:"requestId"`
 }
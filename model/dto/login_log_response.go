@@ -15,6 +15,7 @@ type LoginLogListResponse struct {
 	Status        string            `json:"status"`
 	Msg           string            `json:"msg"`
 	LoginTime     datetime.Datetime `json:"loginTime"`
+	RequestId     string            `json:"requestId"`
 }
 
 // LoginLogExportResponse 登陆日志导出
@@ -28,4 +29,5 @@ type LoginLogExportResponse struct {
 	Os            string `excel:"name:操作系统;"`
 	Msg           string `excel:"name:提示消息;"`
 	LoginTime     string `excel:"name:访问时间;"`
+	RequestId     string `excel:"name:请求id;"`
 }
@@ -0,0 +1,7 @@
+package dto
+
+// OnlineListRequest 在线用户列表
+type OnlineListRequest struct {
+	Ipaddr   string `query:"ipaddr" form:"ipaddr"`
+	UserName string `query:"userName" form:"userName"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+// OnlineListResponse 在线用户列表
+type OnlineListResponse struct {
+	TokenId       string            `json:"tokenId"`
+	UserName      string            `json:"userName"`
+	DeptName      string            `json:"deptName"`
+	Ipaddr        string            `json:"ipaddr"`
+	LoginLocation string            `json:"loginLocation"`
+	Browser       string            `json:"browser"`
+	Os            string            `json:"os"`
+	LoginTime     datetime.Datetime `json:"loginTime"`
+}
@@ -8,6 +8,7 @@ import (
 type OperLogListRequest struct {
 	PageRequest
 	OperIp        string `query:"operIp" form:"operIp"`
+	RequestId     string `query:"requestId" form:"requestId"`
 	Title         string `query:"title" form:"title"`
 	OperName      string `query:"operName" form:"operName"`
 	BusinessType  string `query:"businessType" form:"businessType"`
@@ -36,4 +37,5 @@ type SaveOperLogRequest struct {
 	ErrorMsg      string            `json:"errorMsg"`
 	OperTime      datetime.Datetime `json:"operTime"`
 	CostTime      int               `json:"costTime"`
+	RequestId     string            `json:"requestId"`
 }
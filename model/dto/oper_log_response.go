@@ -22,6 +22,7 @@ type OperLogListResponse struct {
 	ErrorMsg      string            `json:"errorMsg"`
 	OperTime      datetime.Datetime `json:"operTime"`
 	CostTime      int               `json:"costTime"`
+	RequestId     string            `json:"requestId"`
 }
 
 // OperLogExportResponse 操作日志导出
@@ -42,4 +43,5 @@ type OperLogExportResponse struct {
 	ErrorMsg      string `excel:"name:错误消息;"`
 	OperTime      string `excel:"name:操作时间;"`
 	CostTime      string `excel:"name:消耗时间;"`
+	RequestId     string `excel:"name:请求id;"`
 }
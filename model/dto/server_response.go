@@ -0,0 +1,81 @@
+package dto
+
+// ServerResponse 服务监控
+type ServerResponse struct {
+	Cpu      ServerCpuResponse     `json:"cpu"`
+	Mem      ServerMemResponse     `json:"mem"`
+	Process  ServerProcessResponse `json:"process"`
+	Runtime  ServerRuntimeResponse `json:"runtime"`
+	Sys      ServerSysResponse     `json:"sys"`
+	SysFiles []ServerDiskResponse  `json:"sysFiles"`
+}
+
+// ServerCpuResponse 主机CPU信息
+type ServerCpuResponse struct {
+	CpuNum    int     `json:"cpuNum"`
+	ModelName string  `json:"modelName"`
+	Used      float64 `json:"used"`
+	Free      float64 `json:"free"`
+}
+
+// ServerMemResponse 主机内存信息，单位字节
+type ServerMemResponse struct {
+	Total uint64  `json:"total"`
+	Used  uint64  `json:"used"`
+	Free  uint64  `json:"free"`
+	Usage float64 `json:"usage"`
+}
+
+// ServerProcessResponse 当前进程信息
+type ServerProcessResponse struct {
+	Pid        int32   `json:"pid"`
+	CpuPercent float64 `json:"cpuPercent"`
+	MemPercent float32 `json:"memPercent"`
+	Rss        uint64  `json:"rss"`
+	Vms        uint64  `json:"vms"`
+	NumThreads int32   `json:"numThreads"`
+	StartTime  string  `json:"startTime"`
+	RunTime    string  `json:"runTime"`
+}
+
+// ServerRuntimeResponse Go运行时信息
+type ServerRuntimeResponse struct {
+	Version       string  `json:"version"`
+	Home          string  `json:"home"`
+	NumCpu        int     `json:"numCpu"`
+	GoMaxProcs    int     `json:"goMaxProcs"`
+	NumGoroutine  int     `json:"numGoroutine"`
+	HeapAlloc     uint64  `json:"heapAlloc"`
+	HeapSys       uint64  `json:"heapSys"`
+	HeapInuse     uint64  `json:"heapInuse"`
+	HeapObjects   uint64  `json:"heapObjects"`
+	StackInuse    uint64  `json:"stackInuse"`
+	Sys           uint64  `json:"sys"`
+	NumGC         uint32  `json:"numGc"`
+	PauseTotalNs  uint64  `json:"pauseTotalNs"`
+	LastPauseNs   uint64  `json:"lastPauseNs"`
+	LastGC        string  `json:"lastGc"`
+	GCCPUFraction float64 `json:"gcCpuFraction"`
+}
+
+// ServerSysResponse 主机系统信息
+type ServerSysResponse struct {
+	ComputerName string `json:"computerName"`
+	ComputerIp   string `json:"computerIp"`
+	OsName       string `json:"osName"`
+	OsArch       string `json:"osArch"`
+	Platform     string `json:"platform"`
+	UserDir      string `json:"userDir"`
+	BootTime     string `json:"bootTime"`
+}
+
+// ServerDiskResponse 磁盘信息，单位字节
+type ServerDiskResponse struct {
+	DirName     string  `json:"dirName"`
+	SysTypeName string  `json:"sysTypeName"`
+	TypeName    string  `json:"typeName"`
+	Total       uint64  `json:"total"`
+	Free        uint64  `json:"free"`
+	Used        uint64  `json:"used"`
+	Usage       float64 `json:"usage"`
+}
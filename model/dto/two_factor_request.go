@@ -0,0 +1,24 @@
+package dto
+
+// TwoFactorLoginRequest 两步验证登录，code与recoveryCode二选一
+type TwoFactorLoginRequest struct {
+	Ticket       string `json:"ticket"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+// TwoFactorTicketRequest 使用登录凭证绑定两步验证
+type TwoFactorTicketRequest struct {
+	Ticket string `json:"ticket"`
+}
+
+// TwoFactorCodeRequest 两步验证码，code与recoveryCode二选一
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+// ResetTwoFactorRequest 重置用户两步验证
+type ResetTwoFactorRequest struct {
+	UserId int `json:"userId"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+// TwoFactorStatusResponse 两步验证状态
+type TwoFactorStatusResponse struct {
+	Enabled       bool `json:"enabled"`
+	Forced        bool `json:"forced"`        // 角色要求必须启用
+	RecoveryCodes int  `json:"recoveryCodes"` // 剩余恢复码数量
+}
+
+// TwoFactorSetupResponse 两步验证绑定信息
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+	QrCode string `json:"qrCode"` // otpauth地址的二维码，base64编码的png图片
+}
@@ -6,22 +6,23 @@ import (
 
 // SaveUserRequest 保存用户
 type SaveUserRequest struct {
-	UserId      int               `json:"userId"`
-	DeptId      int               `json:"deptId"`
-	UserName    string            `json:"userName"`
-	NickName    string            `json:"nickName"`
-	UserType    string            `json:"userType"`
-	Email       string            `json:"email"`
-	PhoneNumber string            `json:"phoneNumber"`
-	Sex         string            `json:"sex"`
-	Avatar      string            `json:"avatar"`
-	Password    string            `json:"password"`
-	LoginIP     string            `json:"loginIp"`
-	LoginDate   datetime.Datetime `json:"loginDate"`
-	Status      string            `json:"status"`
-	CreateBy    string            `json:"createBy"`
-	UpdateBy    string            `json:"updateBy"`
-	Remark      string            `json:"remark"`
+	UserId        int               `json:"userId"`
+	DeptId        int               `json:"deptId"`
+	UserName      string            `json:"userName"`
+	NickName      string            `json:"nickName"`
+	UserType      string            `json:"userType"`
+	Email         string            `json:"email"`
+	PhoneNumber   string            `json:"phoneNumber"`
+	Sex           string            `json:"sex"`
+	Avatar        string            `json:"avatar"`
+	Password      string            `json:"password"`
+	LoginIP       string            `json:"loginIp"`
+	LoginDate     datetime.Datetime `json:"loginDate"`
+	Status        string            `json:"status"`
+	PwdMustChange string            `json:"pwdMustChange"` // 下次登录须修改密码：0-否；1-是
+	CreateBy      string            `json:"createBy"`
+	UpdateBy      string            `json:"updateBy"`
+	Remark        string            `json:"remark"`
 }
 
 // UserListRequest 用户列表
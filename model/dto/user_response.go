@@ -7,15 +7,23 @@ import (
 
 // UserTokenResponse 用户Token信息
 type UserTokenResponse struct {
-	UserId     int               `json:"userId"`
-	DeptId     int               `json:"deptId"`
-	UserName   string            `json:"userName"`
-	NickName   string            `json:"nickName"`
-	UserType   string            `json:"userType"`
-	Password   string            `json:"-"`
-	Status     string            `json:"status"`
-	DeptName   string            `json:"deptName"`
-	ExpireTime datetime.Datetime `json:"expireTime"`
+	UserId        int               `json:"userId"`
+	DeptId        int               `json:"deptId"`
+	UserName      string            `json:"userName"`
+	NickName      string            `json:"nickName"`
+	UserType      string            `json:"userType"`
+	Password      string            `json:"-"`
+	Status        string            `json:"status"`
+	DeptName      string            `json:"deptName"`
+	TokenId       string            `json:"tokenId"`
+	ClientType    string            `json:"clientType"`
+	Ipaddr        string            `json:"ipaddr"`
+	LoginLocation string            `json:"loginLocation"`
+	Browser       string            `json:"browser"`
+	Os            string            `json:"os"`
+	LoginTime     datetime.Datetime `json:"loginTime"`
+	ExpireTime    datetime.Datetime `json:"expireTime"`
+	PwdChange     bool              `json:"pwdChange"` // 须修改密码后才能访问其他接口
 }
 
 // MarshalBinary 序列化dto.UserTokenResponse，实现redis读写
@@ -28,6 +36,23 @@ func (u *UserTokenResponse) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, u)
 }
 
+// UserPermissionResponse 用户权限标识及角色key
+type UserPermissionResponse struct {
+	Perms []string `json:"perms"`
+	Roles []string `json:"roles"`
+	Admin bool     `json:"admin"`
+}
+
+// MarshalBinary 序列化dto.UserPermissionResponse，实现redis读写
+func (u UserPermissionResponse) MarshalBinary() ([]byte, error) {
+	return json.Marshal(u)
+}
+
+// UnmarshalBinary 反序列化dto.UserPermissionResponse，实现redis读写
+func (u *UserPermissionResponse) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, u)
+}
+
 // UserListResponse 用户列表
 type UserListResponse struct {
 	UserId      int               `json:"userId"`
@@ -65,6 +90,7 @@ type UserDetailResponse struct {
 	LoginIP     string            `json:"loginIp"`
 	LoginDate   datetime.Datetime `json:"loginDate"`
 	Status      string            `json:"status"`
+	TotpEnabled string            `json:"totpEnabled"`
 	CreateTime  datetime.Datetime `json:"createTime"`
 	Admin       bool              `json:"admin" gorm:"-"`
 }
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+// GenTable 代码生成业务表
+//
+// 字段TableName与gorm的Tabler接口同名，因此不实现TableName方法，表名由命名策略推导为gen_table
+type GenTable struct {
+	TableId        int `gorm:"primaryKey;autoIncrement"`
+	TableName      string
+	TableComment   string
+	ClassName      string
+	TplCategory    string `gorm:"default:crud"`
+	PackageName    string
+	ModuleName     string
+	BusinessName   string
+	FunctionName   string
+	FunctionAuthor string
+	GenType        string `gorm:"default:0"`
+	GenPath        string `gorm:"default:/"`
+	Options        string
+	CreateBy       string
+	CreateTime     datetime.Datetime `gorm:"autoCreateTime"`
+	UpdateBy       string
+	UpdateTime     datetime.Datetime `gorm:"autoUpdateTime"`
+	Remark         string
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+type GenTableColumn struct {
+	ColumnId      int `gorm:"primaryKey;autoIncrement"`
+	TableId       int
+	ColumnName    string
+	ColumnComment string
+	ColumnType    string
+	GoType        string
+	GoField       string
+	IsPk          string `gorm:"default:0"`
+	IsIncrement   string `gorm:"default:0"`
+	IsRequired    string `gorm:"default:0"`
+	IsInsert      string `gorm:"default:0"`
+	IsEdit        string `gorm:"default:0"`
+	IsList        string `gorm:"default:0"`
+	IsQuery       string `gorm:"default:0"`
+	QueryType     string `gorm:"default:EQ"`
+	HtmlType      string
+	DictType      string
+	Sort          int
+	CreateBy      string
+	CreateTime    datetime.Datetime `gorm:"autoCreateTime"`
+	UpdateBy      string
+	UpdateTime    datetime.Datetime `gorm:"autoUpdateTime"`
+}
+
+func (GenTableColumn) TableName() string {
+	return "gen_table_column"
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+type SysJob struct {
+	JobId          int `gorm:"primaryKey;autoIncrement"`
+	JobName        string
+	JobGroup       string `gorm:"default:DEFAULT"`
+	InvokeTarget   string
+	CronExpression string
+	MisfirePolicy  string `gorm:"default:3"`
+	Concurrent     string `gorm:"default:1"`
+	Status         string `gorm:"default:0"`
+	CreateBy       string
+	CreateTime     datetime.Datetime `gorm:"autoCreateTime"`
+	UpdateBy       string
+	UpdateTime     datetime.Datetime `gorm:"autoUpdateTime"`
+	Remark         string
+}
+
+func (SysJob) TableName() string {
+	return "sys_job"
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+type SysJobLog struct {
+	JobLogId      int `gorm:"primaryKey;autoIncrement"`
+	JobId         int
+	JobName       string
+	JobGroup      string
+	InvokeTarget  string
+	JobMessage    string
+	Status        string `gorm:"default:0"`
+	ExceptionInfo string
+	StartTime     datetime.Datetime
+	StopTime      datetime.Datetime
+	CreateTime    datetime.Datetime `gorm:"autoCreateTime"`
+}
+
+func (SysJobLog) TableName() string {
+	return "sys_job_log"
+}
@@ -14,6 +14,7 @@ type SysLoginLog struct {
 	Status        string `gorm:"default:0"`
 	Msg           string
 	LoginTime     datetime.Datetime
+	RequestId     string
 }
 
 func (SysLoginLog) TableName() string {
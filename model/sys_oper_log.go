@@ -21,6 +21,7 @@ type SysOperLog struct {
 	ErrorMsg      string
 	OperTime      datetime.Datetime
 	CostTime      int
+	RequestId     string
 }
 
 func (SysOperLog) TableName() string {
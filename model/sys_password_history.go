@@ -0,0 +1,16 @@
+package model
+
+import (
+	"github.com/hugo8680/goat/common/serializer/datetime"
+)
+
+type SysPasswordHistory struct {
+	HistoryId  int `gorm:"primaryKey;autoIncrement"`
+	UserId     int
+	Password   string
+	CreateTime datetime.Datetime `gorm:"autoCreateTime"`
+}
+
+func (SysPasswordHistory) TableName() string {
+	return "sys_password_history"
+}
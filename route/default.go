@@ -4,7 +4,9 @@ import (
 	"github.com/hugo8680/goat/api/controller/admin"
 	"github.com/hugo8680/goat/common/constant/log_request_type"
 	"github.com/hugo8680/goat/framework"
+	"github.com/hugo8680/goat/framework/ratelimit"
 	"github.com/hugo8680/goat/middleware"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,6 +17,8 @@ import (
 //
 // 组RelativePath为路由前缀(可重复)
 //
+// 组Access为组内路由默认的访问方式，未设置时需要登录
+//
 // 组Middlewares为中间件集合，按顺序传递一个HandlerFunc数组
 //
 // 组Routes为组内管理的路由集合
@@ -23,61 +27,101 @@ import (
 //
 // 路由RelativePath为路由前缀
 //
+// 路由Access为访问方式，framework.AccessAnonymous无需登录，framework.AccessLogin登录即可访问，未设置时沿用组的访问方式
+//
+// 路由Permission为权限表达式，支持&&、||、括号、role(角色key)及通配符，如"system:user:add && system:user:edit"
+//
+// 路由PwdChangeExempt为true时，初始密码须修改或密码已过期的用户仍可访问
+//
 // 路由Middlewares为路由的中间件集合，按顺序传递一个HandlerFunc数组
 //
 // 组的Middlewares和路由的Middlewares会形成一个并集按顺序传递
 //
 // 路由Function为实际处理方法
 //
-// 若希望自行添加新路由文件，将新的路由组按照可变参数的形式传递给RunServer即可，如：RunServer(groups1, groups2, groups3)
+// 若希望自行添加新路由文件，将新的路由组按照可变参数的形式传递给app.Run即可，如：app.Run(groups1, groups2, groups3)
 func DefaultRoutes() []framework.RouteGroup {
 	return []framework.RouteGroup{
 		{
-			Name:         "管理后台（无需授权登录）",
+			Name:         "管理后台",
 			RelativePath: "/",
+			Access:       framework.AccessLogin,
 			Routes: []framework.Route{
 				{
 					Method:       "GET",
 					RelativePath: "/captchaImage",
-					Function:     admin.NewAuthController().GetCaptchaImage,
+					Access:       framework.AccessAnonymous,
+					Middlewares: gin.HandlersChain{middleware.RateLimitMiddleware(middleware.RateLimit{
+						Algorithm: ratelimit.TokenBucket,
+						Limit:     20,
+						Window:    time.Minute,
+					})},
+					Function: admin.NewAuthController().GetCaptchaImage,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/register",
-					Function:     admin.NewAuthController().Register,
+					Access:       framework.AccessAnonymous,
+					Middlewares: gin.HandlersChain{middleware.RateLimitMiddleware(middleware.RateLimit{
+						Algorithm: ratelimit.SlidingWindow,
+						Limit:     5,
+						Window:    time.Hour,
+					})},
+					Function: admin.NewAuthController().Register,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/login",
+					Access:       framework.AccessAnonymous,
 					Middlewares:  gin.HandlersChain{middleware.LoginLogMiddleware()},
 					Function:     admin.NewAuthController().Login,
 				},
+				{
+					Method:       "POST",
+					RelativePath: "/login/twoFactor",
+					Access:       framework.AccessAnonymous,
+					Middlewares: gin.HandlersChain{middleware.RateLimitMiddleware(middleware.RateLimit{
+						Algorithm: ratelimit.SlidingWindow,
+						Limit:     10,
+						Window:    time.Minute,
+					}), middleware.LoginLogMiddleware()},
+					Function: admin.NewAuthController().LoginTwoFactor,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/login/twoFactor/setup",
+					Access:       framework.AccessAnonymous,
+					Function:     admin.NewAuthController().SetupTwoFactor,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/refresh",
+					Access:       framework.AccessAnonymous,
+					Function:     admin.NewAuthController().RefreshToken,
+				},
 				{
 					Method:       "GET",
 					RelativePath: "/logout",
+					Access:       framework.AccessAnonymous,
 					Function:     admin.NewAuthController().Logout,
 				},
-			},
-		},
-		{
-			Name:         "管理后台（需要授权登录）",
-			RelativePath: "/",
-			Middlewares:  gin.HandlersChain{middleware.AdminAuthMiddleware()},
-			Routes: []framework.Route{
 				{
-					Method:       "GET",
-					RelativePath: "/getInfo",
-					Function:     admin.NewAuthController().GetInfo,
+					Method:          "GET",
+					RelativePath:    "/getInfo",
+					PwdChangeExempt: true,
+					Function:        admin.NewAuthController().GetInfo,
 				},
 				{
-					Method:       "GET",
-					RelativePath: "/getRouters",
-					Function:     admin.NewAuthController().GetRouters,
+					Method:          "GET",
+					RelativePath:    "/getRouters",
+					PwdChangeExempt: true,
+					Function:        admin.NewAuthController().GetRouters,
 				},
 				{
-					Method:       "GET",
-					RelativePath: "/system/user/profile",
-					Function:     admin.NewUserController().GetProfile,
+					Method:          "GET",
+					RelativePath:    "/system/user/profile",
+					PwdChangeExempt: true,
+					Function:        admin.NewUserController().GetProfile,
 				},
 				{
 					Method:       "PUT",
@@ -85,253 +129,238 @@ func DefaultRoutes() []framework.RouteGroup {
 					Function:     admin.NewUserController().UpdateProfile,
 				},
 				{
-					Method:       "PUT",
-					RelativePath: "/system/user/profile/updatePwd",
-					Function:     admin.NewUserController().UpdatePassword,
+					Method:          "PUT",
+					RelativePath:    "/system/user/profile/updatePwd",
+					PwdChangeExempt: true,
+					Function:        admin.NewUserController().UpdatePassword,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/user/profile/avatar",
 					Function:     admin.NewUserController().UpdateAvatar,
 				},
+				{
+					Method:       "GET",
+					RelativePath: "/system/user/profile/twoFactor",
+					Function:     admin.NewUserController().GetTwoFactor,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/system/user/profile/twoFactor/setup",
+					Function:     admin.NewUserController().SetupTwoFactor,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/system/user/profile/twoFactor/enable",
+					Function:     admin.NewUserController().EnableTwoFactor,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/system/user/profile/twoFactor/disable",
+					Function:     admin.NewUserController().DisableTwoFactor,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/system/user/profile/twoFactor/recoveryCodes",
+					Function:     admin.NewUserController().RegenerateRecoveryCodes,
+				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/user/deptTree",
-					Middlewares:  gin.HandlersChain{middleware.PermissionCheckMiddleware("system:user:list")},
+					Permission:   "system:user:list",
 					Function:     admin.NewUserController().DeptTree,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/user/list",
-					Middlewares:  gin.HandlersChain{middleware.PermissionCheckMiddleware("system:user:list")},
+					Permission:   "system:user:list",
 					Function:     admin.NewUserController().List,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/user",
-					Middlewares:  gin.HandlersChain{middleware.PermissionCheckMiddleware("system:user:query")},
+					Permission:   "system:user:query",
 					Function:     admin.NewUserController().Get,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/user/:userId",
-					Middlewares:  gin.HandlersChain{middleware.PermissionCheckMiddleware("system:user:query")},
+					Permission:   "system:user:query",
 					Function:     admin.NewUserController().Get,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/user/authRole/:userId",
-					Middlewares:  gin.HandlersChain{middleware.PermissionCheckMiddleware("system:user:query")},
+					Permission:   "system:user:query",
 					Function:     admin.NewUserController().ListRoleByUserId,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/user",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:add"),
-						middleware.OperLogMiddleware("新增用户", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewUserController().Create,
+					Permission:   "system:user:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增用户", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewUserController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/user",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:edit"),
-						middleware.OperLogMiddleware("更新用户", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewUserController().Update,
+					Permission:   "system:user:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新用户", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewUserController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/user/:userIds",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:remove"),
-						middleware.OperLogMiddleware("删除用户", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewUserController().Delete,
+					Permission:   "system:user:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除用户", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewUserController().Delete,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/user/changeStatus",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:edit"),
-						middleware.OperLogMiddleware("修改用户状态", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewUserController().ChangeStatus,
+					Permission:   "system:user:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("修改用户状态", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewUserController().ChangeStatus,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/user/resetPwd",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:edit"),
-						middleware.OperLogMiddleware("修改用户密码", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewUserController().ResetPassword,
+					Permission:   "system:user:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("修改用户密码", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewUserController().ResetPassword,
+				},
+				{
+					Method:       "PUT",
+					RelativePath: "/system/user/resetTwoFactor",
+					Permission:   "system:user:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("重置用户两步验证", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewUserController().ResetTwoFactor,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/user/authRole",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:edit"),
-						middleware.OperLogMiddleware("用户授权角色", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewUserController().AuthRoles,
+					Permission:   "system:user:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("用户授权角色", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewUserController().AuthRoles,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/user/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:export"),
-						middleware.OperLogMiddleware("导出用户", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewUserController().Export,
+					Permission:   "system:user:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出用户", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewUserController().Export,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/user/importData",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:user:import"),
-						middleware.OperLogMiddleware("导入用户", log_request_type.REQUEST_BUSINESS_TYPE_IMPORT),
-					},
-					Function: admin.NewUserController().Import,
+					Permission:   "system:user:import",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导入用户", log_request_type.REQUEST_BUSINESS_TYPE_IMPORT)},
+					Function:     admin.NewUserController().Import,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/user/importTemplate",
-					Middlewares: gin.HandlersChain{
-						middleware.OperLogMiddleware("导入用户模板", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewUserController().DownloadImportTemplate,
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导入用户模板", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewUserController().DownloadImportTemplate,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/role/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:list"),
-					},
-					Function: admin.NewRoleController().List,
+					Permission:   "system:role:list",
+					Function:     admin.NewRoleController().List,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/role/:roleId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:query"),
-					},
-					Function: admin.NewRoleController().Get,
+					Permission:   "system:role:query",
+					Function:     admin.NewRoleController().Get,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/role/deptTree/:roleId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:query"),
-					},
-					Function: admin.NewRoleController().DeptTree,
+					Permission:   "system:role:query",
+					Function:     admin.NewRoleController().DeptTree,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/role/authUser/allocatedList",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:list"),
-					},
-					Function: admin.NewRoleController().RoleUsersAllocated,
+					Permission:   "system:role:list",
+					Function:     admin.NewRoleController().RoleUsersAllocated,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/role/authUser/unallocatedList",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:list"),
-					},
-					Function: admin.NewRoleController().RoleUsersUnAllocated,
+					Permission:   "system:role:list",
+					Function:     admin.NewRoleController().RoleUsersUnAllocated,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/role",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:add"),
-						middleware.OperLogMiddleware("新增角色", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewRoleController().Create,
+					Permission:   "system:role:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增角色", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewRoleController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/role",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:edit"),
-						middleware.OperLogMiddleware("更新角色", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewRoleController().Update,
+					Permission:   "system:role:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新角色", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewRoleController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/role/:roleIds",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:remove"),
-						middleware.OperLogMiddleware("删除角色", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewRoleController().Delete,
+					Permission:   "system:role:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除角色", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewRoleController().Delete,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/role/changeStatus",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:edit"),
-						middleware.OperLogMiddleware("修改角色状态", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewRoleController().ChangeStatus,
+					Permission:   "system:role:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("修改角色状态", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewRoleController().ChangeStatus,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/role/dataScope",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:edit"),
-						middleware.OperLogMiddleware("分配数据权限", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewRoleController().AssignDataScope,
+					Permission:   "system:role:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("分配数据权限", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewRoleController().AssignDataScope,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/role/authUser/selectAll",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:edit"),
-						middleware.OperLogMiddleware("批量选择用户授权", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewRoleController().AuthUsers,
+					Permission:   "system:role:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("批量选择用户授权", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewRoleController().AuthUsers,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/role/authUser/cancel",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:edit"),
-						middleware.OperLogMiddleware("取消用户授权", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewRoleController().UnAuthUser,
+					Permission:   "system:role:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("取消用户授权", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewRoleController().UnAuthUser,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/role/authUser/cancelAll",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:edit"),
-						middleware.OperLogMiddleware("批量取消用户授权", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewRoleController().UnAuthUsers,
+					Permission:   "system:role:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("批量取消用户授权", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewRoleController().UnAuthUsers,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/role/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:role:export"),
-						middleware.OperLogMiddleware("导出角色", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewRoleController().Export,
+					Permission:   "system:role:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出角色", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewRoleController().Export,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/menu/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:menu:list"),
-					},
-					Function: admin.NewMenuController().List,
+					Permission:   "system:menu:list",
+					Function:     admin.NewMenuController().List,
 				},
 				{
 					Method:       "GET",
@@ -346,156 +375,120 @@ func DefaultRoutes() []framework.RouteGroup {
 				{
 					Method:       "GET",
 					RelativePath: "/system/menu/:menuId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:menu:query"),
-					},
-					Function: admin.NewMenuController().Get,
+					Permission:   "system:menu:query",
+					Function:     admin.NewMenuController().Get,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/menu",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:menu:add"),
-						middleware.OperLogMiddleware("新增菜单", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewRoleController().Create,
+					Permission:   "system:menu:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增菜单", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewRoleController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/menu",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:menu:edit"),
-						middleware.OperLogMiddleware("修改菜单", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewMenuController().Update,
+					Permission:   "system:menu:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("修改菜单", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewMenuController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/menu/:menuId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:menu:remove"),
-						middleware.OperLogMiddleware("删除菜单", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewRoleController().Delete,
+					Permission:   "system:menu:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除菜单", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewRoleController().Delete,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/dept/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dept:list"),
-					},
-					Function: admin.NewDeptController().List,
+					Permission:   "system:dept:list",
+					Function:     admin.NewDeptController().List,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/dept/list/exclude/:deptId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dept:list"),
-					},
-					Function: admin.NewDeptController().ListExclude,
+					Permission:   "system:dept:list",
+					Function:     admin.NewDeptController().ListExclude,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/dept/:deptId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dept:query"),
-					},
-					Function: admin.NewDeptController().Get,
+					Permission:   "system:dept:query",
+					Function:     admin.NewDeptController().Get,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/dept",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dept:add"),
-						middleware.OperLogMiddleware("新增部门", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewDeptController().Create,
+					Permission:   "system:dept:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增部门", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewDeptController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/dept",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dept:edit"),
-						middleware.OperLogMiddleware("修改部门", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewDeptController().Update,
+					Permission:   "system:dept:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("修改部门", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewDeptController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/dept/:deptId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dept:remove"),
-						middleware.OperLogMiddleware("删除部门", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewDeptController().Delete,
+					Permission:   "system:dept:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除部门", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewDeptController().Delete,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/post/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:post:list"),
-					},
-					Function: admin.NewPostController().List,
+					Permission:   "system:post:list",
+					Function:     admin.NewPostController().List,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/post/:postId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:post:query"),
-					},
-					Function: admin.NewPostController().Get,
+					Permission:   "system:post:query",
+					Function:     admin.NewPostController().Get,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/post",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:post:add"),
-						middleware.OperLogMiddleware("新增岗位", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewPostController().Create,
+					Permission:   "system:post:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增岗位", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewPostController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/post",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:post:edit"),
-						middleware.OperLogMiddleware("更新岗位", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewPostController().Update,
+					Permission:   "system:post:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新岗位", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewPostController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/post/:postIds",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:post:remove"),
-						middleware.OperLogMiddleware("删除岗位", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewPostController().Delete,
+					Permission:   "system:post:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除岗位", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewPostController().Delete,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/post/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:post:export"),
-						middleware.OperLogMiddleware("导出岗位", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewPostController().Export,
+					Permission:   "system:post:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出岗位", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewPostController().Export,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/dict/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:list"),
-					},
-					Function: admin.NewDictTypeController().List,
+					Permission:   "system:dict:list",
+					Function:     admin.NewDictTypeController().List,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/dict/type/:dictId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:query"),
-					},
-					Function: admin.NewDictTypeController().Get,
+					Permission:   "system:dict:query",
+					Function:     admin.NewDictTypeController().Get,
 				},
 				{
 					Method:       "GET",
@@ -505,63 +498,49 @@ func DefaultRoutes() []framework.RouteGroup {
 				{
 					Method:       "POST",
 					RelativePath: "/system/dict/type",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:add"),
-						middleware.OperLogMiddleware("新增字典类型", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewDictTypeController().Create,
+					Permission:   "system:dict:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增字典类型", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewDictTypeController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/dict/type",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:edit"),
-						middleware.OperLogMiddleware("更新字典类型", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewDictTypeController().Update,
+					Permission:   "system:dict:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新字典类型", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewDictTypeController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/dict/type/:dictIds",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:remove"),
-						middleware.OperLogMiddleware("删除字典类型", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewDictTypeController().Delete,
+					Permission:   "system:dict:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除字典类型", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewDictTypeController().Delete,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/dict/type/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:export"),
-						middleware.OperLogMiddleware("导出字典类型", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewDictTypeController().Export,
+					Permission:   "system:dict:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出字典类型", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewDictTypeController().Export,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/dict/type/refreshCache",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:remove"),
-						middleware.OperLogMiddleware("刷新字典类型缓存", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewDictTypeController().RefreshCache,
+					Permission:   "system:dict:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("刷新字典类型缓存", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewDictTypeController().RefreshCache,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/dict/data/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:list"),
-					},
-					Function: admin.NewDictDataController().List,
+					Permission:   "system:dict:list",
+					Function:     admin.NewDictDataController().List,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/dict/data/:dictCode",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:query"),
-					},
-					Function: admin.NewDictDataController().Get,
+					Permission:   "system:dict:query",
+					Function:     admin.NewDictDataController().Get,
 				},
 				{
 					Method:       "GET",
@@ -571,54 +550,42 @@ func DefaultRoutes() []framework.RouteGroup {
 				{
 					Method:       "POST",
 					RelativePath: "/system/dict/data",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:add"),
-						middleware.OperLogMiddleware("新增字典数据", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewDictDataController().Create,
+					Permission:   "system:dict:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增字典数据", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewDictDataController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/dict/data",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:edit"),
-						middleware.OperLogMiddleware("更新字典数据", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewDictDataController().Update,
+					Permission:   "system:dict:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新字典数据", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewDictDataController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/dict/data/:dictCodes",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:remove"),
-						middleware.OperLogMiddleware("删除字典数据", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewDictDataController().Delete,
+					Permission:   "system:dict:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除字典数据", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewDictDataController().Delete,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/dict/data/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:dict:export"),
-						middleware.OperLogMiddleware("导出字典数据", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewDictDataController().Export,
+					Permission:   "system:dict:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出字典数据", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewDictDataController().Export,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/config/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:config:list"),
-					},
-					Function: admin.NewConfigController().List,
+					Permission:   "system:config:list",
+					Function:     admin.NewConfigController().List,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/config/:configId",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:config:query"),
-					},
-					Function: admin.NewConfigController().Get,
+					Permission:   "system:config:query",
+					Function:     admin.NewConfigController().Get,
 				},
 				{
 					Method:       "GET",
@@ -628,126 +595,336 @@ func DefaultRoutes() []framework.RouteGroup {
 				{
 					Method:       "POST",
 					RelativePath: "/system/config",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:config:add"),
-						middleware.OperLogMiddleware("新增参数配置", log_request_type.REQUEST_BUSINESS_TYPE_INSERT),
-					},
-					Function: admin.NewConfigController().Create,
+					Permission:   "system:config:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增参数配置", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewConfigController().Create,
 				},
 				{
 					Method:       "PUT",
 					RelativePath: "/system/config",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:config:edit"),
-						middleware.OperLogMiddleware("更新参数配置", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE),
-					},
-					Function: admin.NewConfigController().Update,
+					Permission:   "system:config:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新参数配置", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewConfigController().Update,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/config/:configIds",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:config:remove"),
-						middleware.OperLogMiddleware("删除参数配置", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewConfigController().Delete,
+					Permission:   "system:config:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除参数配置", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewConfigController().Delete,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/config/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:config:export"),
-						middleware.OperLogMiddleware("导出参数配置", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewConfigController().Export,
+					Permission:   "system:config:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出参数配置", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewConfigController().Export,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/config/refreshCache",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:config:remove"),
-						middleware.OperLogMiddleware("刷新参数配置缓存", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewConfigController().RefreshCache,
+					Permission:   "system:config:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("刷新参数配置缓存", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewConfigController().RefreshCache,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/loginLog/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:loginLog:list"),
-					},
-					Function: admin.NewLoginLogController().List,
+					Permission:   "system:loginLog:list",
+					Function:     admin.NewLoginLogController().List,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/loginLog/:infoIds",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:loginLog:remove"),
-						middleware.OperLogMiddleware("删除登录日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewLoginLogController().Delete,
+					Permission:   "system:loginLog:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除登录日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewLoginLogController().Delete,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/loginLog/clean",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:loginLog:remove"),
-						middleware.OperLogMiddleware("清空登录日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewLoginLogController().Clean,
+					Permission:   "system:loginLog:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("清空登录日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewLoginLogController().Clean,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/loginLog/unlock/:userName",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:loginLog:unlock"),
-						middleware.OperLogMiddleware("账户解锁", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewLoginLogController().Unlock,
+					Permission:   "system:loginLog:unlock",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("账户解锁", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewLoginLogController().Unlock,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/system/loginLog/unlock/ip/:ipaddr",
+					Permission:   "system:loginLog:unlock",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("IP解锁", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewLoginLogController().UnlockIp,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/loginLog/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:loginLog:export"),
-						middleware.OperLogMiddleware("导出登录日志", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewLoginLogController().Export,
+					Permission:   "system:loginLog:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出登录日志", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewLoginLogController().Export,
 				},
 				{
 					Method:       "GET",
 					RelativePath: "/system/operLog/list",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:operLog:list"),
-					},
-					Function: admin.NewOperLogController().List,
+					Permission:   "system:operLog:list",
+					Function:     admin.NewOperLogController().List,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/operLog/:operIds",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:operLog:remove"),
-						middleware.OperLogMiddleware("删除操作日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewOperLogController().Delete,
+					Permission:   "system:operLog:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除操作日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewOperLogController().Delete,
 				},
 				{
 					Method:       "DELETE",
 					RelativePath: "/system/operLog/clean",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:operLog:remove"),
-						middleware.OperLogMiddleware("清空操作日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE),
-					},
-					Function: admin.NewOperLogController().Clean,
+					Permission:   "system:operLog:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("清空操作日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewOperLogController().Clean,
 				},
 				{
 					Method:       "POST",
 					RelativePath: "/system/operLog/export",
-					Middlewares: gin.HandlersChain{
-						middleware.PermissionCheckMiddleware("system:operLog:export"),
-						middleware.OperLogMiddleware("导出操作日志", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT),
-					},
-					Function: admin.NewOperLogController().Export,
+					Permission:   "system:operLog:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出操作日志", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewOperLogController().Export,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/online/list",
+					Permission:   "monitor:online:list",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("在线用户查询", log_request_type.REQUEST_BUSINESS_TYPE_OTHER)},
+					Function:     admin.NewOnlineController().List,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/monitor/online/:tokenId",
+					Permission:   "monitor:online:forceLogout",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("在线用户强退", log_request_type.REQUEST_BUSINESS_TYPE_FORCE)},
+					Function:     admin.NewOnlineController().ForceLogout,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/job/list",
+					Permission:   "monitor:job:list",
+					Function:     admin.NewJobController().List,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/job/:jobId",
+					Permission:   "monitor:job:query",
+					Function:     admin.NewJobController().Get,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/monitor/job",
+					Permission:   "monitor:job:add",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("新增定时任务", log_request_type.REQUEST_BUSINESS_TYPE_INSERT)},
+					Function:     admin.NewJobController().Create,
+				},
+				{
+					Method:       "PUT",
+					RelativePath: "/monitor/job",
+					Permission:   "monitor:job:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("更新定时任务", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewJobController().Update,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/monitor/job/:jobIds",
+					Permission:   "monitor:job:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除定时任务", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewJobController().Delete,
+				},
+				{
+					Method:       "PUT",
+					RelativePath: "/monitor/job/changeStatus",
+					Permission:   "monitor:job:changeStatus",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("修改定时任务状态", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewJobController().ChangeStatus,
+				},
+				{
+					Method:       "PUT",
+					RelativePath: "/monitor/job/run",
+					Permission:   "monitor:job:changeStatus",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("执行定时任务", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewJobController().Run,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/monitor/job/export",
+					Permission:   "monitor:job:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出定时任务", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewJobController().Export,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/jobLog/list",
+					Permission:   "monitor:job:list",
+					Function:     admin.NewJobLogController().List,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/jobLog/:jobLogId",
+					Permission:   "monitor:job:query",
+					Function:     admin.NewJobLogController().Get,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/monitor/jobLog/:jobLogIds",
+					Permission:   "monitor:job:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除调度日志", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewJobLogController().Delete,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/monitor/jobLog/clean",
+					Permission:   "monitor:job:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("清空调度日志", log_request_type.REQUEST_BUSINESS_TYPE_CLEAN)},
+					Function:     admin.NewJobLogController().Clean,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/monitor/jobLog/export",
+					Permission:   "monitor:job:export",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导出调度日志", log_request_type.REQUEST_BUSINESS_TYPE_EXPORT)},
+					Function:     admin.NewJobLogController().Export,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/server",
+					Permission:   "monitor:server:list",
+					Function:     admin.NewServerController().Get,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/cache",
+					Permission:   "monitor:cache:list",
+					Function:     admin.NewCacheController().Info,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/cache/getNames",
+					Permission:   "monitor:cache:list",
+					Function:     admin.NewCacheController().ListNames,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/cache/getKeys/:cacheName",
+					Permission:   "monitor:cache:list",
+					Function:     admin.NewCacheController().ListKeys,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/monitor/cache/getValue/:cacheName/:cacheKey",
+					Permission:   "monitor:cache:list",
+					Function:     admin.NewCacheController().GetValue,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/monitor/cache/clearCacheName/:cacheName",
+					Permission:   "monitor:cache:list",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("缓存监控", log_request_type.REQUEST_BUSINESS_TYPE_CLEAN)},
+					Function:     admin.NewCacheController().ClearCacheName,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/monitor/cache/clearCacheKey/:cacheKey",
+					Permission:   "monitor:cache:list",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("缓存监控", log_request_type.REQUEST_BUSINESS_TYPE_CLEAN)},
+					Function:     admin.NewCacheController().ClearCacheKey,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/monitor/cache/clearCacheAll",
+					Permission:   "monitor:cache:list",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("缓存监控", log_request_type.REQUEST_BUSINESS_TYPE_CLEAN)},
+					Function:     admin.NewCacheController().ClearCacheAll,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/list",
+					Permission:   "tool:gen:list",
+					Function:     admin.NewGenController().List,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/db/list",
+					Permission:   "tool:gen:list",
+					Function:     admin.NewGenController().DbList,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/:tableId",
+					Permission:   "tool:gen:query",
+					Function:     admin.NewGenController().Get,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/column/:tableId",
+					Permission:   "tool:gen:query",
+					Function:     admin.NewGenController().ColumnList,
+				},
+				{
+					Method:       "POST",
+					RelativePath: "/tool/gen/importTable",
+					Permission:   "tool:gen:import",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("导入代码生成", log_request_type.REQUEST_BUSINESS_TYPE_IMPORT)},
+					Function:     admin.NewGenController().Import,
+				},
+				{
+					Method:       "PUT",
+					RelativePath: "/tool/gen",
+					Permission:   "tool:gen:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("修改代码生成", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewGenController().Update,
+				},
+				{
+					Method:       "DELETE",
+					RelativePath: "/tool/gen/:tableIds",
+					Permission:   "tool:gen:remove",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("删除代码生成", log_request_type.REQUEST_BUSINESS_TYPE_DELETE)},
+					Function:     admin.NewGenController().Delete,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/synchDb/:tableName",
+					Permission:   "tool:gen:edit",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("同步数据库表结构", log_request_type.REQUEST_BUSINESS_TYPE_UPDATE)},
+					Function:     admin.NewGenController().SyncDb,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/preview/:tableId",
+					Permission:   "tool:gen:preview",
+					Function:     admin.NewGenController().Preview,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/download/:tableName",
+					Permission:   "tool:gen:code",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("下载代码", log_request_type.REQUEST_BUSINESS_TYPE_GENCOD)},
+					Function:     admin.NewGenController().Download,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/genCode/:tableName",
+					Permission:   "tool:gen:code",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("生成代码", log_request_type.REQUEST_BUSINESS_TYPE_GENCOD)},
+					Function:     admin.NewGenController().GenCode,
+				},
+				{
+					Method:       "GET",
+					RelativePath: "/tool/gen/batchGenCode",
+					Permission:   "tool:gen:code",
+					Middlewares:  gin.HandlersChain{middleware.OperLogMiddleware("批量下载代码", log_request_type.REQUEST_BUSINESS_TYPE_GENCOD)},
+					Function:     admin.NewGenController().BatchDownload,
 				},
 			},
 		},
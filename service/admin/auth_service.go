@@ -1,14 +1,14 @@
 package admin
 
 import (
+	"context"
 	"errors"
-	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/common/constant/auth"
+	"github.com/hugo8680/goat/common/ip"
 	"github.com/hugo8680/goat/common/password"
 	"github.com/hugo8680/goat/common/serializer/datetime"
-	"github.com/hugo8680/goat/framework/config"
-	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/framework/metrics"
 	"github.com/hugo8680/goat/model/dto"
-	"strconv"
 	"strings"
 	"time"
 
@@ -18,34 +18,38 @@ import (
 type AuthService struct {
 }
 
-func (s *AuthService) GetCaptchaImage() dto.CaptchaResponse {
+// GetCaptchaImage 获取图形验证码，关闭验证码功能时当前ip登录失败次数过多仍须输入
+func (s *AuthService) GetCaptchaImage(ctx *gin.Context) dto.CaptchaResponse {
 	captchaService := NewCaptchaService()
 	id, b64s := captchaService.Generate()
 	b64s = strings.Replace(b64s, "data:image/png;base64,", "", 1)
-	conf := (&ConfigService{}).GetCacheByConfigKey("sys.account.captchaEnabled")
+	conf := (&ConfigService{}).GetCacheByConfigKey(ctx.Request.Context(), "sys.account.captchaEnabled")
 	return dto.CaptchaResponse{
 		Uuid:           id,
 		Img:            b64s,
-		CaptchaEnabled: conf.ConfigValue == "true",
+		CaptchaEnabled: conf.ConfigValue == "true" || (&LoginThrottleService{}).CaptchaRequired(ctx.Request.Context(), ctx.ClientIP(), ""),
 	}
 }
 
-func (s *AuthService) Register(param *dto.RegisterRequest) error {
+func (s *AuthService) Register(ctx context.Context, param *dto.RegisterRequest) error {
 	configService := &ConfigService{}
 	userService := &UserService{}
 	captchaService := NewCaptchaService()
-	if conf := configService.GetCacheByConfigKey("sys.account.registerUser"); conf.ConfigValue != "true" {
+	if conf := configService.GetCacheByConfigKey(ctx, "sys.account.registerUser"); conf.ConfigValue != "true" {
 		return errors.New("当前系统没有开启注册功能")
 	}
-	if conf := configService.GetCacheByConfigKey("sys.account.captchaEnabled"); conf.ConfigValue == "true" {
+	if conf := configService.GetCacheByConfigKey(ctx, "sys.account.captchaEnabled"); conf.ConfigValue == "true" {
 		if !captchaService.Verify(param.Uuid, param.Code) {
 			return errors.New("验证码错误")
 		}
 	}
-	if user := userService.GetByUserName(param.Username); user.UserId > 0 {
+	if user := userService.GetByUserName(ctx, param.Username); user.UserId > 0 {
 		return errors.New("注册账号已存在")
 	}
-	if err := userService.Create(dto.SaveUserRequest{
+	if err := (&PasswordPolicyService{}).Check(ctx, 0, param.Username, param.Password); err != nil {
+		return err
+	}
+	if err := userService.Create(ctx, dto.SaveUserRequest{
 		UserName: param.Username,
 		NickName: param.Username,
 		Password: password.Generate(param.Password),
@@ -58,46 +62,136 @@ func (s *AuthService) Register(param *dto.RegisterRequest) error {
 	return nil
 }
 
-func (s *AuthService) Login(param *dto.LoginRequest, ctx *gin.Context) (string, error) {
+// Login 登录，记录登录成功及失败次数，需要两步验证时在验证后记录
+func (s *AuthService) Login(param *dto.LoginRequest, ctx *gin.Context) (dto.TokenResponse, error) {
+	token, err := s.login(param, ctx)
+	if err != nil || token.TwoFactorTicket == "" {
+		metrics.ObserveLogin(err == nil)
+	}
+	return token, err
+}
+
+func (s *AuthService) login(param *dto.LoginRequest, ctx *gin.Context) (dto.TokenResponse, error) {
 	configService := &ConfigService{}
 	userService := &UserService{}
-	tokenService := NewTokenService()
 	captchaService := NewCaptchaService()
-	setting := config.GetSetting()
-	cache := connector.GetCache()
-	if conf := configService.GetCacheByConfigKey("sys.account.captchaEnabled"); conf.ConfigValue == "true" {
+	throttleService := &LoginThrottleService{}
+	clientIp := ctx.ClientIP()
+	// ip或账号登录失败次数过多时锁定，锁定时间随锁定次数翻倍
+	if err := throttleService.Check(ctx.Request.Context(), clientIp, param.Username); err != nil {
+		return dto.TokenResponse{}, err
+	}
+	captchaEnabled := configService.GetCacheByConfigKey(ctx.Request.Context(), "sys.account.captchaEnabled").ConfigValue == "true"
+	if captchaEnabled || throttleService.CaptchaRequired(ctx.Request.Context(), clientIp, param.Username) {
+		if param.Uuid == "" || param.Code == "" {
+			return dto.TokenResponse{}, errors.New("登录失败次数过多，请输入验证码")
+		}
 		if !captchaService.Verify(param.Uuid, param.Code) {
-			return "", errors.New("验证码错误")
+			return dto.TokenResponse{}, errors.New("验证码错误")
 		}
 	}
-	user := userService.GetByUserName(param.Username)
+	user := userService.GetByUserName(ctx.Request.Context(), param.Username)
 	if user.UserId <= 0 || user.Status != "0" {
-		return "", errors.New("用户不存在或被禁用")
-	}
-	// 登陆密码错误次数超过限制，锁定账号10分钟
-	count, _ := cache.Get(ctx.Request.Context(), redis_key.LoginPasswordErrorKey+param.Username).Int()
-	if count >= setting.Auth.Password.MaxRetryCount {
-		return "", errors.New("密码错误次数超过限制，请" + strconv.Itoa(setting.Auth.Password.LockTime) + "分钟后重试")
+		// 不存在的账号同样计入失败次数，避免通过锁定行为探测账号
+		return dto.TokenResponse{}, s.loginFailed(ctx, throttleService, param.Username, "用户不存在或被禁用")
 	}
 	if !password.Verify(user.Password, param.Password) {
-		// 密码错误次数加1，并设置缓存过期时间为锁定时间
-		cache.Set(ctx.Request.Context(), redis_key.LoginPasswordErrorKey+param.Username, count+1, time.Minute*time.Duration(setting.Auth.Password.LockTime))
-		return "", errors.New("密码错误")
+		return dto.TokenResponse{}, s.loginFailed(ctx, throttleService, param.Username, "密码错误")
 	}
-	// 登录成功，删除错误次数
-	cache.Del(ctx.Request.Context(), redis_key.LoginPasswordErrorKey+param.Username)
-	token, err := tokenService.Create(&user)
+	// 登录成功，清除失败记录
+	throttleService.Succeed(ctx.Request.Context(), clientIp, param.Username)
+	// 记录登录终端信息，用于在线用户监控
+	ipAddr := ip.GetAddress(ctx.ClientIP(), ctx.Request.UserAgent())
+	user.Ipaddr = ipAddr.Ip
+	user.LoginLocation = ipAddr.Addr
+	user.Browser = ipAddr.Browser
+	user.Os = ipAddr.Os
+	user.LoginTime = datetime.Datetime{Time: time.Now()}
+	user.ClientType = param.ClientType
+	if user.ClientType == "" {
+		user.ClientType = auth.CLIENT_TYPE_WEB
+	}
+	// 已启用两步验证或角色要求两步验证时，返回登录凭证，校验验证码后再签发令牌
+	twoFactorService := &TwoFactorService{}
+	if enabled := twoFactorService.IsEnabled(ctx.Request.Context(), user.UserId); enabled || twoFactorService.IsForced(ctx.Request.Context(), user.UserId) {
+		ticket, err := twoFactorService.CreateTicket(ctx.Request.Context(), user, !enabled)
+		if err != nil {
+			return dto.TokenResponse{}, err
+		}
+		ctx.Set(auth.CONTEXT_LOGIN_PENDING_KEY, true)
+		return dto.TokenResponse{TwoFactorTicket: ticket, TwoFactorSetup: !enabled}, nil
+	}
+	return s.signIn(ctx, &user)
+}
+
+// loginFailed 记录登录失败，触发锁定时在提示中附带锁定信息，写入登录日志
+func (s *AuthService) loginFailed(ctx *gin.Context, throttleService *LoginThrottleService, userName, msg string) error {
+	if lockMsg := throttleService.Fail(ctx.Request.Context(), ctx.ClientIP(), userName); lockMsg != "" {
+		return errors.New(msg + "，" + lockMsg)
+	}
+	return errors.New(msg)
+}
+
+// LoginTwoFactor 两步验证登录，使用登录凭证及验证码或恢复码换取令牌
+func (s *AuthService) LoginTwoFactor(param *dto.TwoFactorLoginRequest, ctx *gin.Context) (dto.TokenResponse, error) {
+	token, err := s.loginTwoFactor(param, ctx)
+	metrics.ObserveLogin(err == nil)
+	return token, err
+}
+
+// loginTwoFactor 验证码错误同样计入登录失败次数，避免通过重新获取凭证绕过凭证的重试次数限制
+func (s *AuthService) loginTwoFactor(param *dto.TwoFactorLoginRequest, ctx *gin.Context) (dto.TokenResponse, error) {
+	twoFactorService := &TwoFactorService{}
+	throttleService := &LoginThrottleService{}
+	clientIp := ctx.ClientIP()
+	data, err := twoFactorService.getTicket(ctx.Request.Context(), param.Ticket)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+	userName := data.User.UserName
+	ctx.Set(auth.CONTEXT_LOGIN_USER_NAME_KEY, userName)
+	if err = throttleService.Check(ctx.Request.Context(), clientIp, userName); err != nil {
+		return dto.TokenResponse{}, err
+	}
+	user, recoveryCodes, err := twoFactorService.VerifyTicket(ctx.Request.Context(), param.Ticket, param.Code, param.RecoveryCode)
+	if err != nil {
+		return dto.TokenResponse{}, s.loginFailed(ctx, throttleService, userName, err.Error())
+	}
+	throttleService.Succeed(ctx.Request.Context(), clientIp, userName)
+	token, err := s.signIn(ctx, &user)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+	token.RecoveryCodes = recoveryCodes
+	return token, nil
+}
+
+// SetupTwoFactor 角色要求两步验证但尚未绑定时，使用登录凭证获取绑定信息
+func (s *AuthService) SetupTwoFactor(param *dto.TwoFactorTicketRequest, ctx *gin.Context) (dto.TwoFactorSetupResponse, error) {
+	return (&TwoFactorService{}).SetupByTicket(ctx.Request.Context(), param.Ticket)
+}
+
+// signIn 签发令牌，加载权限并更新登录信息
+//
+// 初始密码须修改或密码已过期时，会话仅能访问修改密码等接口
+func (s *AuthService) signIn(ctx *gin.Context, user *dto.UserTokenResponse) (dto.TokenResponse, error) {
+	pwdChange, pwdChangeMsg := (&PasswordPolicyService{}).ChangeRequired(ctx.Request.Context(), user.UserId)
+	user.PwdChange = pwdChange
+	token, err := NewTokenService().Create(user)
 	if err != nil {
-		return "", err
+		return dto.TokenResponse{}, err
 	}
+	token.PwdChangeMsg = pwdChangeMsg
+	// 加载权限及角色到缓存，后续鉴权不再查询数据库
+	(&PermissionService{}).Load(ctx.Request.Context(), user.UserId)
 	// 更新登录的ip和时间
-	err = userService.Update(dto.SaveUserRequest{
+	err = (&UserService{}).Update(ctx.Request.Context(), dto.SaveUserRequest{
 		UserId:    user.UserId,
 		LoginIP:   ctx.ClientIP(),
 		LoginDate: datetime.Datetime{Time: time.Now()},
 	}, nil, nil)
 	if err != nil {
-		return "", err
+		return dto.TokenResponse{}, err
 	}
 	return token, nil
 }
@@ -109,21 +203,23 @@ func (s *AuthService) GetAuthInfo(ctx *gin.Context) dto.AuthInfoResponse {
 	roleService := &RoleService{}
 	menuService := &MenuService{}
 	userId, _ := securityService.GetCurrentUserId(ctx)
-	user := userService.Get(userId)
-	user.Admin = user.UserId == 1
-	dept := deptService.Get(user.DeptId)
-	roles := roleService.ListByUserId(user.UserId)
+	user := userService.Get(ctx.Request.Context(), userId)
+	user.Admin = securityService.IsSuperAdmin(ctx.Request.Context(), user.UserId)
+	dept := deptService.Get(ctx.Request.Context(), user.DeptId)
+	roles := roleService.ListByUserId(ctx.Request.Context(), user.UserId)
 	data := dto.AuthUserInfoResponse{
 		UserDetailResponse: user,
 		Dept:               dept,
 		Roles:              roles,
 	}
-	roleKeys := roleService.ListKeyByUserId(user.UserId)
-	perms := menuService.ListPermsByUserId(user.UserId)
+	roleKeys := roleService.ListKeyByUserId(ctx.Request.Context(), user.UserId)
+	perms := menuService.ListPermsByUserId(ctx.Request.Context(), user.UserId)
+	_, pwdChangeMsg := (&PasswordPolicyService{}).ChangeRequired(ctx.Request.Context(), user.UserId)
 	return dto.AuthInfoResponse{
-		User:        data,
-		Roles:       roleKeys,
-		Permissions: perms,
+		User:         data,
+		Roles:        roleKeys,
+		Permissions:  perms,
+		PwdChangeMsg: pwdChangeMsg,
 	}
 }
 
@@ -131,11 +227,16 @@ func (s *AuthService) GetRouters(ctx *gin.Context) []dto.MenuMetaTreeResponse {
 	securityService := &SecurityService{}
 	menuService := &MenuService{}
 	userId, _ := securityService.GetCurrentUserId(ctx)
-	menus := menuService.GetMCListByUserId(userId)
+	menus := menuService.GetMCListByUserId(ctx.Request.Context(), userId)
 	tree := menuService.MCListToTree(menus, 0)
 	return menuService.BuildRouterMenus(tree)
 }
 
+// RefreshToken 使用刷新令牌换取新的令牌
+func (s *AuthService) RefreshToken(param *dto.RefreshTokenRequest, ctx *gin.Context) (dto.TokenResponse, error) {
+	return NewTokenService().Rotate(ctx.Request.Context(), param.RefreshToken)
+}
+
 func (s *AuthService) Logout(ctx *gin.Context) error {
 	tokenService := NewTokenService()
 	return tokenService.Delete(ctx)
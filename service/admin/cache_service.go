@@ -0,0 +1,201 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/model/dto"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CacheService struct {
+}
+
+// cacheNames 可在缓存监控中浏览和清理的缓存，缓存名称即redis键前缀
+func (s *CacheService) cacheNames() []dto.CacheNameResponse {
+	return []dto.CacheNameResponse{
+		{CacheName: redis_key.UserTokenKey, Remark: "用户信息"},
+		{CacheName: redis_key.UserRefreshTokenKey, Remark: "刷新令牌"},
+		{CacheName: redis_key.UserSessionKey, Remark: "用户会话"},
+		{CacheName: redis_key.SysConfigKey, Remark: "配置信息"},
+		{CacheName: redis_key.SysDictKey, Remark: "数据字典"},
+		{CacheName: redis_key.CaptchaCodeKey, Remark: "验证码"},
+		{CacheName: redis_key.RepeatSubmitKey, Remark: "防重提交"},
+		{CacheName: redis_key.LoginFailKey, Remark: "登录失败记录"},
+		{CacheName: redis_key.LoginLockKey, Remark: "登录锁定"},
+		{CacheName: redis_key.RateLimitKey, Remark: "接口限流"},
+	}
+}
+
+// Info 获取redis服务信息、键数量及命令统计
+func (s *CacheService) Info(ctx *gin.Context) (dto.CacheInfoResponse, error) {
+	cache := connector.GetCache()
+	info, err := cache.Info(ctx.Request.Context()).Result()
+	if err != nil {
+		return dto.CacheInfoResponse{}, err
+	}
+	dbSize, err := cache.DBSize(ctx.Request.Context()).Result()
+	if err != nil {
+		return dto.CacheInfoResponse{}, err
+	}
+	commandStats := make([]dto.CacheCommandStatResponse, 0)
+	if stats, err := cache.Info(ctx.Request.Context(), "commandstats").Result(); err == nil {
+		// 格式如 cmdstat_get:calls=21,usec=175,usec_per_call=8.33
+		for key, value := range s.parseInfo(stats) {
+			calls := strings.TrimPrefix(strings.Split(value, ",")[0], "calls=")
+			commandStats = append(commandStats, dto.CacheCommandStatResponse{
+				Name:  strings.TrimPrefix(key, "cmdstat_"),
+				Value: calls,
+			})
+		}
+		sort.Slice(commandStats, func(i, j int) bool {
+			return commandStats[i].Name < commandStats[j].Name
+		})
+	}
+	return dto.CacheInfoResponse{
+		Info:         s.parseInfo(info),
+		DbSize:       dbSize,
+		CommandStats: commandStats,
+	}, nil
+}
+
+// ListNames 缓存名称列表
+func (s *CacheService) ListNames() []dto.CacheNameResponse {
+	return s.cacheNames()
+}
+
+// ListKeys 获取缓存名称下的键名列表
+func (s *CacheService) ListKeys(ctx *gin.Context, cacheName string) ([]string, error) {
+	if !s.isCacheName(cacheName) {
+		return nil, errors.New("缓存名称不存在")
+	}
+	cache := connector.GetCache()
+	keys := make([]string, 0)
+	iter := cache.Scan(ctx.Request.Context(), 0, cacheName+"*", 100).Iterator()
+	for iter.Next(ctx.Request.Context()) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GetValue 获取缓存内容，非字符串类型的值序列化为json
+func (s *CacheService) GetValue(ctx *gin.Context, cacheName, cacheKey string) (dto.CacheValueResponse, error) {
+	if !s.isCacheKey(cacheKey) {
+		return dto.CacheValueResponse{}, errors.New("缓存键名不存在")
+	}
+	cache := connector.GetCache()
+	cacheType, err := cache.Type(ctx.Request.Context(), cacheKey).Result()
+	if err != nil {
+		return dto.CacheValueResponse{}, err
+	}
+	var value interface{}
+	switch cacheType {
+	case "string":
+		value, err = cache.Get(ctx.Request.Context(), cacheKey).Result()
+	case "hash":
+		value, err = cache.HGetAll(ctx.Request.Context(), cacheKey).Result()
+	case "list":
+		value, err = cache.LRange(ctx.Request.Context(), cacheKey, 0, -1).Result()
+	case "set":
+		value, err = cache.SMembers(ctx.Request.Context(), cacheKey).Result()
+	case "zset":
+		value, err = cache.ZRangeWithScores(ctx.Request.Context(), cacheKey, 0, -1).Result()
+	case "none":
+		return dto.CacheValueResponse{}, errors.New("缓存已过期或不存在")
+	default:
+		return dto.CacheValueResponse{}, errors.New("不支持的缓存类型" + cacheType)
+	}
+	if err != nil {
+		return dto.CacheValueResponse{}, err
+	}
+	cacheValue, ok := value.(string)
+	if !ok {
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return dto.CacheValueResponse{}, err
+		}
+		cacheValue = string(valueBytes)
+	}
+	ttl, _ := cache.TTL(ctx.Request.Context(), cacheKey).Result()
+	return dto.CacheValueResponse{
+		CacheName:  cacheName,
+		CacheKey:   cacheKey,
+		CacheType:  cacheType,
+		CacheValue: cacheValue,
+		Ttl:        int64(ttl.Seconds()),
+	}, nil
+}
+
+// ClearCacheName 清理缓存名称下的所有键
+func (s *CacheService) ClearCacheName(ctx *gin.Context, cacheName string) error {
+	keys, err := s.ListKeys(ctx, cacheName)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return connector.GetCache().Del(ctx.Request.Context(), keys...).Err()
+}
+
+// ClearCacheKey 清理指定键名的缓存
+func (s *CacheService) ClearCacheKey(ctx *gin.Context, cacheKey string) error {
+	if !s.isCacheKey(cacheKey) {
+		return errors.New("缓存键名不存在")
+	}
+	return connector.GetCache().Del(ctx.Request.Context(), cacheKey).Err()
+}
+
+// ClearCacheAll 清理全部缓存
+//
+// 只清理缓存名称列表中的键，不使用FLUSHDB，避免误删共用redis的其他应用数据
+func (s *CacheService) ClearCacheAll(ctx *gin.Context) error {
+	for _, cacheName := range s.cacheNames() {
+		if err := s.ClearCacheName(ctx, cacheName.CacheName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isCacheName 是否为缓存名称列表中的名称
+func (s *CacheService) isCacheName(cacheName string) bool {
+	for _, item := range s.cacheNames() {
+		if item.CacheName == cacheName {
+			return true
+		}
+	}
+	return false
+}
+
+// isCacheKey 键名是否属于缓存名称列表中的某个前缀
+func (s *CacheService) isCacheKey(cacheKey string) bool {
+	for _, item := range s.cacheNames() {
+		if strings.HasPrefix(cacheKey, item.CacheName) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInfo 解析INFO命令返回的文本
+func (s *CacheService) parseInfo(info string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(info, "\r\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			result[key] = value
+		}
+	}
+	return result
+}
@@ -16,11 +16,11 @@ type ConfigService struct {
 }
 
 // Create 创建参数
-func (s *ConfigService) Create(param dto.SaveConfigRequest) error {
-	if config := s.GetCacheByConfigKey(param.ConfigKey); config.ConfigId > 0 {
+func (s *ConfigService) Create(ctx context.Context, param dto.SaveConfigRequest) error {
+	if config := s.GetCacheByConfigKey(ctx, param.ConfigKey); config.ConfigId > 0 {
 		return errors.New("新增参数" + param.ConfigName + "失败，参数键名已存在")
 	}
-	return connector.GetDB().Model(model.SysConfig{}).Create(&model.SysConfig{
+	return connector.DB(ctx).Model(model.SysConfig{}).Create(&model.SysConfig{
 		ConfigName:  param.ConfigName,
 		ConfigKey:   param.ConfigKey,
 		ConfigValue: param.ConfigValue,
@@ -31,11 +31,11 @@ func (s *ConfigService) Create(param dto.SaveConfigRequest) error {
 }
 
 // Update 更新参数
-func (s *ConfigService) Update(param dto.SaveConfigRequest) error {
-	if config := s.GetCacheByConfigKey(param.ConfigKey); config.ConfigId > 0 && config.ConfigId != param.ConfigId {
+func (s *ConfigService) Update(ctx context.Context, param dto.SaveConfigRequest) error {
+	if config := s.GetCacheByConfigKey(ctx, param.ConfigKey); config.ConfigId > 0 && config.ConfigId != param.ConfigId {
 		return errors.New("修改参数" + param.ConfigName + "失败，参数键名已存在")
 	}
-	return connector.GetDB().Model(model.SysConfig{}).Where("config_id = ?", param.ConfigId).Updates(&model.SysConfig{
+	return connector.DB(ctx).Model(model.SysConfig{}).Where("config_id = ?", param.ConfigId).Updates(&model.SysConfig{
 		ConfigName:  param.ConfigName,
 		ConfigKey:   param.ConfigKey,
 		ConfigValue: param.ConfigValue,
@@ -46,15 +46,15 @@ func (s *ConfigService) Update(param dto.SaveConfigRequest) error {
 }
 
 // Delete 删除参数
-func (s *ConfigService) Delete(configIds []int) error {
-	return connector.GetDB().Model(model.SysConfig{}).Where("config_id IN ?", configIds).Delete(&model.SysConfig{}).Error
+func (s *ConfigService) Delete(ctx context.Context, configIds []int) error {
+	return connector.DB(ctx).Model(model.SysConfig{}).Where("config_id IN ?", configIds).Delete(&model.SysConfig{}).Error
 }
 
 // List 获取参数列表
-func (s *ConfigService) List(param dto.ConfigListRequest, isPaging bool) ([]dto.ConfigListResponse, int) {
+func (s *ConfigService) List(ctx context.Context, param dto.ConfigListRequest, isPaging bool) ([]dto.ConfigListResponse, int) {
 	var count int64
 	configs := make([]dto.ConfigListResponse, 0)
-	query := connector.GetDB().Model(model.SysConfig{}).Order("config_id")
+	query := connector.DB(ctx).Model(model.SysConfig{}).Order("config_id")
 	if param.ConfigName != "" {
 		query = query.Where("config_name LIKE ?", "%"+param.ConfigName+"%")
 	}
@@ -75,34 +75,34 @@ func (s *ConfigService) List(param dto.ConfigListRequest, isPaging bool) ([]dto.
 }
 
 // Get 获取参数详情
-func (s *ConfigService) Get(configId int) dto.ConfigDetailResponse {
+func (s *ConfigService) Get(ctx context.Context, configId int) dto.ConfigDetailResponse {
 	var config dto.ConfigDetailResponse
-	connector.GetDB().Model(model.SysConfig{}).Where("config_id = ?", configId).Last(&config)
+	connector.DB(ctx).Model(model.SysConfig{}).Where("config_id = ?", configId).Last(&config)
 	return config
 }
 
 // GetByConfigKey 根据参数key获取参数值
-func (s *ConfigService) GetByConfigKey(configKey string) dto.ConfigDetailResponse {
+func (s *ConfigService) GetByConfigKey(ctx context.Context, configKey string) dto.ConfigDetailResponse {
 	var config dto.ConfigDetailResponse
-	connector.GetDB().Model(model.SysConfig{}).Where("config_key = ?", configKey).Last(&config)
+	connector.DB(ctx).Model(model.SysConfig{}).Where("config_key = ?", configKey).Last(&config)
 	return config
 }
 
 // GetCacheByConfigKey 根据参数key获取参数配置
-func (s *ConfigService) GetCacheByConfigKey(configKey string) dto.ConfigDetailResponse {
+func (s *ConfigService) GetCacheByConfigKey(ctx context.Context, configKey string) dto.ConfigDetailResponse {
 	cache := connector.GetCache()
 	var config dto.ConfigDetailResponse
 	// 缓存不为空不从数据库读取，减少数据库压力
-	if configCache, _ := cache.HGet(context.Background(), redis_key.SysConfigKey, configKey).Result(); configCache != "" {
+	if configCache, _ := cache.HGet(ctx, redis_key.SysConfigKey, configKey).Result(); configCache != "" {
 		if err := json.Unmarshal([]byte(configCache), &config); err == nil {
 			return config
 		}
 	}
 	// 从数据库读取配置并且记录到缓存
-	config = s.GetByConfigKey(configKey)
+	config = s.GetByConfigKey(ctx, configKey)
 	if config.ConfigId > 0 {
 		configBytes, _ := json.Marshal(&config)
-		cache.HSet(context.Background(), redis_key.SysConfigKey, configKey, string(configBytes)).Result()
+		cache.HSet(ctx, redis_key.SysConfigKey, configKey, string(configBytes)).Result()
 	}
 	return config
 }
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"strings"
 
 	"gorm.io/gorm"
@@ -19,9 +20,9 @@ type DataScopeService struct {
 // 例如：db.Model(model.User{}).Scopes(GetDataScope(deptAlias, userId, userAlias)...).Find(&[]model.User{})
 //
 // 数据范围：1-全部数据权限；2-自定数据权限；3-本部门数据权限；4-本部门及以下数据权限；5-仅本人数据权限
-func (s *DataScopeService) GetDataScope(deptAlias string, userId int, userAlias string) func(*gorm.DB) *gorm.DB {
+func (s *DataScopeService) GetDataScope(ctx context.Context, deptAlias string, userId int, userAlias string) func(*gorm.DB) *gorm.DB {
 	// 超级管理员不进行数据权限过滤
-	if userId == 1 {
+	if (&UserService{}).IsSuperAdmin(ctx, userId) {
 		return func(db *gorm.DB) *gorm.DB {
 			return db
 		}
@@ -30,10 +31,10 @@ func (s *DataScopeService) GetDataScope(deptAlias string, userId int, userAlias
 		deptAlias = "sys_dept"
 	}
 	// 获取用户信息
-	user := (&UserService{}).Get(userId)
+	user := (&UserService{}).Get(ctx, userId)
 	var roleIds []int
 	// 获取当前用户的角色
-	roles := (&RoleService{}).ListByUserId(user.UserId)
+	roles := (&RoleService{}).ListByUserId(ctx, user.UserId)
 	for _, role := range roles {
 		if role.DataScope == "2" && role.Status == "0" {
 			roleIds = append(roleIds, role.RoleId)
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"errors"
 	"github.com/hugo8680/goat/framework/connector"
 	"github.com/hugo8680/goat/model"
@@ -12,17 +13,17 @@ type DeptService struct {
 }
 
 // Create 创建部门
-func (s *DeptService) Create(param dto.SaveDeptRequest) error {
-	if dept := s.GetByDeptName(param.DeptName); dept.DeptId > 0 {
+func (s *DeptService) Create(ctx context.Context, param dto.SaveDeptRequest) error {
+	if dept := s.GetByDeptName(ctx, param.DeptName); dept.DeptId > 0 {
 		return errors.New("新增部门" + param.DeptName + "失败，部门名称已存在")
 	}
 	// 拼接ancestors，获取上级的祖级列表
-	parentDept := s.Get(param.ParentId)
+	parentDept := s.Get(ctx, param.ParentId)
 	if parentDept.Status == "1" {
 		return errors.New("部门停用，不允许新增")
 	}
 	ancestors := parentDept.Ancestors + "," + strconv.Itoa(parentDept.DeptId)
-	return connector.GetDB().Model(model.SysDept{}).Create(&model.SysDept{
+	return connector.DB(ctx).Model(model.SysDept{}).Create(&model.SysDept{
 		ParentId:  param.ParentId,
 		Ancestors: ancestors,
 		DeptName:  param.DeptName,
@@ -36,20 +37,20 @@ func (s *DeptService) Create(param dto.SaveDeptRequest) error {
 }
 
 // Update 更新部门
-func (s *DeptService) Update(param dto.SaveDeptRequest) error {
-	if dept := s.GetByDeptName(param.DeptName); dept.DeptId > 0 && dept.DeptId != param.DeptId {
+func (s *DeptService) Update(ctx context.Context, param dto.SaveDeptRequest) error {
+	if dept := s.GetByDeptName(ctx, param.DeptName); dept.DeptId > 0 && dept.DeptId != param.DeptId {
 		return errors.New("修改部门" + param.DeptName + "失败，部门名称已存在")
 	}
-	if dept := s.Get(param.DeptId); dept.ParentId != param.ParentId && s.HasChildren(param.DeptId) {
+	if dept := s.Get(ctx, param.DeptId); dept.ParentId != param.ParentId && s.HasChildren(ctx, param.DeptId) {
 		return errors.New("存在子级部门，无法直接修改所属部门")
 	}
 	// 拼接ancestors，获取上级的祖级列表
-	parentDept := s.Get(param.ParentId)
+	parentDept := s.Get(ctx, param.ParentId)
 	if parentDept.Status == "1" {
 		return errors.New("部门停用，不允许新增")
 	}
 	ancestors := parentDept.Ancestors + "," + strconv.Itoa(parentDept.DeptId)
-	return connector.GetDB().Model(model.SysDept{}).Where("dept_id = ?", param.DeptId).Updates(&model.SysDept{
+	return connector.DB(ctx).Model(model.SysDept{}).Where("dept_id = ?", param.DeptId).Updates(&model.SysDept{
 		ParentId:  param.ParentId,
 		Ancestors: ancestors,
 		DeptName:  param.DeptName,
@@ -63,20 +64,20 @@ func (s *DeptService) Update(param dto.SaveDeptRequest) error {
 }
 
 // Delete 删除部门
-func (s *DeptService) Delete(deptId int) error {
-	if s.HasChildren(deptId) {
+func (s *DeptService) Delete(ctx context.Context, deptId int) error {
+	if s.HasChildren(ctx, deptId) {
 		return errors.New("存在下级部门，不允许删除")
 	}
-	if (&UserService{}).HasUser(deptId) {
+	if (&UserService{}).HasUser(ctx, deptId) {
 		return errors.New("部门存在用户，不允许删除")
 	}
-	return connector.GetDB().Model(model.SysDept{}).Where("dept_id = ?", deptId).Delete(&model.SysDept{}).Error
+	return connector.DB(ctx).Model(model.SysDept{}).Where("dept_id = ?", deptId).Delete(&model.SysDept{}).Error
 }
 
 // List 获取部门列表
-func (s *DeptService) List(param dto.DeptListRequest, userId int) []dto.DeptListResponse {
+func (s *DeptService) List(ctx context.Context, param dto.DeptListRequest, userId int) []dto.DeptListResponse {
 	deptList := make([]dto.DeptListResponse, 0)
-	query := connector.GetDB().Model(model.SysDept{}).Order("order_num, dept_id").Scopes((&DataScopeService{}).GetDataScope("sys_dept", userId, ""))
+	query := connector.DB(ctx).Model(model.SysDept{}).Order("order_num, dept_id").Scopes((&DataScopeService{}).GetDataScope(ctx, "sys_dept", userId, ""))
 	if param.DeptName != "" {
 		query.Where("dept_name LIKE ?", "%"+param.DeptName+"%")
 	}
@@ -88,23 +89,23 @@ func (s *DeptService) List(param dto.DeptListRequest, userId int) []dto.DeptList
 }
 
 // Get 根据部门id查询部门信息
-func (s *DeptService) Get(deptId int) dto.DeptDetailResponse {
+func (s *DeptService) Get(ctx context.Context, deptId int) dto.DeptDetailResponse {
 	var dept dto.DeptDetailResponse
-	connector.GetDB().Model(model.SysDept{}).Where("dept_id = ?", deptId).Last(&dept)
+	connector.DB(ctx).Model(model.SysDept{}).Where("dept_id = ?", deptId).Last(&dept)
 	return dept
 }
 
 // GetByDeptName 根据部门名称查询部门信息
-func (s *DeptService) GetByDeptName(deptName string) dto.DeptDetailResponse {
+func (s *DeptService) GetByDeptName(ctx context.Context, deptName string) dto.DeptDetailResponse {
 	var dept dto.DeptDetailResponse
-	connector.GetDB().Model(model.SysDept{}).Where("dept_name = ?", deptName).Last(&dept)
+	connector.DB(ctx).Model(model.SysDept{}).Where("dept_name = ?", deptName).Last(&dept)
 	return dept
 }
 
 // ListIdsByRoleId 根据角色id获取部门id集合
-func (s *DeptService) ListIdsByRoleId(roleId int) []int {
+func (s *DeptService) ListIdsByRoleId(ctx context.Context, roleId int) []int {
 	deptIds := make([]int, 0)
-	connector.GetDB().Model(model.SysRoleDept{}).
+	connector.DB(ctx).Model(model.SysRoleDept{}).
 		Joins("JOIN sys_dept ON sys_dept.dept_id = sys_role_dept.dept_id").
 		Where("sys_dept.status = 0 AND sys_role_dept.role_id = ?", roleId).
 		Pluck("sys_dept.dept_id", &deptIds)
@@ -112,9 +113,9 @@ func (s *DeptService) ListIdsByRoleId(roleId int) []int {
 }
 
 // Tree 部门下拉树列表
-func (s *DeptService) Tree() []dto.TreeResponse {
+func (s *DeptService) Tree(ctx context.Context) []dto.TreeResponse {
 	deptList := make([]dto.TreeResponse, 0)
-	connector.GetDB().Model(model.SysDept{}).Order("order_num, dept_id").
+	connector.DB(ctx).Model(model.SysDept{}).Order("order_num, dept_id").
 		Select("dept_id as id", "dept_name as label", "parent_id").
 		Where("status = 0").
 		Find(&deptList)
@@ -138,9 +139,9 @@ func (s *DeptService) RemakeTree(depts []dto.TreeResponse, parentId int) []dto.T
 }
 
 // TreeByUserId 获取用户所属的部门树
-func (s *DeptService) TreeByUserId(userId int) []dto.DeptTreeResponse {
+func (s *DeptService) TreeByUserId(ctx context.Context, userId int) []dto.DeptTreeResponse {
 	depts := make([]dto.DeptTreeResponse, 0)
-	connector.GetDB().Model(model.SysDept{}).
+	connector.DB(ctx).Model(model.SysDept{}).
 		Select(
 			"dept_id as id",
 			"dept_name as label",
@@ -148,7 +149,7 @@ func (s *DeptService) TreeByUserId(userId int) []dto.DeptTreeResponse {
 		).
 		Order("order_num, dept_id").
 		Where("status = 0").
-		Scopes((&DataScopeService{}).GetDataScope("sys_dept", userId, "")).
+		Scopes((&DataScopeService{}).GetDataScope(ctx, "sys_dept", userId, "")).
 		Find(&depts)
 	return depts
 }
@@ -167,15 +168,15 @@ func (s *UserService) RemakeTreeByUserId(depts []dto.DeptTreeResponse, parentId
 }
 
 // HasChildren 查询部门是否存在下级
-func (s *DeptService) HasChildren(deptId int) bool {
+func (s *DeptService) HasChildren(ctx context.Context, deptId int) bool {
 	var count int64
-	connector.GetDB().Model(model.SysDept{}).Where("parent_id = ?", deptId).Count(&count)
+	connector.DB(ctx).Model(model.SysDept{}).Where("parent_id = ?", deptId).Count(&count)
 	return count > 0
 }
 
 // HasUser 根据部门id查询是否存在用户
-func (s *UserService) HasUser(deptId int) bool {
+func (s *UserService) HasUser(ctx context.Context, deptId int) bool {
 	var count int64
-	connector.GetDB().Model(model.SysUser{}).Where("dept_id = ?", deptId).Count(&count)
+	connector.DB(ctx).Model(model.SysUser{}).Where("dept_id = ?", deptId).Count(&count)
 	return count > 0
 }
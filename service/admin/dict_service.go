@@ -16,11 +16,11 @@ type DictTypeService struct {
 }
 
 // Create 创建字典类型
-func (s *DictTypeService) Create(param dto.SaveDictTypeRequest) error {
-	if dictType := s.GetByDictType(param.DictType); dictType.DictId > 0 {
+func (s *DictTypeService) Create(ctx context.Context, param dto.SaveDictTypeRequest) error {
+	if dictType := s.GetByDictType(ctx, param.DictType); dictType.DictId > 0 {
 		return errors.New("新增字典" + param.DictName + "失败，字典类型已存在")
 	}
-	return connector.GetDB().Model(model.SysDictType{}).Create(&model.SysDictType{
+	return connector.DB(ctx).Model(model.SysDictType{}).Create(&model.SysDictType{
 		DictName: param.DictName,
 		DictType: param.DictType,
 		Status:   param.Status,
@@ -30,11 +30,11 @@ func (s *DictTypeService) Create(param dto.SaveDictTypeRequest) error {
 }
 
 // Update 更新字典类型
-func (s *DictTypeService) Update(param dto.SaveDictTypeRequest) error {
-	if dictType := s.GetByDictType(param.DictType); dictType.DictId > 0 && dictType.DictId != param.DictId {
+func (s *DictTypeService) Update(ctx context.Context, param dto.SaveDictTypeRequest) error {
+	if dictType := s.GetByDictType(ctx, param.DictType); dictType.DictId > 0 && dictType.DictId != param.DictId {
 		return errors.New("修改字典" + param.DictName + "失败，字典类型已存在")
 	}
-	return connector.GetDB().Model(model.SysDictType{}).Where("dict_id = ?", param.DictId).Updates(&model.SysDictType{
+	return connector.DB(ctx).Model(model.SysDictType{}).Where("dict_id = ?", param.DictId).Updates(&model.SysDictType{
 		DictName: param.DictName,
 		DictType: param.DictType,
 		Status:   param.Status,
@@ -44,15 +44,15 @@ func (s *DictTypeService) Update(param dto.SaveDictTypeRequest) error {
 }
 
 // Delete 删除字典类型
-func (s *DictTypeService) Delete(dictIds []int) error {
-	return connector.GetDB().Model(model.SysDictType{}).Where("dict_id IN ?", dictIds).Delete(&model.SysDictType{}).Error
+func (s *DictTypeService) Delete(ctx context.Context, dictIds []int) error {
+	return connector.DB(ctx).Model(model.SysDictType{}).Where("dict_id IN ?", dictIds).Delete(&model.SysDictType{}).Error
 }
 
 // List 字典类型列表
-func (s *DictTypeService) List(param dto.DictTypeListRequest, isPaging bool) ([]dto.DictTypeListResponse, int) {
+func (s *DictTypeService) List(ctx context.Context, param dto.DictTypeListRequest, isPaging bool) ([]dto.DictTypeListResponse, int) {
 	var count int64
 	dictTypes := make([]dto.DictTypeListResponse, 0)
-	query := connector.GetDB().Model(model.SysDictType{}).Order("dict_id")
+	query := connector.DB(ctx).Model(model.SysDictType{}).Order("dict_id")
 	if param.DictName != "" {
 		query = query.Where("dict_name LIKE ?", "%"+param.DictName+"%")
 	}
@@ -73,16 +73,16 @@ func (s *DictTypeService) List(param dto.DictTypeListRequest, isPaging bool) ([]
 }
 
 // Get 字典类型详情
-func (s *DictTypeService) Get(dictId int) dto.DictTypeDetailResponse {
+func (s *DictTypeService) Get(ctx context.Context, dictId int) dto.DictTypeDetailResponse {
 	var dictType dto.DictTypeDetailResponse
-	connector.GetDB().Model(model.SysDictType{}).Where("dict_id = ?", dictId).Last(&dictType)
+	connector.DB(ctx).Model(model.SysDictType{}).Where("dict_id = ?", dictId).Last(&dictType)
 	return dictType
 }
 
 // GetByDictType 根据字典类型查询详情
-func (s *DictTypeService) GetByDictType(dictType string) dto.DictTypeDetailResponse {
+func (s *DictTypeService) GetByDictType(ctx context.Context, dictType string) dto.DictTypeDetailResponse {
 	var dictTypeResult dto.DictTypeDetailResponse
-	connector.GetDB().Model(model.SysDictType{}).Where("dict_type = ?", dictType).Last(&dictTypeResult)
+	connector.DB(ctx).Model(model.SysDictType{}).Where("dict_type = ?", dictType).Last(&dictTypeResult)
 	return dictTypeResult
 }
 
@@ -95,8 +95,8 @@ type DictDataService struct {
 }
 
 // Create 创建字典数据
-func (s *DictDataService) Create(param dto.SaveDictDataRequest) error {
-	return connector.GetDB().Model(model.SysDictData{}).Create(&model.SysDictData{
+func (s *DictDataService) Create(ctx context.Context, param dto.SaveDictDataRequest) error {
+	return connector.DB(ctx).Model(model.SysDictData{}).Create(&model.SysDictData{
 		DictSort:  param.DictSort,
 		DictLabel: param.DictLabel,
 		DictValue: param.DictValue,
@@ -111,8 +111,8 @@ func (s *DictDataService) Create(param dto.SaveDictDataRequest) error {
 }
 
 // 更新字典数据
-func (s *DictDataService) Update(param dto.SaveDictDataRequest) error {
-	return connector.GetDB().Model(model.SysDictData{}).Where("dict_code = ?", param.DictCode).Updates(&model.SysDictData{
+func (s *DictDataService) Update(ctx context.Context, param dto.SaveDictDataRequest) error {
+	return connector.DB(ctx).Model(model.SysDictData{}).Where("dict_code = ?", param.DictCode).Updates(&model.SysDictData{
 		DictSort:  param.DictSort,
 		DictLabel: param.DictLabel,
 		DictValue: param.DictValue,
@@ -127,15 +127,15 @@ func (s *DictDataService) Update(param dto.SaveDictDataRequest) error {
 }
 
 // Delete 删除字典数据
-func (s *DictDataService) Delete(dictCodes []int) error {
-	return connector.GetDB().Model(model.SysDictData{}).Where("dict_code IN ?", dictCodes).Delete(&model.SysDictData{}).Error
+func (s *DictDataService) Delete(ctx context.Context, dictCodes []int) error {
+	return connector.DB(ctx).Model(model.SysDictData{}).Where("dict_code IN ?", dictCodes).Delete(&model.SysDictData{}).Error
 }
 
 // List 字典数据列表
-func (s *DictDataService) List(param dto.DictDataListRequest, isPaging bool) ([]dto.DictDataListResponse, int) {
+func (s *DictDataService) List(ctx context.Context, param dto.DictDataListRequest, isPaging bool) ([]dto.DictDataListResponse, int) {
 	var count int64
 	dictDataList := make([]dto.DictDataListResponse, 0)
-	query := connector.GetDB().Model(model.SysDictData{}).Order("dict_code")
+	query := connector.DB(ctx).Model(model.SysDictData{}).Order("dict_code")
 	if param.DictLabel != "" {
 		query = query.Where("dict_label LIKE ?", "%"+param.DictLabel+"%")
 	}
@@ -153,34 +153,34 @@ func (s *DictDataService) List(param dto.DictDataListRequest, isPaging bool) ([]
 }
 
 // GetByDictCode 根据字典数据编码获取字典数据详情
-func (s *DictDataService) GetByDictCode(dictCode int) dto.DictDataDetailResponse {
+func (s *DictDataService) GetByDictCode(ctx context.Context, dictCode int) dto.DictDataDetailResponse {
 	var dictData dto.DictDataDetailResponse
-	connector.GetDB().Model(model.SysDictData{}).Where("dict_code = ?", dictCode).Last(&dictData)
+	connector.DB(ctx).Model(model.SysDictData{}).Where("dict_code = ?", dictCode).Last(&dictData)
 	return dictData
 }
 
 // GetByDictType 根据字典类型查询字典数据
-func (s *DictDataService) GetByDictType(dictType string) []dto.DictDataListResponse {
+func (s *DictDataService) GetByDictType(ctx context.Context, dictType string) []dto.DictDataListResponse {
 	dictDataList := make([]dto.DictDataListResponse, 0)
-	connector.GetDB().Model(model.SysDictData{}).Where("status = 0 AND dict_type = ?", dictType).Find(&dictDataList)
+	connector.DB(ctx).Model(model.SysDictData{}).Where("status = 0 AND dict_type = ?", dictType).Find(&dictDataList)
 	return dictDataList
 }
 
 // GetCacheByDictType 根据字典类型查询字典数据
-func (s *DictDataService) GetCacheByDictType(dictType string) []dto.DictDataListResponse {
+func (s *DictDataService) GetCacheByDictType(ctx context.Context, dictType string) []dto.DictDataListResponse {
 	cache := connector.GetCache()
 	dictDataList := make([]dto.DictDataListResponse, 0)
 	// 缓存不为空不从数据库读取，减少数据库压力
-	if dictDataListCache, _ := cache.HGet(context.Background(), redis_key.SysDictKey, dictType).Result(); dictDataListCache != "" {
+	if dictDataListCache, _ := cache.HGet(ctx, redis_key.SysDictKey, dictType).Result(); dictDataListCache != "" {
 		if err := json.Unmarshal([]byte(dictDataListCache), &dictDataList); err == nil {
 			return dictDataList
 		}
 	}
 	// 从数据库读取配置并且记录到缓存
-	dictDataList = s.GetByDictType(dictType)
+	dictDataList = s.GetByDictType(ctx, dictType)
 	if len(dictDataList) > 0 {
 		dictDadasBytes, _ := json.Marshal(&dictDataList)
-		cache.HSet(context.Background(), redis_key.SysDictKey, dictType, string(dictDadasBytes))
+		cache.HSet(ctx, redis_key.SysDictKey, dictType, string(dictDadasBytes))
 	}
 	return dictDataList
 }
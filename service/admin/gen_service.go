@@ -0,0 +1,398 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/hugo8680/goat/common/generator"
+	"github.com/hugo8680/goat/framework/config"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/model"
+	"github.com/hugo8680/goat/model/dto"
+	"os"
+	"strings"
+)
+
+type GenService struct {
+}
+
+// ListDbTables 数据库表列表，排除已导入和代码生成相关的表
+func (s *GenService) ListDbTables(ctx context.Context, param dto.DbTableListRequest, isPaging bool) ([]dto.DbTableListResponse, int) {
+	var count int64
+	tables := make([]dto.DbTableListResponse, 0)
+	query := connector.DB(ctx).Table("information_schema.tables").
+		Select("table_name AS table_name, table_comment AS table_comment, create_time AS create_time, IFNULL(update_time, create_time) AS update_time").
+		Where("table_schema = (SELECT DATABASE()) AND table_type = 'BASE TABLE'").
+		Where("table_name NOT LIKE 'gen\\_%'").
+		Where("table_name NOT IN (SELECT table_name FROM gen_table)").
+		Order("create_time DESC")
+	if param.TableName != "" {
+		query.Where("LOWER(table_name) LIKE LOWER(?)", "%"+param.TableName+"%")
+	}
+	if param.TableComment != "" {
+		query.Where("LOWER(table_comment) LIKE LOWER(?)", "%"+param.TableComment+"%")
+	}
+	if isPaging {
+		query.Count(&count).Offset((param.PageNum - 1) * param.PageSize).Limit(param.PageSize)
+	}
+	query.Scan(&tables)
+	return tables, int(count)
+}
+
+// ListDbTablesByNames 根据表名查询数据库表
+func (s *GenService) ListDbTablesByNames(ctx context.Context, tableNames []string) []dto.DbTableListResponse {
+	tables := make([]dto.DbTableListResponse, 0)
+	connector.DB(ctx).Table("information_schema.tables").
+		Select("table_name AS table_name, table_comment AS table_comment, create_time AS create_time, IFNULL(update_time, create_time) AS update_time").
+		Where("table_schema = (SELECT DATABASE()) AND table_name IN ?", tableNames).
+		Scan(&tables)
+	return tables
+}
+
+// ListDbTableColumns 根据表名查询数据库表字段
+func (s *GenService) ListDbTableColumns(ctx context.Context, tableName string) []dto.DbTableColumnResponse {
+	columns := make([]dto.DbTableColumnResponse, 0)
+	connector.DB(ctx).Table("information_schema.columns").
+		Select("column_name AS column_name, column_comment AS column_comment, column_type AS column_type, "+
+			"(CASE WHEN is_nullable = 'NO' AND column_key != 'PRI' THEN '1' ELSE '0' END) AS is_required, "+
+			"(CASE WHEN column_key = 'PRI' THEN '1' ELSE '0' END) AS is_pk, "+
+			"(CASE WHEN extra = 'auto_increment' THEN '1' ELSE '0' END) AS is_increment, "+
+			"ordinal_position AS sort").
+		Where("table_schema = (SELECT DATABASE()) AND table_name = ?", tableName).
+		Order("ordinal_position").
+		Scan(&columns)
+	return columns
+}
+
+// Import 导入表结构
+func (s *GenService) Import(ctx context.Context, tableNames []string, operName string) error {
+	tables := s.ListDbTablesByNames(ctx, tableNames)
+	if len(tables) == 0 {
+		return errors.New("请选择要导入的表")
+	}
+	gen := config.GetSetting().Gen
+	tx := connector.DB(ctx).Begin()
+	for _, table := range tables {
+		genTable := model.GenTable{
+			TableName:      table.TableName,
+			TableComment:   table.TableComment,
+			ClassName:      generator.ClassName(table.TableName, gen.AutoRemovePre, gen.TablePrefix),
+			PackageName:    "admin",
+			ModuleName:     gen.ModuleName,
+			BusinessName:   generator.BusinessName(table.TableName),
+			FunctionName:   strings.TrimSuffix(table.TableComment, "表"),
+			FunctionAuthor: gen.Author,
+			CreateBy:       operName,
+		}
+		if err := tx.Model(model.GenTable{}).Create(&genTable).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, column := range s.ListDbTableColumns(ctx, table.TableName) {
+			genTableColumn := s.newGenTableColumn(genTable.TableId, column)
+			genTableColumn.CreateBy = operName
+			if err := tx.Model(model.GenTableColumn{}).Create(&genTableColumn).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	return tx.Commit().Error
+}
+
+// List 代码生成业务表列表
+func (s *GenService) List(ctx context.Context, param dto.GenTableListRequest, isPaging bool) ([]dto.GenTableListResponse, int) {
+	var count int64
+	tables := make([]dto.GenTableListResponse, 0)
+	query := connector.DB(ctx).Model(model.GenTable{}).Order("table_id DESC")
+	if param.TableName != "" {
+		query.Where("LOWER(table_name) LIKE LOWER(?)", "%"+param.TableName+"%")
+	}
+	if param.TableComment != "" {
+		query.Where("LOWER(table_comment) LIKE LOWER(?)", "%"+param.TableComment+"%")
+	}
+	if param.BeginTime != "" && param.EndTime != "" {
+		query.Where("create_time BETWEEN ? AND ?", param.BeginTime, param.EndTime)
+	}
+	if isPaging {
+		query.Count(&count).Offset((param.PageNum - 1) * param.PageSize).Limit(param.PageSize)
+	}
+	query.Find(&tables)
+	return tables, int(count)
+}
+
+// ListAll 全部代码生成业务表
+func (s *GenService) ListAll(ctx context.Context) []dto.GenTableListResponse {
+	tables := make([]dto.GenTableListResponse, 0)
+	connector.DB(ctx).Model(model.GenTable{}).Order("table_id").Find(&tables)
+	return tables
+}
+
+// Get 根据表id获取代码生成业务表详情，包含字段信息
+func (s *GenService) Get(ctx context.Context, tableId int) dto.GenTableDetailResponse {
+	var table dto.GenTableDetailResponse
+	connector.DB(ctx).Model(model.GenTable{}).Where("table_id = ?", tableId).Last(&table)
+	s.fillDetail(ctx, &table)
+	return table
+}
+
+// GetByTableName 根据表名获取代码生成业务表详情，包含字段信息
+func (s *GenService) GetByTableName(ctx context.Context, tableName string) dto.GenTableDetailResponse {
+	var table dto.GenTableDetailResponse
+	connector.DB(ctx).Model(model.GenTable{}).Where("table_name = ?", tableName).Last(&table)
+	s.fillDetail(ctx, &table)
+	return table
+}
+
+// ListColumns 根据表id查询字段列表
+func (s *GenService) ListColumns(ctx context.Context, tableId int) []dto.GenTableColumnResponse {
+	columns := make([]dto.GenTableColumnResponse, 0)
+	connector.DB(ctx).Model(model.GenTableColumn{}).Where("table_id = ?", tableId).Order("sort").Find(&columns)
+	return columns
+}
+
+// Update 修改代码生成业务表
+func (s *GenService) Update(ctx context.Context, param dto.SaveGenTableRequest, columns []dto.SaveGenTableColumnRequest) error {
+	tx := connector.DB(ctx).Begin()
+	if err := tx.Model(model.GenTable{}).Where("table_id = ?", param.TableId).
+		Select("table_comment", "class_name", "tpl_category", "package_name", "module_name", "business_name", "function_name", "function_author", "gen_type", "gen_path", "options", "update_by", "remark").
+		Updates(&model.GenTable{
+			TableComment:   param.TableComment,
+			ClassName:      param.ClassName,
+			TplCategory:    param.TplCategory,
+			PackageName:    param.PackageName,
+			ModuleName:     param.ModuleName,
+			BusinessName:   param.BusinessName,
+			FunctionName:   param.FunctionName,
+			FunctionAuthor: param.FunctionAuthor,
+			GenType:        param.GenType,
+			GenPath:        param.GenPath,
+			Options:        param.Options,
+			UpdateBy:       param.UpdateBy,
+			Remark:         param.Remark,
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, column := range columns {
+		if err := tx.Model(model.GenTableColumn{}).Where("column_id = ? AND table_id = ?", column.ColumnId, param.TableId).
+			Select("column_comment", "go_type", "go_field", "is_required", "is_insert", "is_edit", "is_list", "is_query", "query_type", "html_type", "dict_type", "update_by").
+			Updates(&model.GenTableColumn{
+				ColumnComment: column.ColumnComment,
+				GoType:        column.GoType,
+				GoField:       column.GoField,
+				IsRequired:    column.IsRequired,
+				IsInsert:      column.IsInsert,
+				IsEdit:        column.IsEdit,
+				IsList:        column.IsList,
+				IsQuery:       column.IsQuery,
+				QueryType:     column.QueryType,
+				HtmlType:      column.HtmlType,
+				DictType:      column.DictType,
+				UpdateBy:      column.UpdateBy,
+			}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// Delete 删除代码生成业务表
+func (s *GenService) Delete(ctx context.Context, tableIds []int) error {
+	tx := connector.DB(ctx).Begin()
+	if err := tx.Model(model.GenTable{}).Where("table_id IN ?", tableIds).Delete(&model.GenTable{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(model.GenTableColumn{}).Where("table_id IN ?", tableIds).Delete(&model.GenTableColumn{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// SyncDb 同步数据库表结构
+//
+// 保留仍存在字段的生成配置，新增字段按默认规则初始化，删除已不存在的字段
+func (s *GenService) SyncDb(ctx context.Context, tableName, operName string) error {
+	table := s.GetByTableName(ctx, tableName)
+	if table.TableId <= 0 {
+		return errors.New("同步数据失败，业务表不存在")
+	}
+	dbColumns := s.ListDbTableColumns(ctx, tableName)
+	if len(dbColumns) == 0 {
+		return errors.New("同步数据失败，原表结构不存在")
+	}
+	existColumns := make(map[string]dto.GenTableColumnResponse)
+	for _, column := range table.Columns {
+		existColumns[column.ColumnName] = column
+	}
+	tx := connector.DB(ctx).Begin()
+	columnNames := make([]string, 0, len(dbColumns))
+	for _, dbColumn := range dbColumns {
+		columnNames = append(columnNames, dbColumn.ColumnName)
+		genTableColumn := s.newGenTableColumn(table.TableId, dbColumn)
+		if exist, ok := existColumns[dbColumn.ColumnName]; ok {
+			// 保留用户修改过的生成配置，主键、自增等结构信息以数据库为准
+			if err := tx.Model(model.GenTableColumn{}).Where("column_id = ?", exist.ColumnId).
+				Select("column_comment", "column_type", "is_pk", "is_increment", "is_required", "sort", "update_by").
+				Updates(&model.GenTableColumn{
+					ColumnComment: genTableColumn.ColumnComment,
+					ColumnType:    genTableColumn.ColumnType,
+					IsPk:          genTableColumn.IsPk,
+					IsIncrement:   genTableColumn.IsIncrement,
+					IsRequired:    genTableColumn.IsRequired,
+					Sort:          genTableColumn.Sort,
+					UpdateBy:      operName,
+				}).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+			continue
+		}
+		genTableColumn.CreateBy = operName
+		if err := tx.Model(model.GenTableColumn{}).Create(&genTableColumn).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Model(model.GenTableColumn{}).Where("table_id = ? AND column_name NOT IN ?", table.TableId, columnNames).Delete(&model.GenTableColumn{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// Preview 预览代码
+func (s *GenService) Preview(ctx context.Context, tableId int) (map[string]string, error) {
+	table := s.Get(ctx, tableId)
+	if table.TableId <= 0 {
+		return nil, errors.New("业务表不存在")
+	}
+	return generator.Render(s.toGeneratorTable(table))
+}
+
+// Download 生成代码压缩包
+func (s *GenService) Download(ctx context.Context, tableNames []string) ([]byte, error) {
+	files := make(map[string]string)
+	for _, tableName := range tableNames {
+		table := s.GetByTableName(ctx, tableName)
+		if table.TableId <= 0 {
+			return nil, errors.New("业务表" + tableName + "不存在")
+		}
+		tableFiles, err := generator.Render(s.toGeneratorTable(table))
+		if err != nil {
+			return nil, err
+		}
+		for fileName, content := range tableFiles {
+			files[fileName] = content
+		}
+	}
+	return generator.Zip(files)
+}
+
+// GenCode 生成代码到项目根目录下的自定义路径，路径为/时生成到项目根目录
+//
+// overwrite为false时，文件已存在则不生成并返回已存在的文件
+func (s *GenService) GenCode(ctx context.Context, tableName string, overwrite bool) error {
+	table := s.GetByTableName(ctx, tableName)
+	if table.TableId <= 0 {
+		return errors.New("业务表" + tableName + "不存在")
+	}
+	files, err := generator.Render(s.toGeneratorTable(table))
+	if err != nil {
+		return err
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	genPath := table.GenPath
+	if genPath == "" {
+		genPath = "/"
+	}
+	return generator.WriteFiles(root, genPath, files, overwrite)
+}
+
+// fillDetail 填充业务表的字段和上级菜单
+func (s *GenService) fillDetail(ctx context.Context, table *dto.GenTableDetailResponse) {
+	if table.TableId <= 0 {
+		return
+	}
+	table.Columns = s.ListColumns(ctx, table.TableId)
+	if table.Options != "" {
+		var options struct {
+			ParentMenuId int `json:"parentMenuId"`
+		}
+		if err := json.Unmarshal([]byte(table.Options), &options); err == nil {
+			table.ParentMenuId = options.ParentMenuId
+		}
+	}
+}
+
+// newGenTableColumn 根据数据库字段初始化业务表字段
+func (s *GenService) newGenTableColumn(tableId int, dbColumn dto.DbTableColumnResponse) model.GenTableColumn {
+	column := generator.NewColumn(dbColumn.ColumnName, dbColumn.ColumnComment, dbColumn.ColumnType, dbColumn.IsPk == "1", dbColumn.IsIncrement == "1", dbColumn.IsRequired == "1")
+	return model.GenTableColumn{
+		TableId:       tableId,
+		ColumnName:    column.ColumnName,
+		ColumnComment: column.ColumnComment,
+		ColumnType:    column.ColumnType,
+		GoType:        column.GoType,
+		GoField:       column.GoField,
+		IsPk:          s.flag(column.IsPk),
+		IsIncrement:   s.flag(column.IsIncrement),
+		IsRequired:    s.flag(column.IsRequired),
+		IsInsert:      s.flag(column.IsInsert),
+		IsEdit:        s.flag(column.IsEdit),
+		IsList:        s.flag(column.IsList),
+		IsQuery:       s.flag(column.IsQuery),
+		QueryType:     column.QueryType,
+		HtmlType:      column.HtmlType,
+		Sort:          dbColumn.Sort,
+	}
+}
+
+// toGeneratorTable 业务表转换为代码生成模板数据
+func (s *GenService) toGeneratorTable(table dto.GenTableDetailResponse) *generator.Table {
+	columns := make([]generator.Column, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		columns = append(columns, generator.Column{
+			ColumnName:    column.ColumnName,
+			ColumnComment: column.ColumnComment,
+			ColumnType:    column.ColumnType,
+			GoType:        column.GoType,
+			GoField:       column.GoField,
+			IsPk:          column.IsPk == "1",
+			IsIncrement:   column.IsIncrement == "1",
+			IsRequired:    column.IsRequired == "1",
+			IsInsert:      column.IsInsert == "1",
+			IsEdit:        column.IsEdit == "1",
+			IsList:        column.IsList == "1",
+			IsQuery:       column.IsQuery == "1",
+			QueryType:     column.QueryType,
+			HtmlType:      column.HtmlType,
+			DictType:      column.DictType,
+		})
+	}
+	return &generator.Table{
+		TableName:      table.TableName,
+		TableComment:   table.TableComment,
+		ClassName:      table.ClassName,
+		ModuleName:     table.ModuleName,
+		BusinessName:   table.BusinessName,
+		FunctionName:   table.FunctionName,
+		FunctionAuthor: table.FunctionAuthor,
+		ParentMenuId:   table.ParentMenuId,
+		Columns:        columns,
+	}
+}
+
+// flag 布尔值转换为生成配置标识
+func (s *GenService) flag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"github.com/hugo8680/goat/common/constant/job_key"
+	"github.com/hugo8680/goat/common/scheduler"
+	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/model"
+	"github.com/hugo8680/goat/model/dto"
+	"strconv"
+	"time"
+)
+
+type JobLogService struct {
+}
+
+// Record 记录调度日志，作为调度器的执行结果处理函数
+func (s *JobLogService) Record(result scheduler.Result) {
+	jobLog := model.SysJobLog{
+		JobId:        result.Job.JobId,
+		JobName:      result.Job.JobName,
+		JobGroup:     result.Job.JobGroup,
+		InvokeTarget: result.Job.InvokeTarget,
+		JobMessage:   result.Job.JobName + " 总共耗时：" + strconv.FormatInt(result.StopTime.Sub(result.StartTime).Milliseconds(), 10) + "毫秒",
+		Status:       job_key.JOB_LOG_STATUS_SUCCESS,
+		StartTime:    datetime.Datetime{Time: result.StartTime},
+		StopTime:     datetime.Datetime{Time: result.StopTime},
+	}
+	if result.Err != nil {
+		jobLog.Status = job_key.JOB_LOG_STATUS_FAIL
+		jobLog.ExceptionInfo = result.Err.Error()
+	}
+	if err := connector.GetDB().Model(model.SysJobLog{}).Create(&jobLog).Error; err != nil {
+		logger.Module("job").Error("record job log", "error", err)
+	}
+}
+
+// Delete 删除调度日志
+func (s *JobLogService) Delete(ctx context.Context, jobLogIds []int) error {
+	db := connector.DB(ctx)
+	if len(jobLogIds) > 0 {
+		return db.Model(model.SysJobLog{}).Where("job_log_id IN ?", jobLogIds).Delete(&model.SysJobLog{}).Error
+	}
+	// 为解决 WHERE conditions required 错误，添加 Where("job_log_id > ?", 0) 这个条件
+	return db.Model(model.SysJobLog{}).Where("job_log_id > ?", 0).Delete(&model.SysJobLog{}).Error
+}
+
+// List 调度日志列表
+func (s *JobLogService) List(ctx context.Context, param dto.JobLogListRequest, isPaging bool) ([]dto.JobLogListResponse, int) {
+	var count int64
+	jobLogs := make([]dto.JobLogListResponse, 0)
+	query := connector.DB(ctx).Model(model.SysJobLog{}).Order("job_log_id DESC")
+	if param.JobName != "" {
+		query.Where("job_name LIKE ?", "%"+param.JobName+"%")
+	}
+	if param.JobGroup != "" {
+		query.Where("job_group = ?", param.JobGroup)
+	}
+	if param.InvokeTarget != "" {
+		query.Where("invoke_target LIKE ?", "%"+param.InvokeTarget+"%")
+	}
+	if param.Status != "" {
+		query.Where("status = ?", param.Status)
+	}
+	if param.BeginTime != "" && param.EndTime != "" {
+		query.Where("create_time BETWEEN ? AND ?", param.BeginTime, param.EndTime)
+	}
+	if isPaging {
+		query.Count(&count).Offset((param.PageNum - 1) * param.PageSize).Limit(param.PageSize)
+	}
+	query.Find(&jobLogs)
+	return jobLogs, int(count)
+}
+
+// Get 根据日志id获取调度日志详情
+func (s *JobLogService) Get(ctx context.Context, jobLogId int) dto.JobLogListResponse {
+	var jobLog dto.JobLogListResponse
+	connector.DB(ctx).Model(model.SysJobLog{}).Where("job_log_id = ?", jobLogId).Last(&jobLog)
+	return jobLog
+}
+
+// GetLastStartTime 获取任务最近一次的执行时间，没有执行记录时返回零值
+func (s *JobLogService) GetLastStartTime(ctx context.Context, jobId int) time.Time {
+	var jobLog model.SysJobLog
+	if err := connector.DB(ctx).Model(model.SysJobLog{}).Where("job_id = ?", jobId).Order("job_log_id DESC").Take(&jobLog).Error; err != nil {
+		return time.Time{}
+	}
+	return jobLog.StartTime.Time
+}
@@ -0,0 +1,181 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/hugo8680/goat/common/constant/job_key"
+	"github.com/hugo8680/goat/common/scheduler"
+	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/model"
+	"github.com/hugo8680/goat/model/dto"
+)
+
+type JobService struct {
+}
+
+// InitScheduler 注册内置调用目标，加载状态正常的定时任务并启动调度器
+func (s *JobService) InitScheduler() error {
+	ctx := context.Background()
+	RegisterDefaultTasks()
+	jobLogService := &JobLogService{}
+	scheduler.Default().SetHandler(jobLogService.Record)
+	var jobs []model.SysJob
+	if err := connector.DB(ctx).Model(model.SysJob{}).Where("status = ?", job_key.JOB_STATUS_NORMAL).Find(&jobs).Error; err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		schedulerJob := s.toSchedulerJob(job)
+		// 上次执行时间取最近一条调度日志及任务最近一次恢复或修改时间中较晚的，用于启动时补偿错过的执行，暂停期间的执行不补偿
+		schedulerJob.PrevTime = jobLogService.GetLastStartTime(ctx, job.JobId)
+		if job.UpdateTime.Time.After(schedulerJob.PrevTime) {
+			schedulerJob.PrevTime = job.UpdateTime.Time
+		}
+		if err := scheduler.Default().Add(schedulerJob); err != nil {
+			return errors.New("加载定时任务" + job.JobName + "失败，" + err.Error())
+		}
+	}
+	scheduler.Default().Start()
+	return nil
+}
+
+// StopScheduler 停止调度器，等待正在执行的任务结束或ctx取消
+func (s *JobService) StopScheduler(ctx context.Context) error {
+	select {
+	case <-scheduler.Default().Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return errors.New("等待定时任务结束超时")
+	}
+}
+
+// Create 新增定时任务
+func (s *JobService) Create(ctx context.Context, param dto.SaveJobRequest) error {
+	job := model.SysJob{
+		JobName:        param.JobName,
+		JobGroup:       param.JobGroup,
+		InvokeTarget:   param.InvokeTarget,
+		CronExpression: param.CronExpression,
+		MisfirePolicy:  param.MisfirePolicy,
+		Concurrent:     param.Concurrent,
+		Status:         param.Status,
+		CreateBy:       param.CreateBy,
+		Remark:         param.Remark,
+	}
+	if err := connector.DB(ctx).Model(model.SysJob{}).Create(&job).Error; err != nil {
+		return err
+	}
+	return s.schedule(ctx, job.JobId)
+}
+
+// Update 更新定时任务
+func (s *JobService) Update(ctx context.Context, param dto.SaveJobRequest) error {
+	if err := connector.DB(ctx).Model(model.SysJob{}).Where("job_id = ?", param.JobId).Updates(&model.SysJob{
+		JobName:        param.JobName,
+		JobGroup:       param.JobGroup,
+		InvokeTarget:   param.InvokeTarget,
+		CronExpression: param.CronExpression,
+		MisfirePolicy:  param.MisfirePolicy,
+		Concurrent:     param.Concurrent,
+		Status:         param.Status,
+		UpdateBy:       param.UpdateBy,
+		Remark:         param.Remark,
+	}).Error; err != nil {
+		return err
+	}
+	return s.schedule(ctx, param.JobId)
+}
+
+// Delete 删除定时任务
+func (s *JobService) Delete(ctx context.Context, jobIds []int) error {
+	if err := connector.DB(ctx).Model(model.SysJob{}).Where("job_id IN ?", jobIds).Delete(&model.SysJob{}).Error; err != nil {
+		return err
+	}
+	for _, jobId := range jobIds {
+		scheduler.Default().Remove(jobId)
+	}
+	return nil
+}
+
+// ChangeStatus 修改定时任务状态，暂停时从调度器中移除，恢复时重新调度
+func (s *JobService) ChangeStatus(ctx context.Context, param dto.SaveJobRequest) error {
+	if err := connector.DB(ctx).Model(model.SysJob{}).Where("job_id = ?", param.JobId).Updates(&model.SysJob{
+		Status:   param.Status,
+		UpdateBy: param.UpdateBy,
+	}).Error; err != nil {
+		return err
+	}
+	return s.schedule(ctx, param.JobId)
+}
+
+// Run 立即执行一次定时任务
+func (s *JobService) Run(ctx context.Context, jobId int) error {
+	var job model.SysJob
+	if err := connector.DB(ctx).Model(model.SysJob{}).Where("job_id = ?", jobId).Take(&job).Error; err != nil {
+		return errors.New("任务不存在或已过期")
+	}
+	scheduler.Default().RunOnce(s.toSchedulerJob(job))
+	return nil
+}
+
+// List 定时任务列表
+func (s *JobService) List(ctx context.Context, param dto.JobListRequest, isPaging bool) ([]dto.JobListResponse, int) {
+	var count int64
+	jobs := make([]dto.JobListResponse, 0)
+	query := connector.DB(ctx).Model(model.SysJob{}).Order("job_id")
+	if param.JobName != "" {
+		query.Where("job_name LIKE ?", "%"+param.JobName+"%")
+	}
+	if param.JobGroup != "" {
+		query.Where("job_group = ?", param.JobGroup)
+	}
+	if param.InvokeTarget != "" {
+		query.Where("invoke_target LIKE ?", "%"+param.InvokeTarget+"%")
+	}
+	if param.Status != "" {
+		query.Where("status = ?", param.Status)
+	}
+	if isPaging {
+		query.Count(&count).Offset((param.PageNum - 1) * param.PageSize).Limit(param.PageSize)
+	}
+	query.Find(&jobs)
+	return jobs, int(count)
+}
+
+// Get 根据任务id获取定时任务详情
+func (s *JobService) Get(ctx context.Context, jobId int) dto.JobDetailResponse {
+	var job dto.JobDetailResponse
+	connector.DB(ctx).Model(model.SysJob{}).Where("job_id = ?", jobId).Last(&job)
+	if job.JobId > 0 && job.Status == job_key.JOB_STATUS_NORMAL {
+		if nextTime := scheduler.Default().NextTime(job.JobId); !nextTime.IsZero() {
+			job.NextValidTime = nextTime.Format(datetime.DATETIME_FORMAT0)
+		}
+	}
+	return job
+}
+
+// schedule 根据数据库中的任务状态更新调度器
+func (s *JobService) schedule(ctx context.Context, jobId int) error {
+	var job model.SysJob
+	if err := connector.DB(ctx).Model(model.SysJob{}).Where("job_id = ?", jobId).Take(&job).Error; err != nil {
+		return err
+	}
+	if job.Status != job_key.JOB_STATUS_NORMAL {
+		scheduler.Default().Remove(job.JobId)
+		return nil
+	}
+	return scheduler.Default().Add(s.toSchedulerJob(job))
+}
+
+// toSchedulerJob 转换为调度器任务
+func (s *JobService) toSchedulerJob(job model.SysJob) scheduler.Job {
+	return scheduler.Job{
+		JobId:          job.JobId,
+		JobName:        job.JobName,
+		JobGroup:       job.JobGroup,
+		InvokeTarget:   job.InvokeTarget,
+		CronExpression: job.CronExpression,
+		MisfirePolicy:  job.MisfirePolicy,
+		Concurrent:     job.Concurrent,
+	}
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/common/scheduler"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/model"
+	"strconv"
+	"time"
+)
+
+// 日志默认保留天数
+const defaultLogRetentionDays = 30
+
+// RegisterDefaultTasks 注册内置调用目标
+//
+// 自定义调用目标通过scheduler.RegisterTask注册，调用目标字符串格式为name或name(params)
+func RegisterDefaultTasks() {
+	// 清理操作日志，参数为保留天数，如cleanOperLog(30)
+	scheduler.RegisterTask("cleanOperLog", func(ctx context.Context, params string) error {
+		before, err := retentionTime(params)
+		if err != nil {
+			return err
+		}
+		return connector.DB(ctx).Where("oper_time < ?", before).Delete(&model.SysOperLog{}).Error
+	})
+	// 清理登录日志，参数为保留天数，如cleanLoginLog(30)
+	scheduler.RegisterTask("cleanLoginLog", func(ctx context.Context, params string) error {
+		before, err := retentionTime(params)
+		if err != nil {
+			return err
+		}
+		return connector.DB(ctx).Where("login_time < ?", before).Delete(&model.SysLoginLog{}).Error
+	})
+	// 清理调度日志，参数为保留天数，如cleanJobLog(30)，保留每个任务最近一条日志，用于启动时计算错过的执行
+	scheduler.RegisterTask("cleanJobLog", func(ctx context.Context, params string) error {
+		before, err := retentionTime(params)
+		if err != nil {
+			return err
+		}
+		lastIds := make([]int, 0)
+		if err = connector.DB(ctx).Model(model.SysJobLog{}).Group("job_id").Pluck("MAX(job_log_id)", &lastIds).Error; err != nil {
+			return err
+		}
+		query := connector.DB(ctx).Where("create_time < ?", before)
+		if len(lastIds) > 0 {
+			query = query.Where("job_log_id NOT IN ?", lastIds)
+		}
+		return query.Delete(&model.SysJobLog{}).Error
+	})
+	// 刷新参数缓存
+	scheduler.RegisterTask("refreshConfigCache", func(ctx context.Context, params string) error {
+		return connector.GetCache().Del(ctx, redis_key.SysConfigKey).Err()
+	})
+}
+
+// retentionTime 根据保留天数计算清理的截止时间
+func retentionTime(params string) (time.Time, error) {
+	days := defaultLogRetentionDays
+	if params != "" {
+		var err error
+		if days, err = strconv.Atoi(params); err != nil || days < 0 {
+			return time.Time{}, errors.New("保留天数参数错误：" + params)
+		}
+	}
+	return time.Now().AddDate(0, 0, -days), nil
+}
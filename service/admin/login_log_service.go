@@ -1,11 +1,12 @@
 package admin
 
 import (
-	"github.com/hugo8680/goat/common/constant/redis_key"
+	"context"
+	"github.com/hugo8680/goat/common/batch"
+	"github.com/hugo8680/goat/common/ip"
 	"github.com/hugo8680/goat/framework/connector"
 	"github.com/hugo8680/goat/model"
 	"github.com/hugo8680/goat/model/dto"
-	"log"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,8 +15,8 @@ type LoginLogService struct {
 }
 
 // Delete 删除登录日志
-func (s *LoginLogService) Delete(infoIds []int) error {
-	db := connector.GetDB()
+func (s *LoginLogService) Delete(ctx context.Context, infoIds []int) error {
+	db := connector.DB(ctx)
 	if len(infoIds) > 0 {
 		return db.Model(model.SysLoginLog{}).Where("info_id IN ?", infoIds).Delete(&model.SysLoginLog{}).Error
 	}
@@ -24,13 +25,16 @@ func (s *LoginLogService) Delete(infoIds []int) error {
 }
 
 // List 获取登录日志列表
-func (s *LoginLogService) List(param dto.LoginLogListRequest, isPaging bool) ([]dto.LoginLogListResponse, int) {
+func (s *LoginLogService) List(ctx context.Context, param dto.LoginLogListRequest, isPaging bool) ([]dto.LoginLogListResponse, int) {
 	var count int64
 	loginLogs := make([]dto.LoginLogListResponse, 0)
-	query := connector.GetDB().Model(model.SysLoginLog{}).Order(param.OrderByColumn + " " + param.OrderRule)
+	query := connector.DB(ctx).Model(model.SysLoginLog{}).Order(param.OrderByColumn + " " + param.OrderRule)
 	if param.Ipaddr != "" {
 		query = query.Where("ipaddr LIKE ?", "%"+param.Ipaddr+"%")
 	}
+	if param.RequestId != "" {
+		query = query.Where("request_id = ?", param.RequestId)
+	}
 	if param.UserName != "" {
 		query = query.Where("user_name LIKE ?", "%"+param.UserName+"%")
 	}
@@ -47,28 +51,53 @@ func (s *LoginLogService) List(param dto.LoginLogListRequest, isPaging bool) ([]
 	return loginLogs, int(count)
 }
 
-// Create 记录登录信息
+// 登录日志异步批量写入
+var loginLogWriter = batch.New("login_log", (&LoginLogService{}).flush)
+
+// StartWriter 启动登录日志写入协程
+func (s *LoginLogService) StartWriter() error {
+	return loginLogWriter.Start(logQueueOptions())
+}
+
+// StopWriter 停止接收登录日志，等待队列中的日志写入完成或ctx取消
+func (s *LoginLogService) StopWriter(ctx context.Context) error {
+	return loginLogWriter.Close(ctx)
+}
+
+// Create 记录登录信息，写入队列后由后台协程批量入库
 func (s *LoginLogService) Create(param dto.SaveLoginLogRequest) error {
-	go func() {
-		err := func() error {
-			return connector.GetDB().Model(model.SysLoginLog{}).Create(&model.SysLoginLog{
-				UserName:      param.UserName,
-				Ipaddr:        param.Ipaddr,
-				LoginLocation: param.LoginLocation,
-				Browser:       param.Browser,
-				Os:            param.Os,
-				Status:        param.Status,
-				Msg:           param.Msg,
-				LoginTime:     param.LoginTime,
-			}).Error
-		}()
-		if err != nil {
-			log.Println(err)
-		}
-	}()
+	loginLogWriter.Push(param)
 	return nil
 }
 
+// flush 批量写入登录信息，入库前查询ip归属地
+func (s *LoginLogService) flush(params []dto.SaveLoginLogRequest) error {
+	loginLogs := make([]model.SysLoginLog, 0, len(params))
+	for _, param := range params {
+		if param.LoginLocation == "" {
+			param.LoginLocation = ip.GetLocation(param.Ipaddr)
+		}
+		loginLogs = append(loginLogs, model.SysLoginLog{
+			UserName:      param.UserName,
+			Ipaddr:        param.Ipaddr,
+			LoginLocation: param.LoginLocation,
+			Browser:       param.Browser,
+			Os:            param.Os,
+			Status:        param.Status,
+			Msg:           param.Msg,
+			LoginTime:     param.LoginTime,
+			RequestId:     param.RequestId,
+		})
+	}
+	return connector.GetDB().Model(model.SysLoginLog{}).Create(&loginLogs).Error
+}
+
+// UnLock 解锁账号，清除账号的登录失败记录及锁定
 func (s *LoginLogService) UnLock(ctx *gin.Context) error {
-	return connector.GetCache().Del(ctx.Request.Context(), redis_key.LoginPasswordErrorKey+ctx.Param("userName")).Err()
+	return (&LoginThrottleService{}).UnlockUser(ctx.Request.Context(), ctx.Param("userName"))
+}
+
+// UnLockIp 解锁ip，清除ip的登录失败记录及锁定
+func (s *LoginLogService) UnLockIp(ctx *gin.Context) error {
+	return (&LoginThrottleService{}).UnlockIp(ctx.Request.Context(), ctx.Param("ipaddr"))
 }
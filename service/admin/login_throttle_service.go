@@ -0,0 +1,259 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/common/uuid"
+	"github.com/hugo8680/goat/framework/config"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/framework/logger"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// failScript 记录一次登录失败，清理滑动窗口外的记录并返回窗口内的失败次数
+//
+// KEYS[1]失败记录，ARGV[1]当前时间毫秒，ARGV[2]窗口毫秒，ARGV[3]记录id
+var failScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', tonumber(ARGV[1]) - tonumber(ARGV[2]))
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return redis.call('ZCARD', KEYS[1])
+`)
+
+const (
+	// 账号维度达到失败上限后的首次延迟时间，之后每次失败翻倍
+	loginDelay = time.Second
+	// 账号维度的最长延迟时间
+	loginMaxDelay = time.Second * 10
+)
+
+// throttleScope 登录限流维度
+type throttleScope struct {
+	key      string // 缓存键名后缀
+	name     string // 名称，用于提示信息
+	maxRetry int
+	delay    bool // 达到上限后延迟响应而不锁定
+}
+
+// LoginThrottleService 登录限流
+//
+// 按ip、账号及ip加账号三个维度统计滑动窗口内的登录失败次数，ip及ip加账号维度达到上限即锁定该维度，
+// 同一维度再次锁定时锁定时间翻倍；账号维度达到上限后不锁定，须输入图形验证码且每次登录延迟响应，
+// 避免他人从任意ip故意输错密码锁定账号；失败次数达到captchaAfter后须输入图形验证码
+type LoginThrottleService struct {
+}
+
+// Check 登录前检查ip及账号是否已锁定，账号失败次数达到上限时延迟响应
+func (s *LoginThrottleService) Check(ctx context.Context, ip, userName string) error {
+	cache := connector.GetCache()
+	for _, scope := range s.scopes(ip, userName) {
+		if scope.delay {
+			if err := s.delay(ctx, scope); err != nil {
+				return err
+			}
+			continue
+		}
+		if ttl := cache.PTTL(ctx, redis_key.LoginLockKey+scope.key).Val(); ttl > 0 {
+			return errors.New("登录失败次数过多，" + scope.name + "已锁定，请" + s.minutes(ttl) + "分钟后重试")
+		}
+	}
+	return nil
+}
+
+// CaptchaRequired ip或账号在窗口内的失败次数达到上限后须输入图形验证码，userName为空时仅按ip判断
+//
+// 账号维度达到失败上限时同样须输入图形验证码
+func (s *LoginThrottleService) CaptchaRequired(ctx context.Context, ip, userName string) bool {
+	captchaAfter := config.GetSetting().Auth.Throttle.CaptchaAfter
+	if captchaAfter <= 0 {
+		captchaAfter = 3
+	}
+	for _, scope := range s.scopes(ip, userName) {
+		limit := captchaAfter
+		if scope.delay {
+			limit = min(limit, scope.maxRetry)
+		}
+		if s.count(ctx, scope) >= int64(limit) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fail 记录登录失败，ip或ip加账号维度达到上限时锁定，返回锁定提示，未锁定时返回空
+func (s *LoginThrottleService) Fail(ctx context.Context, ip, userName string) string {
+	cache := connector.GetCache()
+	window := s.window()
+	now := time.Now().UnixMilli()
+	member, _ := uuid.CreateId()
+	var lockMsg string
+	var lockTime time.Duration
+	for _, scope := range s.scopes(ip, userName) {
+		count, err := failScript.Run(ctx, cache, []string{redis_key.LoginFailKey + scope.key}, now, window.Milliseconds(), member).Int()
+		if err != nil {
+			logger.Module("login").WarnContext(ctx, "record login failure", "scope", scope.key, "error", err)
+			continue
+		}
+		if count < scope.maxRetry || scope.delay {
+			continue
+		}
+		duration, err := s.lock(ctx, scope)
+		if err != nil {
+			logger.Module("login").ErrorContext(ctx, "lock login", "scope", scope.key, "error", err)
+			continue
+		}
+		logger.Module("login").WarnContext(ctx, "login locked", "scope", scope.key, "failures", count, "lock_time", duration.String())
+		if duration > lockTime {
+			lockTime = duration
+			lockMsg = "登录失败次数过多，" + scope.name + "已锁定" + s.minutes(duration) + "分钟"
+		}
+	}
+	return lockMsg
+}
+
+// Succeed 登录成功，清除账号及ip加账号维度的失败记录，ip维度保留以限制对多个账号的尝试
+func (s *LoginThrottleService) Succeed(ctx context.Context, ip, userName string) {
+	connector.GetCache().Del(ctx,
+		redis_key.LoginFailKey+"user:"+userName,
+		redis_key.LoginFailKey+"ipuser:"+userName+":"+ip,
+		redis_key.LoginBackoffKey+"ipuser:"+userName+":"+ip,
+	)
+}
+
+// UnlockUser 解锁账号，清除账号的失败记录，同时解锁该账号在各ip下的锁定
+func (s *LoginThrottleService) UnlockUser(ctx context.Context, userName string) error {
+	keys := s.scanKeys(ctx, "ipuser:"+s.escape(userName)+":*")
+	for _, prefix := range []string{redis_key.LoginFailKey, redis_key.LoginLockKey, redis_key.LoginBackoffKey} {
+		keys = append(keys, prefix+"user:"+userName)
+	}
+	return connector.GetCache().Del(ctx, keys...).Err()
+}
+
+// UnlockIp 解锁ip，同时解锁该ip下各账号的锁定
+func (s *LoginThrottleService) UnlockIp(ctx context.Context, ip string) error {
+	keys := s.scanKeys(ctx, "ipuser:*:"+s.escape(ip))
+	for _, prefix := range []string{redis_key.LoginFailKey, redis_key.LoginLockKey, redis_key.LoginBackoffKey} {
+		keys = append(keys, prefix+"ip:"+ip)
+	}
+	return connector.GetCache().Del(ctx, keys...).Err()
+}
+
+// lock 锁定维度并清空失败记录，锁定时间为首次锁定时间乘以2的已锁定次数次方，不超过最长锁定时间
+func (s *LoginThrottleService) lock(ctx context.Context, scope throttleScope) (time.Duration, error) {
+	cache := connector.GetCache()
+	maxLockTime := time.Minute * time.Duration(config.GetSetting().Auth.Throttle.MaxLockTime)
+	if maxLockTime <= 0 {
+		maxLockTime = time.Minute * 1440
+	}
+	level, err := cache.Incr(ctx, redis_key.LoginBackoffKey+scope.key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// 锁定次数在最长锁定时间的2倍内未再次锁定时重新计算
+	cache.Expire(ctx, redis_key.LoginBackoffKey+scope.key, maxLockTime*2)
+	lockTime := time.Minute * time.Duration(config.GetSetting().Auth.Password.LockTime)
+	if lockTime <= 0 {
+		lockTime = time.Minute * 10
+	}
+	duration := time.Duration(float64(lockTime) * math.Pow(2, float64(level-1)))
+	if duration > maxLockTime || duration <= 0 {
+		duration = maxLockTime
+	}
+	if _, err = cache.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redis_key.LoginLockKey+scope.key, level, duration)
+		pipe.Del(ctx, redis_key.LoginFailKey+scope.key)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// delay 账号失败次数达到上限后延迟响应，每多失败一次延迟时间翻倍，不超过最长延迟时间，等待期间请求取消时返回错误
+func (s *LoginThrottleService) delay(ctx context.Context, scope throttleScope) error {
+	over := s.count(ctx, scope) - int64(scope.maxRetry)
+	if over < 0 {
+		return nil
+	}
+	duration := time.Duration(float64(loginDelay) * math.Pow(2, float64(over)))
+	if duration > loginMaxDelay || duration <= 0 {
+		duration = loginMaxDelay
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// count 维度在窗口内的失败次数
+func (s *LoginThrottleService) count(ctx context.Context, scope throttleScope) int64 {
+	since := strconv.FormatInt(time.Now().Add(-s.window()).UnixMilli(), 10)
+	return connector.GetCache().ZCount(ctx, redis_key.LoginFailKey+scope.key, since, "+inf").Val()
+}
+
+// scopes 限流维度，未配置的上限使用默认值
+func (s *LoginThrottleService) scopes(ip, userName string) []throttleScope {
+	setting := config.GetSetting().Auth
+	ipUserMaxRetry := setting.Password.MaxRetryCount
+	if ipUserMaxRetry <= 0 {
+		ipUserMaxRetry = 5
+	}
+	ipMaxRetry := setting.Throttle.IpMaxRetry
+	if ipMaxRetry <= 0 {
+		ipMaxRetry = 30
+	}
+	userMaxRetry := setting.Throttle.UserMaxRetry
+	if userMaxRetry <= 0 {
+		userMaxRetry = 20
+	}
+	scopes := []throttleScope{
+		{key: "ip:" + ip, name: "当前IP", maxRetry: ipMaxRetry},
+	}
+	if userName != "" {
+		scopes = append(scopes,
+			throttleScope{key: "user:" + userName, name: "账号", maxRetry: userMaxRetry, delay: true},
+			throttleScope{key: "ipuser:" + userName + ":" + ip, name: "账号在当前IP", maxRetry: ipUserMaxRetry},
+		)
+	}
+	return scopes
+}
+
+// scanKeys 查找匹配的失败记录、锁定及锁定次数键名
+func (s *LoginThrottleService) scanKeys(ctx context.Context, pattern string) []string {
+	cache := connector.GetCache()
+	keys := make([]string, 0)
+	for _, prefix := range []string{redis_key.LoginFailKey, redis_key.LoginLockKey, redis_key.LoginBackoffKey} {
+		iter := cache.Scan(ctx, 0, prefix+pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+	}
+	return keys
+}
+
+// escape 转义键名匹配模式中的特殊字符
+func (s *LoginThrottleService) escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(value)
+}
+
+func (s *LoginThrottleService) window() time.Duration {
+	if window := config.GetSetting().Auth.Throttle.Window; window > 0 {
+		return time.Minute * time.Duration(window)
+	}
+	return time.Minute * 15
+}
+
+// minutes 剩余时间向上取整为分钟
+func (s *LoginThrottleService) minutes(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Minutes())))
+}
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"errors"
 	"github.com/hugo8680/goat/common/constant/menu_key"
 	"github.com/hugo8680/goat/framework/connector"
@@ -13,11 +14,11 @@ type MenuService struct {
 }
 
 // Create 新增菜单
-func (s *MenuService) Create(param dto.SaveMenuRequest) error {
-	if menu := s.GetByMenuName(param.MenuName); menu.MenuId > 0 {
+func (s *MenuService) Create(ctx context.Context, param dto.SaveMenuRequest) error {
+	if menu := s.GetByMenuName(ctx, param.MenuName); menu.MenuId > 0 {
 		return errors.New("新增菜单" + param.MenuName + "失败，菜单名称已存在")
 	}
-	return connector.GetDB().Model(model.SysMenu{}).Create(&model.SysMenu{
+	return connector.DB(ctx).Model(model.SysMenu{}).Create(&model.SysMenu{
 		MenuName:  param.MenuName,
 		ParentId:  param.ParentId,
 		OrderNum:  param.OrderNum,
@@ -38,11 +39,11 @@ func (s *MenuService) Create(param dto.SaveMenuRequest) error {
 }
 
 // Update 更新菜单
-func (s *MenuService) Update(param dto.SaveMenuRequest) error {
-	if menu := s.GetByMenuName(param.MenuName); menu.MenuId > 0 && menu.MenuId != param.MenuId {
+func (s *MenuService) Update(ctx context.Context, param dto.SaveMenuRequest) error {
+	if menu := s.GetByMenuName(ctx, param.MenuName); menu.MenuId > 0 && menu.MenuId != param.MenuId {
 		return errors.New("修改菜单" + param.MenuName + "失败，菜单名称已存在")
 	}
-	return connector.GetDB().Model(model.SysMenu{}).Where("menu_id = ?", param.MenuId).Updates(&model.SysMenu{
+	if err := connector.DB(ctx).Model(model.SysMenu{}).Where("menu_id = ?", param.MenuId).Updates(&model.SysMenu{
 		MenuName:  param.MenuName,
 		ParentId:  param.ParentId,
 		OrderNum:  param.OrderNum,
@@ -59,24 +60,29 @@ func (s *MenuService) Update(param dto.SaveMenuRequest) error {
 		Status:    param.Status,
 		UpdateBy:  param.UpdateBy,
 		Remark:    param.Remark,
-	}).Error
+	}).Error; err != nil {
+		return err
+	}
+	// 菜单的权限标识或状态可能变更，清除拥有该菜单的用户的权限缓存
+	(&PermissionService{}).EvictByMenuId(ctx, param.MenuId)
+	return nil
 }
 
 // Delete 删除菜单
-func (s *MenuService) Delete(menuId int) error {
-	if s.HasChildren(menuId) {
+func (s *MenuService) Delete(ctx context.Context, menuId int) error {
+	if s.HasChildren(ctx, menuId) {
 		return errors.New("存在子菜单，不允许删除")
 	}
-	if s.HasAssigned(menuId) {
+	if s.HasAssigned(ctx, menuId) {
 		return errors.New("菜单已分配，不允许删除")
 	}
-	return connector.GetDB().Model(model.SysMenu{}).Where("menu_id = ?", menuId).Delete(&model.SysMenu{}).Error
+	return connector.DB(ctx).Model(model.SysMenu{}).Where("menu_id = ?", menuId).Delete(&model.SysMenu{}).Error
 }
 
 // List 菜单列表
-func (s *MenuService) List(param dto.MenuListRequest) []dto.MenuListResponse {
+func (s *MenuService) List(ctx context.Context, param dto.MenuListRequest) []dto.MenuListResponse {
 	menus := make([]dto.MenuListResponse, 0)
-	query := connector.GetDB().Model(model.SysMenu{}).Order("sys_menu.parent_id, sys_menu.order_num, sys_menu.menu_id")
+	query := connector.DB(ctx).Model(model.SysMenu{}).Order("sys_menu.parent_id, sys_menu.order_num, sys_menu.menu_id")
 	if param.MenuName != "" {
 		query.Where("menu_name LIKE ?", "%"+param.MenuName+"%")
 	}
@@ -88,41 +94,41 @@ func (s *MenuService) List(param dto.MenuListRequest) []dto.MenuListResponse {
 }
 
 // Get 根据菜单id查询菜单
-func (s *MenuService) Get(menuId int) dto.MenuDetailResponse {
+func (s *MenuService) Get(ctx context.Context, menuId int) dto.MenuDetailResponse {
 	var menu dto.MenuDetailResponse
-	connector.GetDB().Model(model.SysMenu{}).Where("menu_id = ?", menuId).Last(&menu)
+	connector.DB(ctx).Model(model.SysMenu{}).Where("menu_id = ?", menuId).Last(&menu)
 	return menu
 }
 
 // GetByMenuName 根据菜单名称查询菜单
-func (s *MenuService) GetByMenuName(menuName string) dto.MenuDetailResponse {
+func (s *MenuService) GetByMenuName(ctx context.Context, menuName string) dto.MenuDetailResponse {
 	var menu dto.MenuDetailResponse
-	connector.GetDB().Model(model.SysMenu{}).Where("menu_name = ?", menuName).Last(&menu)
+	connector.DB(ctx).Model(model.SysMenu{}).Where("menu_name = ?", menuName).Last(&menu)
 	return menu
 }
 
 // HasChildren 查询是否存在下级菜单
-func (s *MenuService) HasChildren(menuId int) bool {
+func (s *MenuService) HasChildren(ctx context.Context, menuId int) bool {
 	var count int64
-	connector.GetDB().Model(model.SysMenu{}).Where("parent_id = ?", menuId).Count(&count)
+	connector.DB(ctx).Model(model.SysMenu{}).Where("parent_id = ?", menuId).Count(&count)
 	return count > 0
 }
 
 // HasAssigned 查询菜单是否已分配到权限
-func (s *MenuService) HasAssigned(menuId int) bool {
+func (s *MenuService) HasAssigned(ctx context.Context, menuId int) bool {
 	var count int64
-	connector.GetDB().Model(model.SysRoleMenu{}).Where("menu_id = ?", menuId).Count(&count)
+	connector.DB(ctx).Model(model.SysRoleMenu{}).Where("menu_id = ?", menuId).Count(&count)
 	return count > 0
 }
 
 // ListPermsByUserId 根据用户id查询菜单权限perms
-func (s *MenuService) ListPermsByUserId(userId int) []string {
+func (s *MenuService) ListPermsByUserId(ctx context.Context, userId int) []string {
 	perms := make([]string, 0)
 	// 超级管理员拥有所有权限
-	if userId == 1 {
+	if (&UserService{}).IsSuperAdmin(ctx, userId) {
 		perms = append(perms, "*:*:*")
 	} else {
-		connector.GetDB().Model(model.SysMenu{}).
+		connector.DB(ctx).Model(model.SysMenu{}).
 			Joins("JOIN sys_role_menu ON sys_menu.menu_id = sys_role_menu.menu_id").
 			Joins("JOIN sys_role ON sys_role_menu.role_id = sys_role.role_id").
 			Joins("JOIN sys_user_role ON sys_role.role_id = sys_user_role.role_id").
@@ -133,9 +139,9 @@ func (s *MenuService) ListPermsByUserId(userId int) []string {
 }
 
 // ListIdsByRoleId 根据角色id查询拥有的菜单id集合
-func (s *MenuService) ListIdsByRoleId(roleId int) []int {
+func (s *MenuService) ListIdsByRoleId(ctx context.Context, roleId int) []int {
 	menuIds := make([]int, 0)
-	connector.GetDB().Model(model.SysRoleMenu{}).
+	connector.DB(ctx).Model(model.SysRoleMenu{}).
 		Joins("JOIN sys_menu ON sys_menu.menu_id = sys_role_menu.menu_id").
 		Where("sys_menu.status = 0 AND sys_role_menu.role_id = ?", roleId).
 		Pluck("sys_menu.menu_id", &menuIds)
@@ -143,9 +149,9 @@ func (s *MenuService) ListIdsByRoleId(roleId int) []int {
 }
 
 // Tree 菜单下拉树列表
-func (s *MenuService) Tree() []dto.TreeResponse {
+func (s *MenuService) Tree(ctx context.Context) []dto.TreeResponse {
 	menus := make([]dto.TreeResponse, 0)
-	connector.GetDB().Model(model.SysMenu{}).Order("order_num, menu_id").
+	connector.DB(ctx).Model(model.SysMenu{}).Order("order_num, menu_id").
 		Select("menu_id as id", "menu_name as label", "parent_id").
 		Where("status = 0").
 		Find(&menus)
@@ -171,16 +177,16 @@ func (s *MenuService) RemakeTree(menus []dto.TreeResponse, parentId int) []dto.T
 // GetMCListByUserId 根据用户id查询拥有的菜单权限
 //
 // （M-目录；C-菜单；F-按钮）
-func (s *MenuService) GetMCListByUserId(userId int) []dto.MenuListResponse {
+func (s *MenuService) GetMCListByUserId(ctx context.Context, userId int) []dto.MenuListResponse {
 	menus := make([]dto.MenuListResponse, 0)
-	query := connector.GetDB().Model(model.SysMenu{}).
+	query := connector.DB(ctx).Model(model.SysMenu{}).
 		Distinct("sys_menu.*").
 		Order("sys_menu.parent_id, sys_menu.order_num").
 		Joins("LEFT JOIN sys_role_menu ON sys_menu.menu_id = sys_role_menu.menu_id").
 		Joins("LEFT JOIN sys_role ON sys_role_menu.role_id = sys_role.role_id").
 		Joins("LEFT JOIN sys_user_role ON sys_role.role_id = sys_user_role.role_id").
 		Where("sys_menu.status = 0 AND sys_menu.menu_type IN ?", []string{menu_key.MENU_TYPE_DIRECTORY, menu_key.MENU_TYPE_MENU})
-	if userId > 1 {
+	if !(&UserService{}).IsSuperAdmin(ctx, userId) {
 		query = query.Where("sys_user_role.user_id = ? AND sys_role.status = 0", userId)
 	}
 	query.Find(&menus)
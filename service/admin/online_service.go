@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/model/dto"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OnlineService struct {
+}
+
+// List 在线用户列表
+//
+// 遍历redis中的登录用户token，按登录时间倒序返回
+func (s *OnlineService) List(ctx *gin.Context, param dto.OnlineListRequest) ([]dto.OnlineListResponse, error) {
+	cache := connector.GetCache()
+	onlines := make([]dto.OnlineListResponse, 0)
+	iter := cache.Scan(ctx.Request.Context(), 0, redis_key.UserTokenKey+"*", 100).Iterator()
+	for iter.Next(ctx.Request.Context()) {
+		var user dto.UserTokenResponse
+		// token可能在遍历过程中过期，读取失败直接跳过
+		if err := cache.Get(ctx.Request.Context(), iter.Val()).Scan(&user); err != nil {
+			continue
+		}
+		if param.Ipaddr != "" && !strings.Contains(user.Ipaddr, param.Ipaddr) {
+			continue
+		}
+		if param.UserName != "" && !strings.Contains(user.UserName, param.UserName) {
+			continue
+		}
+		onlines = append(onlines, dto.OnlineListResponse{
+			TokenId:       strings.TrimPrefix(iter.Val(), redis_key.UserTokenKey),
+			UserName:      user.UserName,
+			DeptName:      user.DeptName,
+			Ipaddr:        user.Ipaddr,
+			LoginLocation: user.LoginLocation,
+			Browser:       user.Browser,
+			Os:            user.Os,
+			LoginTime:     user.LoginTime,
+		})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(onlines, func(i, j int) bool {
+		return onlines[i].LoginTime.After(onlines[j].LoginTime.Time)
+	})
+	return onlines, nil
+}
+
+// ForceLogout 强退用户
+func (s *OnlineService) ForceLogout(ctx *gin.Context, tokenId string) error {
+	return NewTokenService().Revoke(ctx.Request.Context(), tokenId)
+}
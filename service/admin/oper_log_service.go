@@ -1,18 +1,22 @@
 package admin
 
 import (
+	"context"
+	"github.com/hugo8680/goat/common/batch"
+	"github.com/hugo8680/goat/common/ip"
+	"github.com/hugo8680/goat/framework/config"
 	"github.com/hugo8680/goat/framework/connector"
 	"github.com/hugo8680/goat/model"
 	"github.com/hugo8680/goat/model/dto"
-	"log"
+	"time"
 )
 
 type OperLogService struct {
 }
 
 // Delete 删除操作日志
-func (s *OperLogService) Delete(operIds []int) error {
-	db := connector.GetDB()
+func (s *OperLogService) Delete(ctx context.Context, operIds []int) error {
+	db := connector.DB(ctx)
 	if len(operIds) > 0 {
 		return db.Model(model.SysOperLog{}).Where("oper_id IN ?", operIds).Delete(&model.SysOperLog{}).Error
 	}
@@ -21,13 +25,16 @@ func (s *OperLogService) Delete(operIds []int) error {
 }
 
 // List 操作日志列表
-func (s *OperLogService) List(param dto.OperLogListRequest, isPaging bool) ([]dto.OperLogListResponse, int) {
+func (s *OperLogService) List(ctx context.Context, param dto.OperLogListRequest, isPaging bool) ([]dto.OperLogListResponse, int) {
 	var count int64
 	operLogs := make([]dto.OperLogListResponse, 0)
-	query := connector.GetDB().Model(model.SysOperLog{}).Order(param.OrderByColumn + " " + param.OrderRule)
+	query := connector.DB(ctx).Model(model.SysOperLog{}).Order(param.OrderByColumn + " " + param.OrderRule)
 	if param.OperIp != "" {
 		query = query.Where("oper_ip LIKE ?", "%"+param.OperIp+"%")
 	}
+	if param.RequestId != "" {
+		query = query.Where("request_id = ?", param.RequestId)
+	}
 	if param.Title != "" {
 		query = query.Where("title LIKE ?", "%"+param.Title+"%")
 	}
@@ -50,31 +57,62 @@ func (s *OperLogService) List(param dto.OperLogListRequest, isPaging bool) ([]dt
 	return operLogs, int(count)
 }
 
-// Create 记录操作日志
+// 操作日志异步批量写入
+var operLogWriter = batch.New("oper_log", (&OperLogService{}).flush)
+
+// StartWriter 启动操作日志写入协程
+func (s *OperLogService) StartWriter() error {
+	return operLogWriter.Start(logQueueOptions())
+}
+
+// StopWriter 停止接收操作日志，等待队列中的日志写入完成或ctx取消
+func (s *OperLogService) StopWriter(ctx context.Context) error {
+	return operLogWriter.Close(ctx)
+}
+
+// Create 记录操作日志，写入队列后由后台协程批量入库
 func (s *OperLogService) Create(param dto.SaveOperLogRequest) error {
-	go func() {
-		err := func() error {
-			return connector.GetDB().Model(model.SysOperLog{}).Create(&model.SysOperLog{
-				Title:         param.Title,
-				BusinessType:  param.BusinessType,
-				Method:        param.Method,
-				RequestMethod: param.RequestMethod,
-				OperName:      param.OperName,
-				DeptName:      param.DeptName,
-				OperUrl:       param.OperUrl,
-				OperIp:        param.OperIp,
-				OperLocation:  param.OperLocation,
-				OperParam:     param.OperParam,
-				JsonResult:    param.JsonResult,
-				Status:        param.Status,
-				ErrorMsg:      param.ErrorMsg,
-				OperTime:      param.OperTime,
-				CostTime:      param.CostTime,
-			}).Error
-		}()
-		if err != nil {
-			log.Println(err)
-		}
-	}()
+	operLogWriter.Push(param)
 	return nil
 }
+
+// flush 批量写入操作日志，入库前查询ip归属地
+func (s *OperLogService) flush(params []dto.SaveOperLogRequest) error {
+	operLogs := make([]model.SysOperLog, 0, len(params))
+	for _, param := range params {
+		if param.OperLocation == "" {
+			param.OperLocation = ip.GetLocation(param.OperIp)
+		}
+		operLogs = append(operLogs, model.SysOperLog{
+			Title:         param.Title,
+			BusinessType:  param.BusinessType,
+			Method:        param.Method,
+			RequestMethod: param.RequestMethod,
+			OperName:      param.OperName,
+			DeptName:      param.DeptName,
+			OperUrl:       param.OperUrl,
+			OperIp:        param.OperIp,
+			OperLocation:  param.OperLocation,
+			OperParam:     param.OperParam,
+			JsonResult:    param.JsonResult,
+			Status:        param.Status,
+			ErrorMsg:      param.ErrorMsg,
+			OperTime:      param.OperTime,
+			CostTime:      param.CostTime,
+			RequestId:     param.RequestId,
+		})
+	}
+	return connector.GetDB().Model(model.SysOperLog{}).Create(&operLogs).Error
+}
+
+// logQueueOptions 操作日志及登录日志的队列配置
+func logQueueOptions() batch.Options {
+	setting := config.GetSetting().LogQueue
+	return batch.Options{
+		Size:           setting.Size,
+		BatchSize:      setting.BatchSize,
+		FlushInterval:  time.Duration(setting.FlushInterval) * time.Millisecond,
+		Workers:        setting.Workers,
+		EnqueueTimeout: time.Duration(setting.EnqueueTimeout) * time.Millisecond,
+	}
+}
@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/hugo8680/goat/common/password"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/model"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordPolicyService 密码策略，策略参数保存在参数配置中，修改后立即生效
+//
+// sys.account.pwdMinLength 最小长度
+// sys.account.pwdCharTypes 必须包含的字符类型
+// sys.account.pwdBannedWords 禁用词
+// sys.account.pwdHistory 不能重复使用的最近密码数
+// sys.account.pwdMaxAge 密码有效天数
+// sys.account.initPwdModify 初始密码是否须在登录后修改
+type PasswordPolicyService struct {
+}
+
+// Policy 获取密码策略
+func (s *PasswordPolicyService) Policy(ctx context.Context) password.Policy {
+	configService := &ConfigService{}
+	minLength, _ := strconv.Atoi(configService.GetCacheByConfigKey(ctx, "sys.account.pwdMinLength").ConfigValue)
+	return password.Policy{
+		MinLength:   minLength,
+		CharTypes:   s.split(configService.GetCacheByConfigKey(ctx, "sys.account.pwdCharTypes").ConfigValue),
+		BannedWords: s.split(configService.GetCacheByConfigKey(ctx, "sys.account.pwdBannedWords").ConfigValue),
+	}
+}
+
+// Check 校验明文密码是否满足密码策略，userId大于0时同时校验不能与最近使用过的密码相同
+func (s *PasswordPolicyService) Check(ctx context.Context, userId int, userName, plain string) error {
+	if err := s.Policy(ctx).Check(userName, plain); err != nil {
+		return err
+	}
+	if userId <= 0 {
+		return nil
+	}
+	count := s.historyCount(ctx)
+	if count <= 0 {
+		return nil
+	}
+	hashes := make([]string, 0)
+	connector.DB(ctx).Model(model.SysPasswordHistory{}).
+		Where("user_id = ?", userId).
+		Order("history_id DESC").
+		Limit(count).
+		Pluck("password", &hashes)
+	// 未记录过历史密码的用户，至少校验当前密码
+	if len(hashes) == 0 {
+		var current string
+		connector.DB(ctx).Model(model.SysUser{}).Where("user_id = ?", userId).Pluck("password", &current)
+		hashes = append(hashes, current)
+	}
+	for _, hash := range hashes {
+		if password.Verify(hash, plain) {
+			return errors.New("新密码不能与最近" + strconv.Itoa(count) + "次使用过的密码相同")
+		}
+	}
+	return nil
+}
+
+// Record 记录用户的历史密码，仅保留策略要求的数量
+func (s *PasswordPolicyService) Record(tx *gorm.DB, userId int, hash string) error {
+	count := s.historyCount(tx.Statement.Context)
+	if count <= 0 {
+		return nil
+	}
+	if err := tx.Model(model.SysPasswordHistory{}).Create(&model.SysPasswordHistory{
+		UserId:   userId,
+		Password: hash,
+	}).Error; err != nil {
+		return err
+	}
+	expiredIds := make([]int, 0)
+	tx.Model(model.SysPasswordHistory{}).
+		Where("user_id = ?", userId).
+		Order("history_id DESC").
+		Offset(count).
+		Limit(100).
+		Pluck("history_id", &expiredIds)
+	if len(expiredIds) == 0 {
+		return nil
+	}
+	return tx.Model(model.SysPasswordHistory{}).Where("history_id IN ?", expiredIds).Delete(&model.SysPasswordHistory{}).Error
+}
+
+// MustChangeInitial 管理员设置的初始密码是否须在登录后修改
+func (s *PasswordPolicyService) MustChangeInitial(ctx context.Context) bool {
+	return (&ConfigService{}).GetCacheByConfigKey(ctx, "sys.account.initPwdModify").ConfigValue == "true"
+}
+
+// ChangeRequired 用户登录后是否须修改密码，返回原因：初始密码须修改或密码已过期
+func (s *PasswordPolicyService) ChangeRequired(ctx context.Context, userId int) (bool, string) {
+	var user model.SysUser
+	connector.DB(ctx).Model(model.SysUser{}).
+		Select("user_id", "pwd_update_time", "pwd_must_change", "create_time").
+		Where("user_id = ?", userId).
+		Last(&user)
+	if user.PwdMustChange == "1" {
+		return true, "初始密码须修改后才能使用"
+	}
+	maxAge, _ := strconv.Atoi((&ConfigService{}).GetCacheByConfigKey(ctx, "sys.account.pwdMaxAge").ConfigValue)
+	if maxAge <= 0 {
+		return false, ""
+	}
+	// 从未修改过密码的用户按创建时间计算
+	updateTime := user.PwdUpdateTime.Time
+	if updateTime.IsZero() {
+		updateTime = user.CreateTime.Time
+	}
+	if time.Since(updateTime) > time.Hour*24*time.Duration(maxAge) {
+		return true, "密码已超过" + strconv.Itoa(maxAge) + "天未修改，请修改密码"
+	}
+	return false, ""
+}
+
+func (s *PasswordPolicyService) historyCount(ctx context.Context) int {
+	count, _ := strconv.Atoi((&ConfigService{}).GetCacheByConfigKey(ctx, "sys.account.pwdHistory").ConfigValue)
+	return count
+}
+
+func (s *PasswordPolicyService) split(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
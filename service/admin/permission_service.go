@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"context"
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/framework/config"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/model"
+	"github.com/hugo8680/goat/model/dto"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// PermissionService 用户权限缓存，登录时写入redis，角色、菜单及用户授权变更时按用户清除
+type PermissionService struct {
+}
+
+// Get 获取用户的权限标识、角色key及是否为超级管理员，缓存不存在时查询数据库并写入缓存
+func (s *PermissionService) Get(ctx context.Context, userId int) dto.UserPermissionResponse {
+	var permission dto.UserPermissionResponse
+	if err := connector.GetCache().Get(ctx, redis_key.UserPermissionKey+strconv.Itoa(userId)).Scan(&permission); err == nil {
+		return permission
+	}
+	return s.Load(ctx, userId)
+}
+
+// Load 查询数据库中用户的权限标识及角色key并写入缓存，缓存时间与token有效期一致
+//
+// 查询出错时不写入缓存，避免数据库短暂不可用时长时间缓存空权限
+func (s *PermissionService) Load(ctx context.Context, userId int) dto.UserPermissionResponse {
+	userService := &UserService{}
+	perms, err := userService.listPerms(ctx, userId)
+	if err != nil {
+		logger.Module("permission").ErrorContext(ctx, "load user permission", "user_id", userId, "error", err)
+		return dto.UserPermissionResponse{Perms: perms}
+	}
+	roles, err := userService.listRoleKeys(ctx, userId)
+	if err != nil {
+		logger.Module("permission").ErrorContext(ctx, "load user roles", "user_id", userId, "error", err)
+		return dto.UserPermissionResponse{Perms: perms, Roles: roles}
+	}
+	var userName string
+	if err = connector.DB(ctx).Model(model.SysUser{}).Where("user_id = ?", userId).Limit(1).Pluck("user_name", &userName).Error; err != nil {
+		logger.Module("permission").ErrorContext(ctx, "load user name", "user_id", userId, "error", err)
+		return dto.UserPermissionResponse{Perms: perms, Roles: roles}
+	}
+	permission := dto.UserPermissionResponse{
+		Perms: perms,
+		Roles: roles,
+		Admin: (&SecurityService{}).IsSuperAdminUser(userName, roles),
+	}
+	expireTime := time.Minute * time.Duration(config.GetSetting().Auth.Token.ExpireIn)
+	if err := connector.GetCache().Set(ctx, redis_key.UserPermissionKey+strconv.Itoa(userId), permission, expireTime).Err(); err != nil {
+		logger.Module("permission").WarnContext(ctx, "cache user permission", "user_id", userId, "error", err)
+	}
+	return permission
+}
+
+// WatchSuperAdmin 订阅配置变更，超级管理员配置变更时清除全部用户的权限缓存
+func (s *PermissionService) WatchSuperAdmin() error {
+	config.OnChange(func(oldSetting, newSetting *config.Setting) {
+		if reflect.DeepEqual(oldSetting.Auth.SuperAdmin, newSetting.Auth.SuperAdmin) {
+			return
+		}
+		s.EvictAll(context.Background())
+	})
+	return nil
+}
+
+// EvictAll 清除全部用户的权限缓存
+func (s *PermissionService) EvictAll(ctx context.Context) {
+	cache := connector.GetCache()
+	keys := make([]string, 0)
+	iter := cache.Scan(ctx, 0, redis_key.UserPermissionKey+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		logger.Module("permission").ErrorContext(ctx, "scan user permission", "error", err)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	if err := cache.Del(ctx, keys...).Err(); err != nil {
+		logger.Module("permission").ErrorContext(ctx, "evict all user permission", "error", err)
+	}
+}
+
+// Evict 清除用户的权限缓存
+func (s *PermissionService) Evict(ctx context.Context, userIds ...int) {
+	if len(userIds) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		keys = append(keys, redis_key.UserPermissionKey+strconv.Itoa(userId))
+	}
+	if err := connector.GetCache().Del(ctx, keys...).Err(); err != nil {
+		logger.Module("permission").ErrorContext(ctx, "evict user permission", "user_ids", userIds, "error", err)
+	}
+}
+
+// EvictByRoleIds 清除拥有角色的用户的权限缓存
+func (s *PermissionService) EvictByRoleIds(ctx context.Context, roleIds ...int) {
+	userIds := make([]int, 0)
+	connector.DB(ctx).Model(model.SysUserRole{}).Where("role_id IN ?", roleIds).Distinct().Pluck("user_id", &userIds)
+	s.Evict(ctx, userIds...)
+}
+
+// EvictByMenuId 清除拥有菜单的用户的权限缓存
+func (s *PermissionService) EvictByMenuId(ctx context.Context, menuId int) {
+	userIds := make([]int, 0)
+	connector.DB(ctx).Model(model.SysUserRole{}).
+		Joins("JOIN sys_role_menu ON sys_role_menu.role_id = sys_user_role.role_id").
+		Where("sys_role_menu.menu_id = ?", menuId).
+		Distinct().
+		Pluck("sys_user_role.user_id", &userIds)
+	s.Evict(ctx, userIds...)
+}
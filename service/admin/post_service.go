@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"errors"
 	"github.com/hugo8680/goat/framework/connector"
 	"github.com/hugo8680/goat/model"
@@ -11,14 +12,14 @@ type PostService struct {
 }
 
 // Create 创建岗位
-func (s *PostService) Create(param dto.SavePostRequest) error {
-	if post := s.GetByPostName(param.PostName); post.PostId > 0 {
+func (s *PostService) Create(ctx context.Context, param dto.SavePostRequest) error {
+	if post := s.GetByPostName(ctx, param.PostName); post.PostId > 0 {
 		return errors.New("新增岗位" + param.PostName + "失败，岗位名称已存在")
 	}
-	if post := s.GetByPostCode(param.PostCode); post.PostId > 0 {
+	if post := s.GetByPostCode(ctx, param.PostCode); post.PostId > 0 {
 		return errors.New("新增岗位" + param.PostName + "失败，岗位编码已存在")
 	}
-	return connector.GetDB().Model(model.SysPost{}).Create(&model.SysPost{
+	return connector.DB(ctx).Model(model.SysPost{}).Create(&model.SysPost{
 		PostCode: param.PostCode,
 		PostName: param.PostName,
 		PostSort: param.PostSort,
@@ -29,14 +30,14 @@ func (s *PostService) Create(param dto.SavePostRequest) error {
 }
 
 // Update 更新岗位
-func (s *PostService) Update(param dto.SavePostRequest) error {
-	if post := s.GetByPostName(param.PostName); post.PostId > 0 && post.PostId != param.PostId {
+func (s *PostService) Update(ctx context.Context, param dto.SavePostRequest) error {
+	if post := s.GetByPostName(ctx, param.PostName); post.PostId > 0 && post.PostId != param.PostId {
 		return errors.New("修改岗位" + param.PostName + "失败，岗位名称已存在")
 	}
-	if post := s.GetByPostCode(param.PostCode); post.PostId > 0 && post.PostId != param.PostId {
+	if post := s.GetByPostCode(ctx, param.PostCode); post.PostId > 0 && post.PostId != param.PostId {
 		return errors.New("修改岗位" + param.PostName + "失败，岗位编码已存在")
 	}
-	return connector.GetDB().Model(model.SysPost{}).Where("post_id = ?", param.PostId).Updates(&model.SysPost{
+	return connector.DB(ctx).Model(model.SysPost{}).Where("post_id = ?", param.PostId).Updates(&model.SysPost{
 		PostCode: param.PostCode,
 		PostName: param.PostName,
 		PostSort: param.PostSort,
@@ -47,15 +48,15 @@ func (s *PostService) Update(param dto.SavePostRequest) error {
 }
 
 // Delete 删除岗位
-func (s *PostService) Delete(postIds []int) error {
-	return connector.GetDB().Model(model.SysPost{}).Where("post_id IN ?", postIds).Delete(&model.SysPost{}).Error
+func (s *PostService) Delete(ctx context.Context, postIds []int) error {
+	return connector.DB(ctx).Model(model.SysPost{}).Where("post_id IN ?", postIds).Delete(&model.SysPost{}).Error
 }
 
 // List 岗位列表
-func (s *PostService) List(param dto.PostListRequest, isPaging bool) ([]dto.PostListResponse, int) {
+func (s *PostService) List(ctx context.Context, param dto.PostListRequest, isPaging bool) ([]dto.PostListResponse, int) {
 	var count int64
 	posts := make([]dto.PostListResponse, 0)
-	query := connector.GetDB().Model(model.SysPost{}).Order("post_sort, post_id")
+	query := connector.DB(ctx).Model(model.SysPost{}).Order("post_sort, post_id")
 	if param.PostCode != "" {
 		query.Where("post_code LIKE ?", "%"+param.PostCode+"%")
 	}
@@ -73,30 +74,30 @@ func (s *PostService) List(param dto.PostListRequest, isPaging bool) ([]dto.Post
 }
 
 // Get 根据岗位id获取岗位详情
-func (s *PostService) Get(postId int) dto.PostDetailResponse {
+func (s *PostService) Get(ctx context.Context, postId int) dto.PostDetailResponse {
 	var post dto.PostDetailResponse
-	connector.GetDB().Model(model.SysPost{}).Where("post_id = ?", postId).Last(&post)
+	connector.DB(ctx).Model(model.SysPost{}).Where("post_id = ?", postId).Last(&post)
 	return post
 }
 
 // GetByPostName 根据岗位名称获取岗位详情
-func (s *PostService) GetByPostName(postName string) dto.PostDetailResponse {
+func (s *PostService) GetByPostName(ctx context.Context, postName string) dto.PostDetailResponse {
 	var post dto.PostDetailResponse
-	connector.GetDB().Model(model.SysPost{}).Where("post_name = ?", postName).Last(&post)
+	connector.DB(ctx).Model(model.SysPost{}).Where("post_name = ?", postName).Last(&post)
 	return post
 }
 
 // GetByPostCode 根据岗位编码获取岗位详情
-func (s *PostService) GetByPostCode(postCode string) dto.PostDetailResponse {
+func (s *PostService) GetByPostCode(ctx context.Context, postCode string) dto.PostDetailResponse {
 	var post dto.PostDetailResponse
-	connector.GetDB().Model(model.SysPost{}).Where("post_code = ?", postCode).Last(&post)
+	connector.DB(ctx).Model(model.SysPost{}).Where("post_code = ?", postCode).Last(&post)
 	return post
 }
 
 // ListIdsByUserId 根据用户id查询岗位id集合
-func (s *PostService) ListIdsByUserId(userId int) []int {
+func (s *PostService) ListIdsByUserId(ctx context.Context, userId int) []int {
 	var postIds []int
-	connector.GetDB().Model(model.SysPost{}).
+	connector.DB(ctx).Model(model.SysPost{}).
 		Joins("JOIN sys_user_post ON sys_user_post.post_id = sys_post.post_id").
 		Where("sys_user_post.user_id = ? AND sys_post.status = 0", userId).
 		Pluck("sys_post.post_id", &postIds)
@@ -104,9 +105,9 @@ func (s *PostService) ListIdsByUserId(userId int) []int {
 }
 
 // ListNamesByUserId 根据用户id查询角色名
-func (s *PostService) ListNamesByUserId(userId int) []string {
+func (s *PostService) ListNamesByUserId(ctx context.Context, userId int) []string {
 	var postNames []string
-	connector.GetDB().Model(model.SysPost{}).
+	connector.DB(ctx).Model(model.SysPost{}).
 		Joins("JOIN sys_user_post ON sys_user_post.post_id = sys_post.post_id").
 		Where("sys_user_post.user_id = ? AND sys_post.status = 0", userId).
 		Pluck("sys_post.post_name", &postNames)
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"errors"
 	"github.com/hugo8680/goat/framework/connector"
 	"github.com/hugo8680/goat/model"
@@ -11,12 +12,12 @@ type RoleService struct {
 }
 
 // Create 新增角色
-func (s *RoleService) Create(param dto.SaveRoleRequest, menuIds []int) error {
-	db := connector.GetDB()
-	if role := s.GetByRoleName(param.RoleName); role.RoleId > 0 {
+func (s *RoleService) Create(ctx context.Context, param dto.SaveRoleRequest, menuIds []int) error {
+	db := connector.DB(ctx)
+	if role := s.GetByRoleName(ctx, param.RoleName); role.RoleId > 0 {
 		return errors.New("新增角色" + param.RoleName + "失败，角色名已存在")
 	}
-	if role := s.GetByRoleKey(param.RoleKey); role.RoleId > 0 {
+	if role := s.GetByRoleKey(ctx, param.RoleKey); role.RoleId > 0 {
 		return errors.New("新增角色" + param.RoleName + "失败，权限字符已存在")
 	}
 	tx := db.Begin()
@@ -49,14 +50,17 @@ func (s *RoleService) Create(param dto.SaveRoleRequest, menuIds []int) error {
 }
 
 // Update 更新角色
-func (s *RoleService) Update(param dto.SaveRoleRequest, menuIds, deptIds []int) error {
-	db := connector.GetDB()
-	if role := s.GetByRoleName(param.RoleName); role.RoleId > 0 && role.RoleId != param.RoleId {
+func (s *RoleService) Update(ctx context.Context, param dto.SaveRoleRequest, menuIds, deptIds []int) error {
+	db := connector.DB(ctx)
+	if role := s.GetByRoleName(ctx, param.RoleName); role.RoleId > 0 && role.RoleId != param.RoleId {
 		return errors.New("修改角色" + param.RoleName + "失败，角色名已存在")
 	}
-	if role := s.GetByRoleKey(param.RoleKey); role.RoleId > 0 && role.RoleId != param.RoleId {
+	if role := s.GetByRoleKey(ctx, param.RoleKey); role.RoleId > 0 && role.RoleId != param.RoleId {
 		return errors.New("修改角色" + param.RoleName + "失败，权限字符已存在")
 	}
+	if err := s.checkSuperAdminRole(ctx, param); err != nil {
+		return err
+	}
 	tx := db.Begin()
 	if err := tx.Model(model.SysRole{}).Where("role_id = ?", param.RoleId).Updates(&model.SysRole{
 		RoleName:          param.RoleName,
@@ -106,12 +110,22 @@ func (s *RoleService) Update(param dto.SaveRoleRequest, menuIds, deptIds []int)
 			}
 		}
 	}
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	// 角色的菜单或状态可能变更，清除拥有该角色的用户的权限缓存
+	(&PermissionService{}).EvictByRoleIds(ctx, param.RoleId)
+	return nil
 }
 
 // Delete 删除角色
-func (s *RoleService) Delete(roleIds []int) error {
-	db := connector.GetDB()
+func (s *RoleService) Delete(ctx context.Context, roleIds []int) error {
+	var count int64
+	connector.DB(ctx).Model(model.SysRole{}).Where("role_id IN ? AND role_key IN ?", roleIds, (&SecurityService{}).SuperAdminRoles()).Count(&count)
+	if count > 0 {
+		return errors.New("超级管理员角色无法删除")
+	}
+	db := connector.DB(ctx)
 	tx := db.Begin()
 	if err := tx.Model(model.SysRole{}).Where("role_id IN ?", roleIds).Delete(&model.SysRole{}).Error; err != nil {
 		tx.Rollback()
@@ -125,14 +139,18 @@ func (s *RoleService) Delete(roleIds []int) error {
 		tx.Rollback()
 		return err
 	}
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	(&PermissionService{}).EvictByRoleIds(ctx, roleIds...)
+	return nil
 }
 
 // List 获取角色列表
-func (s *RoleService) List(param dto.RoleListRequest, isPaging bool) ([]dto.RoleListResponse, int) {
+func (s *RoleService) List(ctx context.Context, param dto.RoleListRequest, isPaging bool) ([]dto.RoleListResponse, int) {
 	var count int64
 	roles := make([]dto.RoleListResponse, 0)
-	query := connector.GetDB().Model(model.SysRole{}).Order("role_sort, role_id")
+	query := connector.DB(ctx).Model(model.SysRole{}).Order("role_sort, role_id")
 	if param.RoleName != "" {
 		query.Where("role_name LIKE ?", "%"+param.RoleName+"%")
 	}
@@ -153,15 +171,15 @@ func (s *RoleService) List(param dto.RoleListRequest, isPaging bool) ([]dto.Role
 }
 
 // Get 获取角色详情
-func (s *RoleService) Get(roleId int) dto.RoleDetailResponse {
+func (s *RoleService) Get(ctx context.Context, roleId int) dto.RoleDetailResponse {
 	var role dto.RoleDetailResponse
-	connector.GetDB().Model(model.SysRole{}).Where("role_id = ?", roleId).Last(&role)
+	connector.DB(ctx).Model(model.SysRole{}).Where("role_id = ?", roleId).Last(&role)
 	return role
 }
 
 // AuthUsers 批量授权用户
-func (s *RoleService) AuthUsers(roleId int, userIds []int) error {
-	db := connector.GetDB()
+func (s *RoleService) AuthUsers(ctx context.Context, roleId int, userIds []int) error {
+	db := connector.DB(ctx)
 	tx := db.Begin()
 	for _, userId := range userIds {
 		if err := tx.Model(model.SysUserRole{}).Create(&model.SysUserRole{
@@ -172,18 +190,29 @@ func (s *RoleService) AuthUsers(roleId int, userIds []int) error {
 			return err
 		}
 	}
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	(&PermissionService{}).Evict(ctx, userIds...)
+	return nil
 }
 
 // UnAuthUsers 批量取消授权用户
-func (s *RoleService) UnAuthUsers(roleId int, userIds []int) error {
-	return connector.GetDB().Model(model.SysUserRole{}).Where("role_id = ? AND user_id in ?", roleId, userIds).Delete(&model.SysUserRole{}).Error
+func (s *RoleService) UnAuthUsers(ctx context.Context, roleId int, userIds []int) error {
+	if err := s.checkLastSuperAdmin(ctx, roleId, userIds, "取消授权"); err != nil {
+		return err
+	}
+	if err := connector.DB(ctx).Model(model.SysUserRole{}).Where("role_id = ? AND user_id in ?", roleId, userIds).Delete(&model.SysUserRole{}).Error; err != nil {
+		return err
+	}
+	(&PermissionService{}).Evict(ctx, userIds...)
+	return nil
 }
 
 // ListByUserId 根据用户id查询角色列表
-func (s *RoleService) ListByUserId(userId int) []dto.RoleListResponse {
+func (s *RoleService) ListByUserId(ctx context.Context, userId int) []dto.RoleListResponse {
 	roles := make([]dto.RoleListResponse, 0)
-	connector.GetDB().Model(model.SysRole{}).Select("sys_role.*").
+	connector.DB(ctx).Model(model.SysRole{}).Select("sys_role.*").
 		Joins("JOIN sys_user_role ON sys_role.role_id = sys_user_role.role_id").
 		Where("sys_user_role.user_id = ? AND sys_role.status = 0", userId).
 		Find(&roles)
@@ -191,9 +220,9 @@ func (s *RoleService) ListByUserId(userId int) []dto.RoleListResponse {
 }
 
 // ListKeyByUserId 根据用户id查询角色key
-func (s *RoleService) ListKeyByUserId(userId int) []string {
+func (s *RoleService) ListKeyByUserId(ctx context.Context, userId int) []string {
 	roleKeys := make([]string, 0)
-	connector.GetDB().Model(model.SysRole{}).
+	connector.DB(ctx).Model(model.SysRole{}).
 		Joins("JOIN sys_user_role ON sys_user_role.role_id = sys_role.role_id").
 		Where("sys_user_role.user_id = ? AND sys_role.status = 0", userId).
 		Pluck("sys_role.role_key", &roleKeys)
@@ -201,9 +230,9 @@ func (s *RoleService) ListKeyByUserId(userId int) []string {
 }
 
 // ListNameByUserId 根据用户id查询角色名
-func (s *RoleService) ListNameByUserId(userId int) []string {
+func (s *RoleService) ListNameByUserId(ctx context.Context, userId int) []string {
 	var roleNames []string
-	connector.GetDB().Model(model.SysRole{}).
+	connector.DB(ctx).Model(model.SysRole{}).
 		Joins("JOIN sys_user_role ON sys_user_role.role_id = sys_role.role_id").
 		Where("sys_user_role.user_id = ? AND sys_role.status = 0", userId).
 		Pluck("sys_role.role_name", &roleNames)
@@ -211,15 +240,56 @@ func (s *RoleService) ListNameByUserId(userId int) []string {
 }
 
 // GetByRoleName 根据角色名称查询角色
-func (s *RoleService) GetByRoleName(roleName string) dto.RoleDetailResponse {
+func (s *RoleService) GetByRoleName(ctx context.Context, roleName string) dto.RoleDetailResponse {
 	var role dto.RoleDetailResponse
-	connector.GetDB().Model(model.SysRole{}).Where("role_name = ?", roleName).Last(&role)
+	connector.DB(ctx).Model(model.SysRole{}).Where("role_name = ?", roleName).Last(&role)
 	return role
 }
 
 // GetByRoleKey 根据角色key称查询角色
-func (s *RoleService) GetByRoleKey(roleKey string) dto.RoleDetailResponse {
+func (s *RoleService) GetByRoleKey(ctx context.Context, roleKey string) dto.RoleDetailResponse {
 	var role dto.RoleDetailResponse
-	connector.GetDB().Model(model.SysRole{}).Where("role_key = ?", roleKey).Last(&role)
+	connector.DB(ctx).Model(model.SysRole{}).Where("role_key = ?", roleKey).Last(&role)
 	return role
 }
+
+// checkSuperAdminRole 停用超级管理员角色或修改其权限字符时，校验是否还有其他可用的超级管理员
+func (s *RoleService) checkSuperAdminRole(ctx context.Context, param dto.SaveRoleRequest) error {
+	securityService := &SecurityService{}
+	role := s.Get(ctx, param.RoleId)
+	if role.Status != "0" || !securityService.IsSuperAdminRole(role.RoleKey) {
+		return nil
+	}
+	// 空值不会更新，保持原值
+	if param.Status != "" && param.Status != "0" {
+		return s.checkLastSuperAdmin(ctx, param.RoleId, nil, "通过停用角色移除")
+	}
+	if param.RoleKey != "" && !securityService.IsSuperAdminRole(param.RoleKey) {
+		return s.checkLastSuperAdmin(ctx, param.RoleId, nil, "通过修改权限字符移除")
+	}
+	return nil
+}
+
+// checkLastSuperAdmin 角色下的用户失去该角色后，校验是否还有其他可用的超级管理员，userIds为nil时为角色下的全部用户
+func (s *RoleService) checkLastSuperAdmin(ctx context.Context, roleId int, userIds []int, action string) error {
+	query := connector.DB(ctx).Model(model.SysUserRole{}).Where("role_id = ?", roleId)
+	if userIds != nil {
+		query = query.Where("user_id IN ?", userIds)
+	}
+	affectedIds := make([]int, 0)
+	if err := query.Pluck("user_id", &affectedIds).Error; err != nil {
+		return err
+	}
+	userService := &UserService{}
+	lostIds := make([]int, 0)
+	for _, userId := range affectedIds {
+		roleIds := make([]int, 0)
+		if err := connector.DB(ctx).Model(model.SysUserRole{}).Where("user_id = ? AND role_id != ?", userId, roleId).Pluck("role_id", &roleIds).Error; err != nil {
+			return err
+		}
+		if !userService.keepsSuperAdmin(ctx, userId, roleIds) {
+			lostIds = append(lostIds, userId)
+		}
+	}
+	return userService.checkLastSuperAdmin(ctx, lostIds, action)
+}
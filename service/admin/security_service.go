@@ -1,7 +1,12 @@
 package admin
 
 import (
+	"context"
 	"errors"
+	"github.com/hugo8680/goat/common/constant/auth"
+	"github.com/hugo8680/goat/common/permission"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/framework/config"
 	"github.com/hugo8680/goat/model/dto"
 
 	"github.com/gin-gonic/gin"
@@ -12,45 +17,36 @@ type SecurityService struct {
 
 // GetCurrentUserId 获取当前用户id
 func (s *SecurityService) GetCurrentUserId(ctx *gin.Context) (int, error) {
-	tokenService := NewTokenService()
-	authUser, err := tokenService.Parse(ctx)
+	authUser, err := s.GetCurrentUser(ctx)
 	if err != nil {
 		return 0, err
 	}
-	if authUser == nil {
-		return 0, errors.New("用户不存在")
-	}
 	return authUser.UserId, nil
 }
 
 // GetCurrentUserDeptId 获取当前用户部门id
 func (s *SecurityService) GetCurrentUserDeptId(ctx *gin.Context) (int, error) {
-	tokenService := NewTokenService()
-	authUser, err := tokenService.Parse(ctx)
+	authUser, err := s.GetCurrentUser(ctx)
 	if err != nil {
 		return 0, err
 	}
-	if authUser == nil {
-		return 0, errors.New("用户不存在")
-	}
 	return authUser.DeptId, nil
 }
 
 // GetCurrentUserName 获取当前账户名
 func (s *SecurityService) GetCurrentUserName(ctx *gin.Context) (string, error) {
-	tokenService := NewTokenService()
-	authUser, err := tokenService.Parse(ctx)
+	authUser, err := s.GetCurrentUser(ctx)
 	if err != nil {
 		return "", err
 	}
-	if authUser == nil {
-		return "", errors.New("用户不存在")
-	}
 	return authUser.UserName, nil
 }
 
-// GetCurrentUser 获取当前账户
+// GetCurrentUser 获取当前账户，认证中间件已解析过token时直接从请求上下文中读取
 func (s *SecurityService) GetCurrentUser(ctx *gin.Context) (*dto.UserTokenResponse, error) {
+	if authUser, ok := ctx.Get(auth.CONTEXT_USER_KEY); ok {
+		return authUser.(*dto.UserTokenResponse), nil
+	}
 	tokenService := NewTokenService()
 	authUser, err := tokenService.Parse(ctx)
 	if err != nil {
@@ -62,32 +58,100 @@ func (s *SecurityService) GetCurrentUser(ctx *gin.Context) (*dto.UserTokenRespon
 	return authUser, nil
 }
 
-// HasPerm 验证用户是否具备某权限
+// Check 验证用户是否满足权限表达式，权限及角色从缓存读取，超级管理员始终通过
+func (s *SecurityService) Check(ctx context.Context, userId int, expression permission.Expression) bool {
+	userPermission := (&PermissionService{}).Get(ctx, userId)
+	if userPermission.Admin {
+		return true
+	}
+	return expression.Eval(&userSubject{
+		UserPermissionResponse: userPermission,
+	})
+}
+
+// IsSuperAdmin 验证用户是否为超级管理员，从缓存读取
+func (s *SecurityService) IsSuperAdmin(ctx context.Context, userId int) bool {
+	return (&PermissionService{}).Get(ctx, userId).Admin
+}
+
+// IsSuperAdminUser 根据用户名及角色key验证是否为超级管理员
+func (s *SecurityService) IsSuperAdminUser(userName string, roleKeys []string) bool {
+	if userName != "" && utils.Contains(config.GetSetting().Auth.SuperAdmin.Users, userName) {
+		return true
+	}
+	for _, roleKey := range roleKeys {
+		if s.IsSuperAdminRole(roleKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSuperAdminRole 验证角色是否为超级管理员角色
+func (s *SecurityService) IsSuperAdminRole(roleKey string) bool {
+	return utils.Contains(s.SuperAdminRoles(), roleKey)
+}
+
+// SuperAdminRoles 超级管理员角色key，未配置超级管理员用户及角色时默认为admin
+func (s *SecurityService) SuperAdminRoles() []string {
+	superAdmin := config.GetSetting().Auth.SuperAdmin
+	if len(superAdmin.Roles) == 0 && len(superAdmin.Users) == 0 {
+		return []string{"admin"}
+	}
+	return superAdmin.Roles
+}
+
+// HasPerm 验证用户是否具备某权限，支持通配符
 func (s *SecurityService) HasPerm(userId int, perm string) bool {
-	return (&UserService{}).HasPerms(userId, []string{perm})
+	return permission.MatchAny((&PermissionService{}).Get(context.Background(), userId).Perms, perm)
 }
 
 // LackPerm 验证用户是否不具备某权限
 func (s *SecurityService) LackPerm(userId int, perm string) bool {
-	return !(&UserService{}).HasPerms(userId, []string{perm})
+	return !s.HasPerm(userId, perm)
 }
 
 // HasAnyPerms 验证用户是否具有以下任意一个权限
 func (s *SecurityService) HasAnyPerms(userId int, perms []string) bool {
-	return (&UserService{}).HasPerms(userId, perms)
+	granted := (&PermissionService{}).Get(context.Background(), userId).Perms
+	for _, perm := range perms {
+		if permission.MatchAny(granted, perm) {
+			return true
+		}
+	}
+	return false
 }
 
 // HasRole 验证用户是否拥有某个角色
 func (s *SecurityService) HasRole(userId int, roleKey string) bool {
-	return (&UserService{}).HasRoles(userId, []string{roleKey})
+	return utils.Contains((&PermissionService{}).Get(context.Background(), userId).Roles, roleKey)
 }
 
 // LackRole 验证用户是否不具备某个角色
 func (s *SecurityService) LackRole(userId int, roleKey string) bool {
-	return !(&UserService{}).HasRoles(userId, []string{roleKey})
+	return !s.HasRole(userId, roleKey)
 }
 
 // HasAnyRoles 验证用户是否具有以下任意一个角色
 func (s *SecurityService) HasAnyRoles(userId int, roleKey []string) bool {
-	return (&UserService{}).HasRoles(userId, roleKey)
+	roles := (&PermissionService{}).Get(context.Background(), userId).Roles
+	for _, key := range roleKey {
+		if utils.Contains(roles, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// userSubject 用户的权限校验对象
+type userSubject struct {
+	dto.UserPermissionResponse
+}
+
+func (u *userSubject) HasPermission(perm string) bool {
+	return permission.MatchAny(u.Perms, perm)
+}
+
+func (u *userSubject) HasRole(roleKey string) bool {
+	return utils.Contains(u.Roles, roleKey)
 }
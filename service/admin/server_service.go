@@ -0,0 +1,182 @@
+package admin
+
+import (
+	"context"
+	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/model/dto"
+	"math"
+	"net"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// CPU使用率的采样间隔
+const cpuSampleInterval = 500 * time.Millisecond
+
+// 进程启动时间
+var processStartTime = time.Now()
+
+type ServerService struct {
+}
+
+// Get 获取服务器信息
+//
+// 采集失败的指标保持零值，不影响其他指标的返回
+func (s *ServerService) Get(ctx *gin.Context) dto.ServerResponse {
+	return s.get(ctx.Request.Context())
+}
+
+func (s *ServerService) get(ctx context.Context) dto.ServerResponse {
+	return dto.ServerResponse{
+		Cpu:      s.getCpu(ctx),
+		Mem:      s.getMem(ctx),
+		Process:  s.getProcess(ctx),
+		Runtime:  s.getRuntime(),
+		Sys:      s.getSys(ctx),
+		SysFiles: s.getSysFiles(ctx),
+	}
+}
+
+func (s *ServerService) getCpu(ctx context.Context) dto.ServerCpuResponse {
+	cpuInfo := dto.ServerCpuResponse{
+		CpuNum: runtime.NumCPU(),
+	}
+	if infos, err := cpu.InfoWithContext(ctx); err == nil && len(infos) > 0 {
+		cpuInfo.ModelName = infos[0].ModelName
+	}
+	if percents, err := cpu.PercentWithContext(ctx, cpuSampleInterval, false); err == nil && len(percents) > 0 {
+		cpuInfo.Used = round(percents[0])
+		cpuInfo.Free = round(100 - percents[0])
+	}
+	return cpuInfo
+}
+
+func (s *ServerService) getMem(ctx context.Context) dto.ServerMemResponse {
+	var memInfo dto.ServerMemResponse
+	if vm, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		memInfo.Total = vm.Total
+		memInfo.Used = vm.Used
+		memInfo.Free = vm.Available
+		memInfo.Usage = round(vm.UsedPercent)
+	}
+	return memInfo
+}
+
+func (s *ServerService) getProcess(ctx context.Context) dto.ServerProcessResponse {
+	processInfo := dto.ServerProcessResponse{
+		Pid:       int32(os.Getpid()),
+		StartTime: processStartTime.Format(datetime.DATETIME_FORMAT0),
+		RunTime:   time.Since(processStartTime).Truncate(time.Second).String(),
+	}
+	p, err := process.NewProcessWithContext(ctx, processInfo.Pid)
+	if err != nil {
+		return processInfo
+	}
+	if percent, err := p.CPUPercentWithContext(ctx); err == nil {
+		processInfo.CpuPercent = round(percent)
+	}
+	if percent, err := p.MemoryPercentWithContext(ctx); err == nil {
+		processInfo.MemPercent = float32(round(float64(percent)))
+	}
+	if memInfo, err := p.MemoryInfoWithContext(ctx); err == nil {
+		processInfo.Rss = memInfo.RSS
+		processInfo.Vms = memInfo.VMS
+	}
+	if numThreads, err := p.NumThreadsWithContext(ctx); err == nil {
+		processInfo.NumThreads = numThreads
+	}
+	return processInfo
+}
+
+func (s *ServerService) getRuntime() dto.ServerRuntimeResponse {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	runtimeInfo := dto.ServerRuntimeResponse{
+		Version:       runtime.Version(),
+		Home:          runtime.GOROOT(),
+		NumCpu:        runtime.NumCPU(),
+		GoMaxProcs:    runtime.GOMAXPROCS(0),
+		NumGoroutine:  runtime.NumGoroutine(),
+		HeapAlloc:     memStats.HeapAlloc,
+		HeapSys:       memStats.HeapSys,
+		HeapInuse:     memStats.HeapInuse,
+		HeapObjects:   memStats.HeapObjects,
+		StackInuse:    memStats.StackInuse,
+		Sys:           memStats.Sys,
+		NumGC:         memStats.NumGC,
+		PauseTotalNs:  memStats.PauseTotalNs,
+		GCCPUFraction: memStats.GCCPUFraction,
+	}
+	if memStats.NumGC > 0 {
+		// PauseNs是环形缓冲区，最近一次GC的停顿时间位于(NumGC+255)%256
+		runtimeInfo.LastPauseNs = memStats.PauseNs[(memStats.NumGC+255)%256]
+		runtimeInfo.LastGC = time.Unix(0, int64(memStats.LastGC)).Format(datetime.DATETIME_FORMAT0)
+	}
+	return runtimeInfo
+}
+
+func (s *ServerService) getSys(ctx context.Context) dto.ServerSysResponse {
+	sysInfo := dto.ServerSysResponse{
+		OsName:     runtime.GOOS,
+		OsArch:     runtime.GOARCH,
+		ComputerIp: s.getLocalIp(),
+	}
+	sysInfo.ComputerName, _ = os.Hostname()
+	sysInfo.UserDir, _ = os.Getwd()
+	if hostInfo, err := host.InfoWithContext(ctx); err == nil {
+		sysInfo.Platform = hostInfo.Platform + " " + hostInfo.PlatformVersion
+		sysInfo.BootTime = time.Unix(int64(hostInfo.BootTime), 0).Format(datetime.DATETIME_FORMAT0)
+	}
+	return sysInfo
+}
+
+func (s *ServerService) getSysFiles(ctx context.Context) []dto.ServerDiskResponse {
+	sysFiles := make([]dto.ServerDiskResponse, 0)
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		return sysFiles
+	}
+	for _, partition := range partitions {
+		usage, err := disk.UsageWithContext(ctx, partition.Mountpoint)
+		if err != nil || usage.Total == 0 {
+			continue
+		}
+		sysFiles = append(sysFiles, dto.ServerDiskResponse{
+			DirName:     partition.Mountpoint,
+			SysTypeName: partition.Fstype,
+			TypeName:    partition.Device,
+			Total:       usage.Total,
+			Free:        usage.Free,
+			Used:        usage.Used,
+			Usage:       round(usage.UsedPercent),
+		})
+	}
+	return sysFiles
+}
+
+// getLocalIp 获取本机第一个非回环的IPv4地址
+func (s *ServerService) getLocalIp() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return ""
+}
+
+// round 保留两位小数
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
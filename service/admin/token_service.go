@@ -3,12 +3,16 @@ package admin
 import (
 	"context"
 	"errors"
+	"github.com/hugo8680/goat/common/constant/auth"
 	"github.com/hugo8680/goat/common/constant/redis_key"
 	"github.com/hugo8680/goat/common/serializer/datetime"
 	"github.com/hugo8680/goat/common/uuid"
 	"github.com/hugo8680/goat/framework/config"
 	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/framework/metrics"
 	"github.com/hugo8680/goat/model/dto"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,15 +21,40 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// 刷新令牌类型标识，访问令牌不设置类型
+const tokenTypeRefresh = "refresh"
+
+// rotateScript 轮换刷新令牌，令牌id与当前有效的一致时替换为新id并保留原过期时间
+//
+// 返回1轮换成功，0令牌已被使用过，-1会话不存在或已过期
+var rotateScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return -1
+end
+if current ~= ARGV[1] then
+	return 0
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl <= 0 then
+	return -1
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ttl)
+return 1
+`)
+
 // TokenService 授权声明
+//
+// Key为登录会话id，同一会话轮换刷新令牌时保持不变，撤销会话即撤销整个令牌族
 type TokenService struct {
 	jwt.RegisteredClaims
-	Key     string `json:"key"`
-	Setting *config.Setting
-	Cache   *redis.Client
+	Key     string          `json:"key"`
+	Type    string          `json:"typ,omitempty"`
+	Setting *config.Setting `json:"-"`
+	Cache   *redis.Client   `json:"-"`
 }
 
-// NewTokenService 获取授权声明
+// NewTokenService 获取授权声明，每次调用读取最新配置
 func NewTokenService() *TokenService {
 	key, _ := uuid.CreateId()
 	conf := config.GetSetting()
@@ -41,83 +70,299 @@ func NewTokenService() *TokenService {
 	}
 }
 
-// Create 生成token
-func (s *TokenService) Create(user *dto.UserTokenResponse) (string, error) {
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, s).SignedString([]byte(s.Setting.Auth.Token.Secret))
+// Mode 令牌模式，未配置时为sliding
+func (s *TokenService) Mode() string {
+	if s.Setting.Auth.Token.Mode == auth.TOKEN_MODE_REFRESH {
+		return auth.TOKEN_MODE_REFRESH
+	}
+	return auth.TOKEN_MODE_SLIDING
+}
+
+// Expire 客户端类型对应的访问令牌及刷新令牌有效期
+//
+// 未知类型按web处理，未配置时访问令牌为30分钟，刷新令牌为auth.token.expireIn
+func (s *TokenService) Expire(clientType string) (time.Duration, time.Duration) {
+	expire := s.Setting.Auth.Token.Clients.Web
+	if clientType == auth.CLIENT_TYPE_MOBILE {
+		expire = s.Setting.Auth.Token.Clients.Mobile
+	}
+	accessExpireIn, refreshExpireIn := 30, s.Setting.Auth.Token.ExpireIn
+	if expire.AccessExpireIn > 0 {
+		accessExpireIn = expire.AccessExpireIn
+	}
+	if expire.RefreshExpireIn > 0 {
+		refreshExpireIn = expire.RefreshExpireIn
+	}
+	if accessExpireIn > refreshExpireIn {
+		accessExpireIn = refreshExpireIn
+	}
+	return time.Minute * time.Duration(accessExpireIn), time.Minute * time.Duration(refreshExpireIn)
+}
+
+// Create 生成token，refresh模式下同时生成刷新令牌，并按会话策略踢出用户之前的会话
+func (s *TokenService) Create(user *dto.UserTokenResponse) (dto.TokenResponse, error) {
+	ctx := context.Background()
+	if s.Mode() == auth.TOKEN_MODE_SLIDING {
+		token, err := s.sign("", "", time.Time{})
+		if err != nil {
+			return dto.TokenResponse{}, err
+		}
+		expireTime := time.Minute * time.Duration(s.Setting.Auth.Token.ExpireIn)
+		user.TokenId = s.Key
+		user.ExpireTime = datetime.Datetime{Time: time.Now().Add(expireTime)}
+		if err = s.track(ctx, user.UserId, expireTime); err != nil {
+			return dto.TokenResponse{}, err
+		}
+		if err = s.Cache.Set(ctx, redis_key.UserTokenKey+s.Key, user, expireTime).Err(); err != nil {
+			return dto.TokenResponse{}, err
+		}
+		return dto.TokenResponse{Token: token}, nil
+	}
+	accessExpire, refreshExpire := s.Expire(user.ClientType)
+	refreshId, err := uuid.CreateId()
 	if err != nil {
-		return "", err
+		return dto.TokenResponse{}, err
 	}
+	user.TokenId = s.Key
+	user.ExpireTime = datetime.Datetime{Time: time.Now().Add(refreshExpire)}
+	if err = s.track(ctx, user.UserId, refreshExpire); err != nil {
+		return dto.TokenResponse{}, err
+	}
+	if _, err = s.Cache.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redis_key.UserTokenKey+s.Key, user, refreshExpire)
+		pipe.Set(ctx, redis_key.UserRefreshTokenKey+s.Key, refreshId, refreshExpire)
+		return nil
+	}); err != nil {
+		return dto.TokenResponse{}, err
+	}
+	return s.issue(refreshId, accessExpire, user.ExpireTime.Time)
+}
+
+// Refresh sliding模式下延长登录会话有效期
+func (s *TokenService) Refresh(ctx *gin.Context, user *dto.UserTokenResponse) {
 	expireTime := time.Minute * time.Duration(s.Setting.Auth.Token.ExpireIn)
 	user.ExpireTime = datetime.Datetime{Time: time.Now().Add(expireTime)}
-	err = s.Cache.Set(context.Background(), redis_key.UserTokenKey+s.Key, user, expireTime).Err()
-	if err != nil {
-		return "", err
+	s.Cache.Set(ctx.Request.Context(), redis_key.UserTokenKey+user.TokenId, user, expireTime)
+	indexKey := redis_key.UserSessionKey + strconv.Itoa(user.UserId)
+	if ttl := s.Cache.TTL(ctx.Request.Context(), indexKey).Val(); ttl < expireTime {
+		s.Cache.Expire(ctx.Request.Context(), indexKey, expireTime)
 	}
-	return token, nil
+	metrics.ObserveTokenRefresh()
 }
 
-// Refresh 刷新token
-func (s *TokenService) Refresh(ctx *gin.Context, user *dto.UserTokenResponse) {
-	tokenKey, err := s.getUserTokenKey(ctx)
+// Save 保存当前登录会话的用户信息，不改变会话有效期
+func (s *TokenService) Save(ctx context.Context, user *dto.UserTokenResponse) error {
+	return s.Cache.Set(ctx, redis_key.UserTokenKey+user.TokenId, user, redis.KeepTTL).Err()
+}
+
+// Rotate 使用刷新令牌换取新的访问令牌及刷新令牌，旧刷新令牌随即失效
+//
+// 已失效的刷新令牌被再次使用时视为泄露，撤销整个令牌族
+func (s *TokenService) Rotate(ctx context.Context, refreshToken string) (dto.TokenResponse, error) {
+	if s.Mode() != auth.TOKEN_MODE_REFRESH {
+		return dto.TokenResponse{}, errors.New("未启用刷新令牌")
+	}
+	claims, err := s.parse(refreshToken, true)
 	if err != nil {
-		return
+		return dto.TokenResponse{}, err
 	}
-	expireTime := time.Minute * time.Duration(s.Setting.Auth.Token.ExpireIn)
-	user.ExpireTime = datetime.Datetime{Time: time.Now().Add(expireTime)}
-	s.Cache.Set(ctx.Request.Context(), tokenKey, user, time.Minute*time.Duration(s.Setting.Auth.Token.ExpireIn))
+	if claims.Type != tokenTypeRefresh || claims.ExpiresAt == nil {
+		return dto.TokenResponse{}, errors.New("刷新令牌无效")
+	}
+	var user dto.UserTokenResponse
+	if err = s.Cache.Get(ctx, redis_key.UserTokenKey+claims.Key).Scan(&user); err != nil {
+		return dto.TokenResponse{}, errors.New("登录状态已过期，请重新登录")
+	}
+	refreshId, err := uuid.CreateId()
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+	result, err := rotateScript.Run(ctx, s.Cache, []string{redis_key.UserRefreshTokenKey + claims.Key}, claims.ID, refreshId).Int()
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+	switch result {
+	case -1:
+		return dto.TokenResponse{}, errors.New("登录状态已过期，请重新登录")
+	case 0:
+		logger.Module("token").WarnContext(ctx, "refresh token reused, revoke token family", "token_id", claims.Key, "user_id", user.UserId)
+		if err = s.Revoke(ctx, claims.Key); err != nil {
+			return dto.TokenResponse{}, err
+		}
+		return dto.TokenResponse{}, errors.New("刷新令牌已失效，请重新登录")
+	}
+	s.Key = claims.Key
+	accessExpire, _ := s.Expire(user.ClientType)
+	metrics.ObserveTokenRefresh()
+	return s.issue(refreshId, accessExpire, claims.ExpiresAt.Time)
 }
 
 // Parse 将token解析为用户信息
 func (s *TokenService) Parse(ctx *gin.Context) (*dto.UserTokenResponse, error) {
-	tokenKey, err := s.getUserTokenKey(ctx)
+	claims, err := s.parseHeader(ctx, true)
 	if err != nil {
 		return nil, err
 	}
+	if claims.Type == tokenTypeRefresh {
+		return nil, errors.New("刷新令牌不能用于访问")
+	}
 	var user dto.UserTokenResponse
 	// &user取的是dto.UserTokenResponse指针类型对应变量的地址
-	if err = s.Cache.Get(ctx.Request.Context(), tokenKey).Scan(&user); err != nil {
+	if err = s.Cache.Get(ctx.Request.Context(), redis_key.UserTokenKey+claims.Key).Scan(&user); err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-// Delete 删除token
+// Delete 删除token，撤销当前登录会话，访问令牌过期后仍可退出登录
 func (s *TokenService) Delete(ctx *gin.Context) error {
-	tokenKey, err := s.getUserTokenKey(ctx)
+	claims, err := s.parseHeader(ctx, false)
 	if err != nil {
 		return err
 	}
-	return s.Cache.Del(ctx.Request.Context(), tokenKey).Err()
+	return s.Revoke(ctx.Request.Context(), claims.Key)
 }
 
-// 获取授权用户的token key
-func (s *TokenService) getUserTokenKey(ctx *gin.Context) (string, error) {
+// Revoke 撤销登录会话及其刷新令牌族
+func (s *TokenService) Revoke(ctx context.Context, tokenId string) error {
+	var user dto.UserTokenResponse
+	if err := s.Cache.Get(ctx, redis_key.UserTokenKey+tokenId).Scan(&user); err == nil {
+		s.Cache.ZRem(ctx, redis_key.UserSessionKey+strconv.Itoa(user.UserId), tokenId)
+	}
+	return s.Cache.Del(ctx, redis_key.UserTokenKey+tokenId, redis_key.UserRefreshTokenKey+tokenId).Err()
+}
+
+// RevokeUser 撤销用户的全部登录会话，用于停用、删除用户及重置密码
+func (s *TokenService) RevokeUser(ctx context.Context, userIds ...int) error {
+	for _, userId := range userIds {
+		indexKey := redis_key.UserSessionKey + strconv.Itoa(userId)
+		tokenIds, err := s.Cache.ZRange(ctx, indexKey, 0, -1).Result()
+		if err != nil {
+			return err
+		}
+		keys := []string{indexKey}
+		for _, tokenId := range tokenIds {
+			keys = append(keys, redis_key.UserTokenKey+tokenId, redis_key.UserRefreshTokenKey+tokenId)
+		}
+		if err = s.Cache.Del(ctx, keys...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// track 将当前会话加入用户会话索引，并按会话策略踢出最早登录的会话
+//
+// 索引为有序集合，成员为会话id，分值为登录时间，已过期的会话在此时清理
+func (s *TokenService) track(ctx context.Context, userId int, expire time.Duration) error {
+	indexKey := redis_key.UserSessionKey + strconv.Itoa(userId)
+	tokenIds, err := s.Cache.ZRange(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	active := make([]string, 0, len(tokenIds))
+	for _, tokenId := range tokenIds {
+		if s.Cache.Exists(ctx, redis_key.UserTokenKey+tokenId).Val() > 0 {
+			active = append(active, tokenId)
+		} else {
+			s.Cache.ZRem(ctx, indexKey, tokenId)
+		}
+	}
+	evict := 0
+	switch s.Setting.Auth.Session.Policy {
+	case auth.SESSION_POLICY_SINGLE:
+		evict = len(active)
+	case auth.SESSION_POLICY_LIMIT:
+		evict = len(active) - s.Setting.Auth.Session.MaxCount + 1
+	}
+	for i := 0; i < evict && i < len(active); i++ {
+		if err = s.Revoke(ctx, active[i]); err != nil {
+			return err
+		}
+		logger.Module("token").InfoContext(ctx, "session evicted by policy", "user_id", userId, "token_id", active[i], "policy", s.Setting.Auth.Session.Policy)
+	}
+	if err = s.Cache.ZAdd(ctx, indexKey, &redis.Z{Score: float64(time.Now().UnixMilli()), Member: s.Key}).Err(); err != nil {
+		return err
+	}
+	// 索引有效期不短于最后登录的会话
+	if ttl := s.Cache.TTL(ctx, indexKey).Val(); ttl < expire {
+		s.Cache.Expire(ctx, indexKey, expire)
+	}
+	return nil
+}
+
+// issue 签发当前会话的访问令牌及刷新令牌，刷新令牌与会话同时过期
+func (s *TokenService) issue(refreshId string, accessExpire time.Duration, refreshExpireAt time.Time) (dto.TokenResponse, error) {
+	token, err := s.sign("", "", time.Now().Add(accessExpire))
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+	refreshToken, err := s.sign(tokenTypeRefresh, refreshId, refreshExpireAt)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+	return dto.TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(accessExpire.Seconds()),
+	}, nil
+}
+
+// sign 以当前会话签发令牌，expireAt为零值时不设置过期时间，由redis中的会话控制有效期
+func (s *TokenService) sign(tokenType, id string, expireAt time.Time) (string, error) {
+	claims := &TokenService{
+		RegisteredClaims: s.RegisteredClaims,
+		Key:              s.Key,
+		Type:             tokenType,
+	}
+	claims.ID = id
+	if !expireAt.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(expireAt)
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.Setting.Auth.Token.Secret))
+}
+
+// parseHeader 解析请求头中的token
+func (s *TokenService) parseHeader(ctx *gin.Context, validate bool) (*TokenService, error) {
 	authorization := ctx.GetHeader(s.Setting.Auth.Token.Header)
 	if authorization == "" {
-		return "", errors.New("请先登录")
+		return nil, errors.New("请先登录")
 	}
 	tokenSplit := strings.Split(authorization, " ")
 	if len(tokenSplit) != 2 || tokenSplit[0] != "Bearer" {
-		return "", errors.New("authorization format error")
+		return nil, errors.New("authorization format error")
 	}
-	token, err := jwt.ParseWithClaims(tokenSplit[1], s, func(token *jwt.Token) (interface{}, error) {
+	return s.parse(tokenSplit[1], validate)
+}
+
+// parse 校验token签名并解析声明，validate为false时不校验过期及生效时间
+func (s *TokenService) parse(tokenString string, validate bool) (*TokenService, error) {
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})}
+	if !validate {
+		options = append(options, jwt.WithoutClaimsValidation())
+	}
+	claims := &TokenService{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(s.Setting.Auth.Token.Secret), nil
-	})
+	}, options...)
 	if err != nil {
 		var ve *jwt.ValidationError
 		if errors.As(err, &ve) {
 			if ve.Errors&jwt.ValidationErrorMalformed != 0 {
-				return "", errors.New("token格式错误")
+				return nil, errors.New("token格式错误")
+			}
+			if ve.Errors&jwt.ValidationErrorExpired != 0 {
+				return nil, errors.New("token已过期")
 			}
 			if ve.Errors&jwt.ValidationErrorNotValidYet != 0 {
-				return "", errors.New("token未生效")
+				return nil, errors.New("token未生效")
 			}
-			return "", errors.New("token校验失败")
+			return nil, errors.New("token校验失败")
 		}
-		return "", err
+		return nil, err
 	}
-	if claims, ok := token.Claims.(*TokenService); ok && token.Valid {
-		return redis_key.UserTokenKey + claims.Key, nil
+	if !token.Valid || claims.Key == "" {
+		return nil, errors.New("token校验失败")
 	}
-	return "", errors.New("token校验失败")
+	return claims, nil
 }
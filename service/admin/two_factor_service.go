@@ -0,0 +1,355 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/hugo8680/goat/common/constant/redis_key"
+	"github.com/hugo8680/goat/common/totp"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/common/uuid"
+	"github.com/hugo8680/goat/framework/config"
+	"github.com/hugo8680/goat/framework/connector"
+	"github.com/hugo8680/goat/framework/logger"
+	"github.com/hugo8680/goat/model"
+	"github.com/hugo8680/goat/model/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// 两步验证登录凭证有效期
+	twoFactorTicketExpire = time.Minute * 5
+	// 两步验证登录凭证允许的验证码错误次数，超过后凭证失效需重新登录
+	twoFactorTicketMaxRetry = 5
+	// 待绑定密钥有效期
+	twoFactorSetupExpire = time.Minute * 10
+	// 恢复码数量
+	twoFactorRecoveryCodeCount = 10
+)
+
+// twoFactorTicket 两步验证登录凭证，密码校验通过后写入缓存，验证码校验通过后换取令牌
+type twoFactorTicket struct {
+	User  dto.UserTokenResponse `json:"user"`
+	Setup bool                  `json:"setup"` // 角色要求两步验证但尚未绑定，需先绑定
+	Retry int                   `json:"retry"`
+}
+
+// MarshalBinary 序列化twoFactorTicket，实现redis读写
+func (t twoFactorTicket) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// UnmarshalBinary 反序列化twoFactorTicket，实现redis读写
+func (t *twoFactorTicket) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, t)
+}
+
+// TwoFactorService 基于TOTP（RFC 6238）的两步验证
+//
+// 密钥绑定后登录分为两步：密码校验通过后返回登录凭证，凭证加验证码或恢复码换取令牌
+type TwoFactorService struct {
+}
+
+// Status 获取用户两步验证状态
+func (s *TwoFactorService) Status(ctx context.Context, userId int) dto.TwoFactorStatusResponse {
+	user := s.get(ctx, userId)
+	return dto.TwoFactorStatusResponse{
+		Enabled:       user.TotpEnabled == "1",
+		Forced:        s.IsForced(ctx, userId),
+		RecoveryCodes: len(s.splitRecoveryCodes(user.RecoveryCodes)),
+	}
+}
+
+// IsEnabled 用户是否已启用两步验证
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userId int) bool {
+	return s.get(ctx, userId).TotpEnabled == "1"
+}
+
+// IsForced 用户的角色是否要求启用两步验证，角色由参数sys.account.twoFactorRoles配置
+func (s *TwoFactorService) IsForced(ctx context.Context, userId int) bool {
+	conf := (&ConfigService{}).GetCacheByConfigKey(ctx, "sys.account.twoFactorRoles")
+	if strings.TrimSpace(conf.ConfigValue) == "" {
+		return false
+	}
+	roleKeys := (&UserService{}).ListRoleKeys(ctx, userId)
+	for _, roleKey := range strings.Split(conf.ConfigValue, ",") {
+		if utils.Contains(roleKeys, strings.TrimSpace(roleKey)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Setup 生成待绑定的密钥，验证码校验通过后才会保存到用户
+func (s *TwoFactorService) Setup(ctx context.Context, userId int) (dto.TwoFactorSetupResponse, error) {
+	user := s.get(ctx, userId)
+	if user.UserId <= 0 {
+		return dto.TwoFactorSetupResponse{}, errors.New("用户不存在")
+	}
+	if user.TotpEnabled == "1" {
+		return dto.TwoFactorSetupResponse{}, errors.New("已启用两步验证")
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+	if err = connector.GetCache().Set(ctx, redis_key.TwoFactorSetupKey+strconv.Itoa(userId), secret, twoFactorSetupExpire).Err(); err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+	uri := totp.URI(config.GetSetting().System.Name, user.UserName, secret)
+	qrCode, err := totp.QRCode(uri)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+	return dto.TwoFactorSetupResponse{
+		Secret: secret,
+		Uri:    uri,
+		QrCode: base64.StdEncoding.EncodeToString(qrCode),
+	}, nil
+}
+
+// Enable 校验待绑定密钥的验证码，通过后启用两步验证并返回恢复码，恢复码仅此时明文返回
+func (s *TwoFactorService) Enable(ctx context.Context, userId int, code string) ([]string, error) {
+	cache := connector.GetCache()
+	secret, err := cache.Get(ctx, redis_key.TwoFactorSetupKey+strconv.Itoa(userId)).Result()
+	if err != nil || secret == "" {
+		return nil, errors.New("绑定信息已过期，请重新获取")
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok || !s.markUsed(ctx, userId, step) {
+		return nil, errors.New("验证码错误")
+	}
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = connector.DB(ctx).Model(model.SysUser{}).Where("user_id = ?", userId).
+		Select("totp_secret", "totp_enabled", "recovery_codes").
+		Updates(&model.SysUser{
+			TotpSecret:    secret,
+			TotpEnabled:   "1",
+			RecoveryCodes: hashes,
+		}).Error; err != nil {
+		return nil, err
+	}
+	cache.Del(ctx, redis_key.TwoFactorSetupKey+strconv.Itoa(userId))
+	logger.Module("2fa").InfoContext(ctx, "two factor enabled", "user_id", userId)
+	return codes, nil
+}
+
+// Disable 校验验证码或恢复码后停用两步验证，角色要求启用时不允许停用
+func (s *TwoFactorService) Disable(ctx context.Context, userId int, code, recoveryCode string) error {
+	if s.IsForced(ctx, userId) {
+		return errors.New("当前角色要求启用两步验证，不允许停用")
+	}
+	if err := s.Verify(ctx, userId, code, recoveryCode); err != nil {
+		return err
+	}
+	return s.clear(ctx, userId)
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，原恢复码全部失效
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId int, code string) ([]string, error) {
+	user := s.get(ctx, userId)
+	if user.TotpEnabled != "1" {
+		return nil, errors.New("未启用两步验证")
+	}
+	step, ok := totp.Validate(user.TotpSecret, code, time.Now())
+	if !ok || !s.markUsed(ctx, userId, step) {
+		return nil, errors.New("验证码错误")
+	}
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = connector.DB(ctx).Model(model.SysUser{}).Where("user_id = ?", userId).
+		Update("recovery_codes", hashes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset 管理员重置用户的两步验证，用户丢失设备及恢复码时使用
+func (s *TwoFactorService) Reset(ctx context.Context, userId int, updateBy string) error {
+	if user := s.get(ctx, userId); user.UserId <= 0 {
+		return errors.New("用户不存在")
+	}
+	if err := s.clear(ctx, userId); err != nil {
+		return err
+	}
+	logger.Module("2fa").InfoContext(ctx, "two factor reset", "user_id", userId, "update_by", updateBy)
+	return nil
+}
+
+// Verify 校验验证码或恢复码，恢复码使用后即失效
+//
+// 同一时间步的验证码只能使用一次，防止验证码被截获后重放
+func (s *TwoFactorService) Verify(ctx context.Context, userId int, code, recoveryCode string) error {
+	user := s.get(ctx, userId)
+	if user.TotpEnabled != "1" {
+		return errors.New("未启用两步验证")
+	}
+	if recoveryCode != "" {
+		return s.useRecoveryCode(ctx, user, recoveryCode)
+	}
+	step, ok := totp.Validate(user.TotpSecret, code, time.Now())
+	if !ok || !s.markUsed(ctx, userId, step) {
+		return errors.New("验证码错误")
+	}
+	return nil
+}
+
+// CreateTicket 密码校验通过后生成两步验证登录凭证
+func (s *TwoFactorService) CreateTicket(ctx context.Context, user dto.UserTokenResponse, setup bool) (string, error) {
+	ticket, err := uuid.CreateId()
+	if err != nil {
+		return "", err
+	}
+	if err = connector.GetCache().Set(ctx, redis_key.TwoFactorTicketKey+ticket, twoFactorTicket{
+		User:  user,
+		Setup: setup,
+	}, twoFactorTicketExpire).Err(); err != nil {
+		return "", err
+	}
+	return ticket, nil
+}
+
+// SetupByTicket 角色要求两步验证但尚未绑定时，使用登录凭证获取绑定信息
+func (s *TwoFactorService) SetupByTicket(ctx context.Context, ticket string) (dto.TwoFactorSetupResponse, error) {
+	data, err := s.getTicket(ctx, ticket)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+	if !data.Setup {
+		return dto.TwoFactorSetupResponse{}, errors.New("已启用两步验证")
+	}
+	return s.Setup(ctx, data.User.UserId)
+}
+
+// VerifyTicket 校验登录凭证及验证码，通过后凭证失效并返回登录用户
+//
+// 凭证需要绑定时校验待绑定密钥并启用两步验证，同时返回恢复码
+func (s *TwoFactorService) VerifyTicket(ctx context.Context, ticket, code, recoveryCode string) (dto.UserTokenResponse, []string, error) {
+	data, err := s.getTicket(ctx, ticket)
+	if err != nil {
+		return dto.UserTokenResponse{}, nil, err
+	}
+	var recoveryCodes []string
+	if data.Setup {
+		recoveryCodes, err = s.Enable(ctx, data.User.UserId, code)
+	} else {
+		err = s.Verify(ctx, data.User.UserId, code, recoveryCode)
+	}
+	if err != nil {
+		s.retryTicket(ctx, ticket, data)
+		return data.User, nil, err
+	}
+	// 凭证只能使用一次，并发请求时仅删除成功的一方登录
+	if connector.GetCache().Del(ctx, redis_key.TwoFactorTicketKey+ticket).Val() == 0 {
+		return data.User, nil, errors.New("登录凭证已过期，请重新登录")
+	}
+	return data.User, recoveryCodes, nil
+}
+
+func (s *TwoFactorService) getTicket(ctx context.Context, ticket string) (twoFactorTicket, error) {
+	var data twoFactorTicket
+	if ticket == "" {
+		return data, errors.New("登录凭证不能为空")
+	}
+	if err := connector.GetCache().Get(ctx, redis_key.TwoFactorTicketKey+ticket).Scan(&data); err != nil {
+		return data, errors.New("登录凭证已过期，请重新登录")
+	}
+	return data, nil
+}
+
+// retryTicket 验证码错误次数加1，超过限制后删除凭证，保留凭证原有效期
+func (s *TwoFactorService) retryTicket(ctx context.Context, ticket string, data twoFactorTicket) {
+	cache := connector.GetCache()
+	data.Retry++
+	if data.Retry >= twoFactorTicketMaxRetry {
+		cache.Del(ctx, redis_key.TwoFactorTicketKey+ticket)
+		return
+	}
+	if ttl := cache.TTL(ctx, redis_key.TwoFactorTicketKey+ticket).Val(); ttl > 0 {
+		cache.Set(ctx, redis_key.TwoFactorTicketKey+ticket, data, ttl)
+	}
+}
+
+// markUsed 记录已使用的时间步，返回false表示该时间步的验证码已被使用
+func (s *TwoFactorService) markUsed(ctx context.Context, userId int, step int64) bool {
+	key := redis_key.TwoFactorUsedKey + strconv.Itoa(userId) + ":" + strconv.FormatInt(step, 10)
+	ok, err := connector.GetCache().SetNX(ctx, key, 1, time.Second*totp.Period*(totp.Skew*2+1)).Result()
+	if err != nil {
+		logger.Module("2fa").WarnContext(ctx, "mark totp step used", "user_id", userId, "error", err)
+		return true
+	}
+	return ok
+}
+
+// useRecoveryCode 校验恢复码并从用户的恢复码中移除
+func (s *TwoFactorService) useRecoveryCode(ctx context.Context, user model.SysUser, recoveryCode string) error {
+	hash := totp.HashRecoveryCode(recoveryCode)
+	hashes := s.splitRecoveryCodes(user.RecoveryCodes)
+	if !utils.Contains(hashes, hash) {
+		return errors.New("恢复码错误")
+	}
+	remain := utils.Filter(hashes, func(item string) bool {
+		return item != hash
+	})
+	// 以原恢复码为条件更新，并发使用同一恢复码时仅一次成功
+	result := connector.DB(ctx).Model(model.SysUser{}).
+		Where("user_id = ? AND recovery_codes = ?", user.UserId, user.RecoveryCodes).
+		Update("recovery_codes", strings.Join(remain, ","))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("恢复码错误")
+	}
+	logger.Module("2fa").InfoContext(ctx, "recovery code used", "user_id", user.UserId, "remain", len(remain))
+	return nil
+}
+
+// generateRecoveryCodes 生成恢复码，返回明文及逗号分隔的摘要
+func (s *TwoFactorService) generateRecoveryCodes() ([]string, string, error) {
+	codes, err := totp.GenerateRecoveryCodes(twoFactorRecoveryCodeCount)
+	if err != nil {
+		return nil, "", err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, totp.HashRecoveryCode(code))
+	}
+	return codes, strings.Join(hashes, ","), nil
+}
+
+func (s *TwoFactorService) splitRecoveryCodes(recoveryCodes string) []string {
+	if recoveryCodes == "" {
+		return []string{}
+	}
+	return strings.Split(recoveryCodes, ",")
+}
+
+// clear 清除用户的两步验证密钥及恢复码
+func (s *TwoFactorService) clear(ctx context.Context, userId int) error {
+	if err := connector.DB(ctx).Model(model.SysUser{}).Where("user_id = ?", userId).
+		Select("totp_secret", "totp_enabled", "recovery_codes").
+		Updates(&model.SysUser{
+			TotpSecret:    "",
+			TotpEnabled:   "0",
+			RecoveryCodes: "",
+		}).Error; err != nil {
+		return err
+	}
+	connector.GetCache().Del(ctx, redis_key.TwoFactorSetupKey+strconv.Itoa(userId))
+	return nil
+}
+
+func (s *TwoFactorService) get(ctx context.Context, userId int) model.SysUser {
+	var user model.SysUser
+	connector.DB(ctx).Model(model.SysUser{}).Where("user_id = ?", userId).Last(&user)
+	return user
+}
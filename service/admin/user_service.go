@@ -1,50 +1,65 @@
 package admin
 
 import (
+	"context"
 	"errors"
+	"github.com/hugo8680/goat/common/serializer/datetime"
+	"github.com/hugo8680/goat/common/utils"
+	"github.com/hugo8680/goat/framework/config"
 	"github.com/hugo8680/goat/framework/connector"
 	"github.com/hugo8680/goat/model"
 	"github.com/hugo8680/goat/model/dto"
+	"time"
 )
 
 type UserService struct {
 }
 
 // Create 新增用户
-func (s *UserService) Create(param dto.SaveUserRequest, roleIds, postIds []int) error {
-	if user := s.GetByUserName(param.UserName); user.UserId > 0 {
+func (s *UserService) Create(ctx context.Context, param dto.SaveUserRequest, roleIds, postIds []int) error {
+	if user := s.GetByUserName(ctx, param.UserName); user.UserId > 0 {
 		return errors.New("新增用户" + param.UserName + "失败，用户名已存在")
 	}
 	if param.Email != "" {
 		return errors.New("新增用户" + param.UserName + "失败，邮箱已存在")
 	}
 	if param.PhoneNumber != "" {
-		if user := s.GetByPhoneNumber(param.PhoneNumber); user.UserId > 0 {
+		if user := s.GetByPhoneNumber(ctx, param.PhoneNumber); user.UserId > 0 {
 			return errors.New("新增用户" + param.UserName + "失败，手机号已存在")
 		}
 	}
-	db := connector.GetDB()
+	db := connector.DB(ctx)
 	tx := db.Begin()
 	user := model.SysUser{
-		DeptId:      param.DeptId,
-		UserName:    param.UserName,
-		NickName:    param.NickName,
-		UserType:    param.UserType,
-		Email:       param.Email,
-		PhoneNumber: param.PhoneNumber,
-		Sex:         param.Sex,
-		Avatar:      param.Avatar,
-		Password:    param.Password,
-		LoginIP:     param.LoginIP,
-		LoginDate:   param.LoginDate,
-		Status:      param.Status,
-		CreateBy:    param.CreateBy,
-		Remark:      param.Remark,
+		DeptId:        param.DeptId,
+		UserName:      param.UserName,
+		NickName:      param.NickName,
+		UserType:      param.UserType,
+		Email:         param.Email,
+		PhoneNumber:   param.PhoneNumber,
+		Sex:           param.Sex,
+		Avatar:        param.Avatar,
+		Password:      param.Password,
+		LoginIP:       param.LoginIP,
+		LoginDate:     param.LoginDate,
+		Status:        param.Status,
+		PwdMustChange: param.PwdMustChange,
+		CreateBy:      param.CreateBy,
+		Remark:        param.Remark,
+	}
+	if param.Password != "" {
+		user.PwdUpdateTime = datetime.Datetime{Time: time.Now()}
 	}
 	if err := tx.Model(model.SysUser{}).Create(&user).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
+	if param.Password != "" {
+		if err := (&PasswordPolicyService{}).Record(tx, user.UserId, param.Password); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 	if len(roleIds) > 0 {
 		for _, roleId := range roleIds {
 			if err := tx.Model(model.SysUserRole{}).Create(&model.SysUserRole{
@@ -71,37 +86,58 @@ func (s *UserService) Create(param dto.SaveUserRequest, roleIds, postIds []int)
 }
 
 // Update 更新用户
-func (s *UserService) Update(param dto.SaveUserRequest, roleIds, postIds []int) error {
+func (s *UserService) Update(ctx context.Context, param dto.SaveUserRequest, roleIds, postIds []int) error {
 	if param.Email != "" {
-		if user := s.GetByEmail(param.Email); user.UserId > 0 && user.UserId != param.UserId {
+		if user := s.GetByEmail(ctx, param.Email); user.UserId > 0 && user.UserId != param.UserId {
 			return errors.New("修改用户" + param.UserName + "失败，邮箱已存在")
 		}
 	}
 	if param.PhoneNumber != "" {
-		if user := s.GetByPhoneNumber(param.PhoneNumber); user.UserId > 0 && user.UserId != param.UserId {
+		if user := s.GetByPhoneNumber(ctx, param.PhoneNumber); user.UserId > 0 && user.UserId != param.UserId {
 			return errors.New("修改用户" + param.UserName + "失败，手机号已存在")
 		}
 	}
-	db := connector.GetDB()
+	if param.Status != "" && param.Status != "0" {
+		if err := s.checkLastSuperAdmin(ctx, []int{param.UserId}, "停用"); err != nil {
+			return err
+		}
+	}
+	if roleIds != nil && !s.keepsSuperAdmin(ctx, param.UserId, roleIds) {
+		if err := s.checkLastSuperAdmin(ctx, []int{param.UserId}, "取消授权"); err != nil {
+			return err
+		}
+	}
+	user := model.SysUser{
+		DeptId:        param.DeptId,
+		NickName:      param.NickName,
+		UserType:      param.UserType,
+		Email:         param.Email,
+		PhoneNumber:   param.PhoneNumber,
+		Sex:           param.Sex,
+		Avatar:        param.Avatar,
+		Password:      param.Password,
+		LoginIP:       param.LoginIP,
+		LoginDate:     param.LoginDate,
+		Status:        param.Status,
+		PwdMustChange: param.PwdMustChange,
+		UpdateBy:      param.UpdateBy,
+		Remark:        param.Remark,
+	}
+	if param.Password != "" {
+		user.PwdUpdateTime = datetime.Datetime{Time: time.Now()}
+	}
+	db := connector.DB(ctx)
 	tx := db.Begin()
-	if err := tx.Model(model.SysUser{}).Where("user_id = ?", param.UserId).Updates(&model.SysUser{
-		DeptId:      param.DeptId,
-		NickName:    param.NickName,
-		UserType:    param.UserType,
-		Email:       param.Email,
-		PhoneNumber: param.PhoneNumber,
-		Sex:         param.Sex,
-		Avatar:      param.Avatar,
-		Password:    param.Password,
-		LoginIP:     param.LoginIP,
-		LoginDate:   param.LoginDate,
-		Status:      param.Status,
-		UpdateBy:    param.UpdateBy,
-		Remark:      param.Remark,
-	}).Error; err != nil {
+	if err := tx.Model(model.SysUser{}).Where("user_id = ?", param.UserId).Updates(&user).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
+	if param.Password != "" {
+		if err := (&PasswordPolicyService{}).Record(tx, param.UserId, param.Password); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 	if roleIds != nil {
 		if err := tx.Model(model.SysUserRole{}).Where("user_id = ?", param.UserId).Delete(&model.SysUserRole{}).Error; err != nil {
 			tx.Rollback()
@@ -136,12 +172,42 @@ func (s *UserService) Update(param dto.SaveUserRequest, roleIds, postIds []int)
 			}
 		}
 	}
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	if roleIds != nil {
+		(&PermissionService{}).Evict(ctx, param.UserId)
+	}
+	// 停用用户时撤销其全部登录会话
+	if param.Status != "" && param.Status != "0" {
+		return NewTokenService().RevokeUser(ctx, param.UserId)
+	}
+	return nil
+}
+
+// ResetPassword 重置用户密码，并撤销其全部登录会话，按密码策略要求用户登录后修改密码
+func (s *UserService) ResetPassword(ctx context.Context, userId int, password, updateBy string) error {
+	pwdMustChange := "0"
+	if (&PasswordPolicyService{}).MustChangeInitial(ctx) {
+		pwdMustChange = "1"
+	}
+	if err := s.Update(ctx, dto.SaveUserRequest{
+		UserId:        userId,
+		Password:      password,
+		PwdMustChange: pwdMustChange,
+		UpdateBy:      updateBy,
+	}, nil, nil); err != nil {
+		return err
+	}
+	return NewTokenService().RevokeUser(ctx, userId)
 }
 
 // Delete 删除用户
-func (s *UserService) Delete(userIds []int) error {
-	tx := connector.GetDB().Begin()
+func (s *UserService) Delete(ctx context.Context, userIds []int) error {
+	if err := s.checkLastSuperAdmin(ctx, userIds, "删除"); err != nil {
+		return err
+	}
+	tx := connector.DB(ctx).Begin()
 	if err := tx.Model(model.SysUser{}).Where("user_id IN ?", userIds).Delete(&model.SysUser{}).Error; err != nil {
 		tx.Rollback()
 		return err
@@ -154,12 +220,21 @@ func (s *UserService) Delete(userIds []int) error {
 		tx.Rollback()
 		return err
 	}
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	(&PermissionService{}).Evict(ctx, userIds...)
+	return NewTokenService().RevokeUser(ctx, userIds...)
 }
 
 // AuthRoles 用户授权角色
-func (s *UserService) AuthRoles(userId int, roleIds []int) error {
-	db := connector.GetDB()
+func (s *UserService) AuthRoles(ctx context.Context, userId int, roleIds []int) error {
+	if !s.keepsSuperAdmin(ctx, userId, roleIds) {
+		if err := s.checkLastSuperAdmin(ctx, []int{userId}, "取消授权"); err != nil {
+			return err
+		}
+	}
+	db := connector.DB(ctx)
 	tx := db.Begin()
 	// 清理用户角色
 	if err := tx.Model(model.SysUserRole{}).Where("user_id = ?", userId).Delete(&model.SysUserRole{}).Error; err != nil {
@@ -178,17 +253,21 @@ func (s *UserService) AuthRoles(userId int, roleIds []int) error {
 			}
 		}
 	}
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	(&PermissionService{}).Evict(ctx, userId)
+	return nil
 }
 
 // List 获取用户列表
-func (s *UserService) List(param dto.UserListRequest, userId int, isPaging bool) ([]dto.UserListResponse, int) {
+func (s *UserService) List(ctx context.Context, param dto.UserListRequest, userId int, isPaging bool) ([]dto.UserListResponse, int) {
 	var count int64
 	users := make([]dto.UserListResponse, 0)
-	query := connector.GetDB().Model(model.SysUser{}).
+	query := connector.DB(ctx).Model(model.SysUser{}).
 		Select("sys_user.*", "sys_dept.dept_name", "sys_dept.leader").
 		Joins("LEFT JOIN sys_dept ON sys_user.dept_id = sys_dept.dept_id").
-		Scopes((&DataScopeService{}).GetDataScope("sys_dept", userId, "sys_user"))
+		Scopes((&DataScopeService{}).GetDataScope(ctx, "sys_dept", userId, "sys_user"))
 	if param.UserName != "" {
 		query = query.Where("sys_user.user_name LIKE ?", "%"+param.UserName+"%")
 	}
@@ -212,16 +291,16 @@ func (s *UserService) List(param dto.UserListRequest, userId int, isPaging bool)
 }
 
 // Get 根据用户id查询用户信息
-func (s *UserService) Get(userId int) dto.UserDetailResponse {
+func (s *UserService) Get(ctx context.Context, userId int) dto.UserDetailResponse {
 	var user dto.UserDetailResponse
-	connector.GetDB().Model(model.SysUser{}).Where("user_id = ?", userId).Last(&user)
+	connector.DB(ctx).Model(model.SysUser{}).Where("user_id = ?", userId).Last(&user)
 	return user
 }
 
 // 根据用户名查询用户信息
-func (s *UserService) GetByUserName(userName string) dto.UserTokenResponse {
+func (s *UserService) GetByUserName(ctx context.Context, userName string) dto.UserTokenResponse {
 	var user dto.UserTokenResponse
-	connector.GetDB().Model(model.SysUser{}).
+	connector.DB(ctx).Model(model.SysUser{}).
 		Select(
 			"sys_user.user_id",
 			"sys_user.dept_id",
@@ -239,9 +318,9 @@ func (s *UserService) GetByUserName(userName string) dto.UserTokenResponse {
 }
 
 // GetByEmail 根据邮箱查询用户信息
-func (s *UserService) GetByEmail(email string) dto.UserTokenResponse {
+func (s *UserService) GetByEmail(ctx context.Context, email string) dto.UserTokenResponse {
 	var user dto.UserTokenResponse
-	connector.GetDB().Model(model.SysUser{}).
+	connector.DB(ctx).Model(model.SysUser{}).
 		Select(
 			"sys_user.user_id",
 			"sys_user.dept_id",
@@ -259,9 +338,9 @@ func (s *UserService) GetByEmail(email string) dto.UserTokenResponse {
 }
 
 // GetByPhoneNumber 根据手机号码查询用户信息
-func (s *UserService) GetByPhoneNumber(phoneNumber string) dto.UserTokenResponse {
+func (s *UserService) GetByPhoneNumber(ctx context.Context, phoneNumber string) dto.UserTokenResponse {
 	var user dto.UserTokenResponse
-	connector.GetDB().Model(model.SysUser{}).
+	connector.DB(ctx).Model(model.SysUser{}).
 		Select(
 			"sys_user.user_id",
 			"sys_user.dept_id",
@@ -281,13 +360,13 @@ func (s *UserService) GetByPhoneNumber(phoneNumber string) dto.UserTokenResponse
 // ListByRoleId 根据角色id查询已分配角色的用户列表
 //
 // isAllocation：true-已分配；false-未分配
-func (s *UserService) ListByRoleId(param dto.RoleAuthUserAllocatedListRequest, userId int, isAllocation bool) ([]dto.UserListResponse, int) {
+func (s *UserService) ListByRoleId(ctx context.Context, param dto.RoleAuthUserAllocatedListRequest, userId int, isAllocation bool) ([]dto.UserListResponse, int) {
 	var count int64
 	users := make([]dto.UserListResponse, 0)
-	query := connector.GetDB().Model(model.SysUser{}).
+	query := connector.DB(ctx).Model(model.SysUser{}).
 		Select("sys_user.*", "sys_dept.dept_name", "sys_dept.leader").
 		Joins("LEFT JOIN sys_dept ON sys_user.dept_id = sys_dept.dept_id").
-		Scopes((&DataScopeService{}).GetDataScope("sys_dept", userId, "sys_user"))
+		Scopes((&DataScopeService{}).GetDataScope(ctx, "sys_dept", userId, "sys_user"))
 	if isAllocation {
 		query.Joins("JOIN sys_user_role ON sys_user_role.user_id = sys_user.user_id").
 			Where("sys_user_role.role_id = ?", param.RoleId)
@@ -306,9 +385,9 @@ func (s *UserService) ListByRoleId(param dto.RoleAuthUserAllocatedListRequest, u
 }
 
 // HasPerms 查询用户是否拥有某权限，拥有返回true
-func (s *UserService) HasPerms(userId int, perms []string) bool {
+func (s *UserService) HasPerms(ctx context.Context, userId int, perms []string) bool {
 	var count int64
-	connector.GetDB().Model(model.SysUserRole{}).
+	connector.DB(ctx).Model(model.SysUserRole{}).
 		Joins("JOIN sys_role ON sys_user_role.role_id = sys_role.role_id AND sys_role.status = 0").
 		Joins("JOIN sys_role_menu ON sys_role_menu.role_id = sys_role.role_id").
 		Joins("JOIN sys_menu ON sys_menu.menu_id = sys_role_menu.menu_id AND sys_menu.status = 0").
@@ -318,10 +397,98 @@ func (s *UserService) HasPerms(userId int, perms []string) bool {
 	return count > 0
 }
 
+// ListPerms 查询用户拥有的权限标识，仅包含状态正常的角色及菜单
+func (s *UserService) ListPerms(ctx context.Context, userId int) []string {
+	perms, _ := s.listPerms(ctx, userId)
+	return perms
+}
+
+// ListRoleKeys 查询用户拥有的角色key，仅包含状态正常的角色
+func (s *UserService) ListRoleKeys(ctx context.Context, userId int) []string {
+	roleKeys, _ := s.listRoleKeys(ctx, userId)
+	return roleKeys
+}
+
+// listPerms 查询用户的权限标识，返回查询错误
+func (s *UserService) listPerms(ctx context.Context, userId int) ([]string, error) {
+	perms := make([]string, 0)
+	err := connector.DB(ctx).Model(model.SysUserRole{}).
+		Joins("JOIN sys_role ON sys_user_role.role_id = sys_role.role_id AND sys_role.status = 0").
+		Joins("JOIN sys_role_menu ON sys_role_menu.role_id = sys_role.role_id").
+		Joins("JOIN sys_menu ON sys_menu.menu_id = sys_role_menu.menu_id AND sys_menu.status = 0").
+		Where("sys_role.delete_time IS NULL AND sys_menu.delete_time IS NULL").
+		Where("sys_user_role.user_id = ? AND sys_menu.perms != ''", userId).
+		Distinct().
+		Pluck("sys_menu.perms", &perms).Error
+	return perms, err
+}
+
+// listRoleKeys 查询用户的角色key，返回查询错误
+func (s *UserService) listRoleKeys(ctx context.Context, userId int) ([]string, error) {
+	roleKeys := make([]string, 0)
+	err := connector.DB(ctx).Model(model.SysUserRole{}).
+		Joins("JOIN sys_role ON sys_user_role.role_id = sys_role.role_id AND sys_role.status = 0").
+		Where("sys_role.delete_time IS NULL").
+		Where("sys_user_role.user_id = ?", userId).
+		Pluck("sys_role.role_key", &roleKeys).Error
+	return roleKeys, err
+}
+
+// IsSuperAdmin 查询用户是否为超级管理员，不经过权限缓存
+func (s *UserService) IsSuperAdmin(ctx context.Context, userId int) bool {
+	user := s.Get(ctx, userId)
+	if user.UserId <= 0 {
+		return false
+	}
+	return (&SecurityService{}).IsSuperAdminUser(user.UserName, s.ListRoleKeys(ctx, userId))
+}
+
+// ListSuperAdminIds 查询状态正常的超级管理员用户id
+func (s *UserService) ListSuperAdminIds(ctx context.Context) []int {
+	userIds := make([]int, 0)
+	connector.DB(ctx).Model(model.SysUser{}).
+		Joins("LEFT JOIN sys_user_role ON sys_user_role.user_id = sys_user.user_id").
+		Joins("LEFT JOIN sys_role ON sys_role.role_id = sys_user_role.role_id AND sys_role.status = 0 AND sys_role.delete_time IS NULL").
+		Where("sys_user.status = 0").
+		Where("sys_role.role_key IN ? OR sys_user.user_name IN ?", (&SecurityService{}).SuperAdminRoles(), config.GetSetting().Auth.SuperAdmin.Users).
+		Distinct().
+		Pluck("sys_user.user_id", &userIds)
+	return userIds
+}
+
+// keepsSuperAdmin 用户授权为roleIds后是否仍为超级管理员
+func (s *UserService) keepsSuperAdmin(ctx context.Context, userId int, roleIds []int) bool {
+	if utils.Contains(config.GetSetting().Auth.SuperAdmin.Users, s.Get(ctx, userId).UserName) {
+		return true
+	}
+	if len(roleIds) == 0 {
+		return false
+	}
+	var count int64
+	connector.DB(ctx).Model(model.SysRole{}).
+		Where("role_id IN ? AND role_key IN ? AND status = 0", roleIds, (&SecurityService{}).SuperAdminRoles()).
+		Count(&count)
+	return count > 0
+}
+
+// checkLastSuperAdmin 用户将失去超级管理员身份时，校验是否还有其他可用的超级管理员
+func (s *UserService) checkLastSuperAdmin(ctx context.Context, userIds []int, action string) error {
+	superAdminIds := s.ListSuperAdminIds(ctx)
+	if len(superAdminIds) == 0 {
+		return nil
+	}
+	for _, superAdminId := range superAdminIds {
+		if !utils.Contains(userIds, superAdminId) {
+			return nil
+		}
+	}
+	return errors.New("不能" + action + "最后一个超级管理员")
+}
+
 // HasRoles 查询用户是否拥有某角色，拥有返回true
-func (s *UserService) HasRoles(userId int, roles []string) bool {
+func (s *UserService) HasRoles(ctx context.Context, userId int, roles []string) bool {
 	var count int64
-	connector.GetDB().Model(model.SysUserRole{}).
+	connector.DB(ctx).Model(model.SysUserRole{}).
 		Joins("JOIN sys_role ON sys_user_role.role_id = sys_role.role_id AND sys_role.status = 0").
 		Where("sys_role.delete_time IS NULL").
 		Where("sys_user_role.user_id = ? AND sys_role.role_key IN ?", userId, roles).